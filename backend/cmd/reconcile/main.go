@@ -0,0 +1,111 @@
+// Command reconcile verifies user_points balances against the double-entry
+// ledger and checks that the ledger itself balances to zero.
+//
+// Usage:
+//
+//	go run ./cmd/reconcile [-repair] [-json]
+//
+// It exits with status 1 when mismatches remain unresolved.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/infrastructure"
+)
+
+type mismatchOutput struct {
+	UserID          int  `json:"user_id"`
+	RecordedBalance int  `json:"recorded_balance"`
+	LedgerBalance   int  `json:"ledger_balance"`
+	Difference      int  `json:"difference"`
+	Repaired        bool `json:"repaired"`
+}
+
+type reportOutput struct {
+	CheckedUsers           int              `json:"checked_users"`
+	Mismatches             []mismatchOutput `json:"mismatches"`
+	LedgerTotal            int              `json:"ledger_total"`
+	UnbalancedTransactions []int            `json:"unbalanced_transactions"`
+	Unresolved             int              `json:"unresolved"`
+	DurationMs             int64            `json:"duration_ms"`
+}
+
+func main() {
+	repair := flag.Bool("repair", false, "write adjustment transactions for mismatched users")
+	asJSON := flag.Bool("json", false, "print the report as JSON")
+	flag.Parse()
+
+	container, err := infrastructure.NewContainer(infrastructure.LoadConfig())
+	if err != nil {
+		log.Fatalf("Failed to initialize container: %v", err)
+	}
+	defer container.Close()
+
+	report, err := container.ReconcileUsecase.Reconcile(context.Background(), *repair)
+	if err != nil {
+		log.Fatalf("Reconciliation failed: %v", err)
+	}
+
+	if *asJSON {
+		printJSON(report)
+	} else {
+		printText(report)
+	}
+
+	if report.Unresolved() > 0 {
+		container.Close()
+		os.Exit(1)
+	}
+}
+
+func printJSON(report *model.ReconciliationReport) {
+	output := reportOutput{
+		CheckedUsers:           report.CheckedUsers,
+		Mismatches:             make([]mismatchOutput, 0, len(report.Mismatches)),
+		LedgerTotal:            report.LedgerTotal,
+		UnbalancedTransactions: append([]int{}, report.UnbalancedTransactions...),
+		Unresolved:             report.Unresolved(),
+		DurationMs:             report.FinishedAt.Sub(report.StartedAt).Milliseconds(),
+	}
+	for _, mismatch := range report.Mismatches {
+		output.Mismatches = append(output.Mismatches, mismatchOutput{
+			UserID:          mismatch.UserID,
+			RecordedBalance: mismatch.RecordedBalance,
+			LedgerBalance:   mismatch.LedgerBalance,
+			Difference:      mismatch.Difference(),
+			Repaired:        mismatch.Repaired,
+		})
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(output); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+}
+
+func printText(report *model.ReconciliationReport) {
+	for _, mismatch := range report.Mismatches {
+		status := "MISMATCH"
+		if mismatch.Repaired {
+			status = "REPAIRED"
+		}
+		fmt.Printf("%s user=%d recorded=%d ledger=%d difference=%+d\n",
+			status, mismatch.UserID, mismatch.RecordedBalance, mismatch.LedgerBalance, mismatch.Difference())
+	}
+	for _, transactionID := range report.UnbalancedTransactions {
+		fmt.Printf("UNBALANCED transaction=%d\n", transactionID)
+	}
+	if report.LedgerTotal != 0 {
+		fmt.Printf("LEDGER total=%d (expected 0)\n", report.LedgerTotal)
+	}
+	fmt.Printf("Checked %d users, %d mismatches, %d unresolved (%s)\n",
+		report.CheckedUsers, len(report.Mismatches), report.Unresolved(), report.FinishedAt.Sub(report.StartedAt))
+}
@@ -0,0 +1,415 @@
+// Command simulate runs gacha spins through the production draw logic so
+// designers can check the economy before changing a pool.
+//
+// Usage:
+//
+//	go run ./cmd/simulate [-pool pool.json] [-players 1000] [-spins 1000]
+//		[-cost 0] [-pity Epic:10,Legendary:90] [-seed 1] [-checkpoints 20]
+//		[-format text|json|csv] [-report distribution|curve]
+//
+// The pool file has the rarity rates, in parts per million summing to exactly
+// 1000000, the items and optionally the rarity tiers:
+//
+//	{
+//		"tiers": [{"id": 4, "name": "Legendary", "rank": 4, "color": "#ff6600", "min_points": 500, "max_points": 5000}, ...],
+//		"rates": [{"rarity": "Legendary", "weight": 20000, "featured": [4], "featured_share": 500000}, ...],
+//		"items": [{"id": 4, "name": "Diamond", "rarity": "Legendary", "points": 1000}, ...]
+//	}
+//
+// Without the file the tiers and pool the server uses are read from the
+// database configured by the DB_* environment variables, as are the tiers
+// when the file lists none. The CSV format writes one table, selected with
+// -report.
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/infrastructure"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/infrastructure/mysql"
+	infraRepo "github.com/Amane-Fujiwara11/FortuneSpinner/backend/infrastructure/repository"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/gacha"
+)
+
+type tierInput struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Rank      int    `json:"rank"`
+	Color     string `json:"color"`
+	MinPoints int    `json:"min_points"`
+	MaxPoints int    `json:"max_points"`
+}
+
+type rateInput struct {
+	Rarity        string `json:"rarity"`
+	Weight        int    `json:"weight"`
+	Featured      []int  `json:"featured"`
+	FeaturedShare int    `json:"featured_share"`
+}
+
+type itemInput struct {
+	ID     int    `json:"id"`
+	Name   string `json:"name"`
+	Rarity string `json:"rarity"`
+	Points int    `json:"points"`
+}
+
+type poolInput struct {
+	Tiers []tierInput `json:"tiers"`
+	Rates []rateInput `json:"rates"`
+	Items []itemInput `json:"items"`
+}
+
+type itemOutput struct {
+	ItemID     int     `json:"item_id"`
+	ItemName   string  `json:"item_name"`
+	Rarity     string  `json:"rarity"`
+	Points     int     `json:"points"`
+	Configured float64 `json:"configured"`
+	Observed   float64 `json:"observed"`
+	Count      int     `json:"count"`
+}
+
+type rarityOutput struct {
+	Rarity     string  `json:"rarity"`
+	Configured float64 `json:"configured"`
+	Observed   float64 `json:"observed"`
+	Count      int     `json:"count"`
+}
+
+type curveOutput struct {
+	Spin         int     `json:"spin"`
+	PointsIssued int64   `json:"points_issued"`
+	MeanNet      float64 `json:"mean_net"`
+	P10          int     `json:"p10"`
+	P50          int     `json:"p50"`
+	P90          int     `json:"p90"`
+}
+
+type reportOutput struct {
+	Players      int            `json:"players"`
+	SpinsPer     int            `json:"spins_per_player"`
+	Draws        int64          `json:"draws"`
+	SpinCost     int            `json:"spin_cost"`
+	Seed         int64          `json:"seed"`
+	PityTriggers int64          `json:"pity_triggers"`
+	MeanNet      float64        `json:"expected_net_per_spin"`
+	VarianceNet  float64        `json:"variance_per_spin"`
+	StdDevNet    float64        `json:"stddev_per_spin"`
+	Items        []itemOutput   `json:"items"`
+	Rarities     []rarityOutput `json:"rarities"`
+	Curve        []curveOutput  `json:"inflation_curve"`
+	DurationMs   int64          `json:"duration_ms"`
+}
+
+func main() {
+	poolPath := flag.String("pool", "", "JSON file with the rarity rates and items (default: the stored pool)")
+	players := flag.Int("players", 1000, "number of simulated players")
+	spins := flag.Int("spins", 1000, "spins per player")
+	cost := flag.Int("cost", 0, "points charged per spin")
+	pity := flag.String("pity", "", "pity rules as Rarity:spins, comma separated (e.g. Legendary:90)")
+	seed := flag.Int64("seed", time.Now().UnixNano(), "random seed, for reproducible runs")
+	checkpoints := flag.Int("checkpoints", 20, "points on the inflation curve")
+	format := flag.String("format", "text", "output format: text, json or csv")
+	report := flag.String("report", "distribution", "table written by -format csv: distribution or curve")
+	flag.Parse()
+
+	if *players <= 0 || *spins <= 0 || *checkpoints <= 0 || *cost < 0 {
+		log.Fatal("players, spins and checkpoints must be positive and cost must not be negative")
+	}
+
+	tiers, pool, err := loadPool(*poolPath)
+	if err != nil {
+		log.Fatalf("Failed to load pool: %v", err)
+	}
+	pityRules, err := parsePityRules(tiers, *pity)
+	if err != nil {
+		log.Fatalf("Invalid pity rules: %v", err)
+	}
+
+	startedAt := time.Now()
+	result, err := runSimulation(simulationConfig{
+		Tiers:       tiers,
+		Pool:        pool,
+		PityRules:   pityRules,
+		SpinCost:    *cost,
+		Players:     *players,
+		Spins:       *spins,
+		Checkpoints: *checkpoints,
+		Seed:        *seed,
+	})
+	if err != nil {
+		log.Fatalf("Simulation failed: %v", err)
+	}
+
+	output := newReportOutput(tiers, result, *players, *spins, *cost, *seed)
+	output.DurationMs = time.Since(startedAt).Milliseconds()
+
+	switch *format {
+	case "text":
+		printText(output)
+	case "json":
+		printJSON(output)
+	case "csv":
+		if err := printCSV(output, *report); err != nil {
+			log.Fatalf("Failed to write report: %v", err)
+		}
+	default:
+		log.Fatalf("Unknown format %q", *format)
+	}
+}
+
+func loadPool(path string) (*model.RarityRegistry, *model.GachaPool, error) {
+	if path == "" {
+		return loadStoredPool()
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	var input poolInput
+	if err := json.Unmarshal(data, &input); err != nil {
+		return nil, nil, err
+	}
+
+	tiers, err := loadTiers(input.Tiers)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rates := make([]model.RarityRate, 0, len(input.Rates))
+	for _, rate := range input.Rates {
+		rarity, err := tiers.Parse(rate.Rarity)
+		if err != nil {
+			return nil, nil, err
+		}
+		rates = append(rates, model.RarityRate{
+			Rarity:        rarity,
+			Weight:        rate.Weight,
+			FeaturedIDs:   rate.Featured,
+			FeaturedShare: rate.FeaturedShare,
+		})
+	}
+
+	items := make([]model.GachaItem, 0, len(input.Items))
+	for _, item := range input.Items {
+		rarity, err := tiers.Parse(item.Rarity)
+		if err != nil {
+			return nil, nil, err
+		}
+		items = append(items, model.GachaItem{
+			ID:     item.ID,
+			Name:   item.Name,
+			Rarity: rarity,
+			Points: item.Points,
+		})
+	}
+
+	pool, err := model.NewGachaPool(tiers, rates, items)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tiers, pool, nil
+}
+
+// loadTiers builds the registry from the tiers in the pool file, or reads the
+// stored tiers when the file lists none
+func loadTiers(input []tierInput) (*model.RarityRegistry, error) {
+	if len(input) == 0 {
+		db, err := mysql.NewDB(infrastructure.LoadConfig().Database)
+		if err != nil {
+			return nil, fmt.Errorf("the pool file lists no tiers and the stored tiers cannot be read: %w", err)
+		}
+		defer db.Close()
+
+		return gacha.LoadRarityTiers(context.Background(), infraRepo.NewRarityTierRepository(db))
+	}
+
+	tiers := make([]model.RarityTier, 0, len(input))
+	for _, tier := range input {
+		tiers = append(tiers, model.RarityTier{
+			Rarity:    model.Rarity(tier.ID),
+			Name:      tier.Name,
+			Rank:      tier.Rank,
+			Color:     tier.Color,
+			MinPoints: tier.MinPoints,
+			MaxPoints: tier.MaxPoints,
+		})
+	}
+	return model.NewRarityRegistry(tiers)
+}
+
+// loadStoredPool reads the tiers and pool from the server's database
+func loadStoredPool() (*model.RarityRegistry, *model.GachaPool, error) {
+	db, err := mysql.NewDB(infrastructure.LoadConfig().Database)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer db.Close()
+
+	ctx := context.Background()
+	tiers, err := gacha.LoadRarityTiers(ctx, infraRepo.NewRarityTierRepository(db))
+	if err != nil {
+		return nil, nil, err
+	}
+	pool, err := gacha.LoadGachaPool(ctx, infraRepo.NewGachaPoolRepository(db), tiers)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tiers, pool, nil
+}
+
+func parsePityRules(tiers *model.RarityRegistry, raw string) ([]model.PityRule, error) {
+	var rules []model.PityRule
+	if raw == "" {
+		return rules, nil
+	}
+
+	for _, part := range strings.Split(raw, ",") {
+		name, spinsStr, ok := strings.Cut(strings.TrimSpace(part), ":")
+		if !ok {
+			return nil, fmt.Errorf("%q is not Rarity:spins", part)
+		}
+		rarity, err := tiers.Parse(name)
+		if err != nil {
+			return nil, err
+		}
+		spins, err := strconv.Atoi(spinsStr)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not Rarity:spins", part)
+		}
+
+		rule := model.PityRule{Rarity: rarity, AfterSpins: spins}
+		if err := rule.Validate(tiers); err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func newReportOutput(tiers *model.RarityRegistry, result *simulationResult, players, spins, cost int, seed int64) reportOutput {
+	output := reportOutput{
+		Players:      players,
+		SpinsPer:     spins,
+		Draws:        result.Draws,
+		SpinCost:     cost,
+		Seed:         seed,
+		PityTriggers: result.PityTriggers,
+		MeanNet:      result.MeanNet,
+		VarianceNet:  result.VarianceNet,
+		StdDevNet:    math.Sqrt(result.VarianceNet),
+		Items:        make([]itemOutput, 0, len(result.Items)),
+		Rarities:     make([]rarityOutput, 0, len(result.Rarities)),
+		Curve:        make([]curveOutput, 0, len(result.Curve)),
+	}
+	draws := float64(result.Draws)
+	for _, stat := range result.Items {
+		output.Items = append(output.Items, itemOutput{
+			ItemID:     stat.Item.ID,
+			ItemName:   stat.Item.Name,
+			Rarity:     tiers.Name(stat.Item.Rarity),
+			Points:     stat.Item.Points,
+			Configured: stat.Item.Probability(),
+			Observed:   float64(stat.Count) / draws,
+			Count:      stat.Count,
+		})
+	}
+	for _, stat := range result.Rarities {
+		output.Rarities = append(output.Rarities, rarityOutput{
+			Rarity:     tiers.Name(stat.Rarity),
+			Configured: float64(stat.Weight) / model.WeightScale,
+			Observed:   float64(stat.Count) / draws,
+			Count:      stat.Count,
+		})
+	}
+	for _, point := range result.Curve {
+		output.Curve = append(output.Curve, curveOutput(point))
+	}
+	return output
+}
+
+func printJSON(output reportOutput) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(output); err != nil {
+		log.Fatalf("Failed to write report: %v", err)
+	}
+}
+
+func printCSV(output reportOutput, report string) error {
+	writer := csv.NewWriter(os.Stdout)
+	switch report {
+	case "distribution":
+		writer.Write([]string{"item_id", "item_name", "rarity", "points", "configured", "observed", "count"})
+		for _, item := range output.Items {
+			writer.Write([]string{
+				strconv.Itoa(item.ItemID),
+				item.ItemName,
+				item.Rarity,
+				strconv.Itoa(item.Points),
+				formatFloat(item.Configured),
+				formatFloat(item.Observed),
+				strconv.Itoa(item.Count),
+			})
+		}
+	case "curve":
+		writer.Write([]string{"spin", "points_issued", "mean_net", "p10", "p50", "p90"})
+		for _, point := range output.Curve {
+			writer.Write([]string{
+				strconv.Itoa(point.Spin),
+				strconv.FormatInt(point.PointsIssued, 10),
+				formatFloat(point.MeanNet),
+				strconv.Itoa(point.P10),
+				strconv.Itoa(point.P50),
+				strconv.Itoa(point.P90),
+			})
+		}
+	default:
+		return fmt.Errorf("unknown report %q", report)
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func printText(output reportOutput) {
+	fmt.Printf("%d players x %d spins = %d draws (cost %d, seed %d, %d pity triggers, %dms)\n",
+		output.Players, output.SpinsPer, output.Draws, output.SpinCost, output.Seed, output.PityTriggers, output.DurationMs)
+	fmt.Printf("Net points per spin: mean %.4f, variance %.4f, stddev %.4f\n\n",
+		output.MeanNet, output.VarianceNet, output.StdDevNet)
+
+	fmt.Printf("%-20s %-10s %8s %12s %12s %12s\n", "ITEM", "RARITY", "POINTS", "CONFIGURED", "OBSERVED", "COUNT")
+	for _, item := range output.Items {
+		fmt.Printf("%-20s %-10s %8d %12.6f %12.6f %12d\n",
+			item.ItemName, item.Rarity, item.Points, item.Configured, item.Observed, item.Count)
+	}
+	fmt.Println()
+
+	fmt.Printf("%-10s %12s %12s %12s\n", "RARITY", "CONFIGURED", "OBSERVED", "COUNT")
+	for _, rarity := range output.Rarities {
+		fmt.Printf("%-10s %12.6f %12.6f %12d\n", rarity.Rarity, rarity.Configured, rarity.Observed, rarity.Count)
+	}
+	fmt.Println()
+
+	fmt.Printf("%8s %16s %12s %10s %10s %10s\n", "SPIN", "POINTS ISSUED", "MEAN NET", "P10", "P50", "P90")
+	for _, point := range output.Curve {
+		fmt.Printf("%8d %16d %12.2f %10d %10d %10d\n",
+			point.Spin, point.PointsIssued, point.MeanNet, point.P10, point.P50, point.P90)
+	}
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/gacha"
+)
+
+type simulationConfig struct {
+	Tiers       *model.RarityRegistry
+	Pool        *model.GachaPool
+	PityRules   []model.PityRule
+	SpinCost    int
+	Players     int
+	Spins       int // per player
+	Checkpoints int
+	Seed        int64
+}
+
+type itemResult struct {
+	Item  model.GachaItem
+	Count int
+}
+
+type rarityResult struct {
+	Rarity model.Rarity
+	Weight int
+	Count  int
+}
+
+// curvePoint summarises players' cumulative net points after Spin spins
+type curvePoint struct {
+	Spin         int
+	PointsIssued int64 // gross points awarded to all players so far
+	MeanNet      float64
+	P10          int
+	P50          int
+	P90          int
+}
+
+type simulationResult struct {
+	Draws        int64
+	PityTriggers int64
+	Items        []itemResult
+	Rarities     []rarityResult
+	// MeanNet and VarianceNet describe the points gained by one spin after its cost
+	MeanNet     float64
+	VarianceNet float64
+	Curve       []curvePoint
+}
+
+func runSimulation(config simulationConfig) (*simulationResult, error) {
+	rng := rand.New(rand.NewSource(config.Seed))
+
+	sampler, err := gacha.NewPoolSampler(config.Pool, config.Tiers)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range config.PityRules {
+		if !sampler.HasRarityFrom(rule.Rarity) {
+			return nil, fmt.Errorf("pool has no items at or above %s", config.Tiers.Name(rule.Rarity))
+		}
+	}
+
+	counts := make(map[int]int)
+	checkpoints := checkpointSpins(config.Spins, config.Checkpoints)
+	balances := make([][]int, len(checkpoints))
+	for i := range balances {
+		balances[i] = make([]int, config.Players)
+	}
+	issued := make([]int64, len(checkpoints))
+
+	result := &simulationResult{}
+	var mean, m2 float64
+	for player := 0; player < config.Players; player++ {
+		pity := gacha.NewPityCounter(config.Tiers, config.PityRules)
+		balance, gross := 0, int64(0)
+		next := 0
+		for spin := 1; spin <= config.Spins; spin++ {
+			guaranteed := pity.Guaranteed()
+			if guaranteed != 0 {
+				result.PityTriggers++
+			}
+
+			item := sampler.SampleFrom(rng, guaranteed)
+			pity.Record(item)
+			counts[item.ID]++
+
+			net := item.Points - config.SpinCost
+			balance += net
+			gross += int64(item.Points)
+
+			// Welford のオンライン分散
+			result.Draws++
+			delta := float64(net) - mean
+			mean += delta / float64(result.Draws)
+			m2 += delta * (float64(net) - mean)
+
+			if next < len(checkpoints) && spin == checkpoints[next] {
+				balances[next][player] = balance
+				issued[next] += gross
+				next++
+			}
+		}
+	}
+
+	result.MeanNet = mean
+	if result.Draws > 1 {
+		result.VarianceNet = m2 / float64(result.Draws-1)
+	}
+
+	byRarity := make(map[model.Rarity]*rarityResult)
+	for _, item := range config.Pool.Items {
+		result.Items = append(result.Items, itemResult{Item: item, Count: counts[item.ID]})
+		if byRarity[item.Rarity] == nil {
+			byRarity[item.Rarity] = &rarityResult{Rarity: item.Rarity}
+		}
+		byRarity[item.Rarity].Weight += item.Weight
+		byRarity[item.Rarity].Count += counts[item.ID]
+	}
+	for _, tier := range config.Tiers.Tiers() {
+		if stat, ok := byRarity[tier.Rarity]; ok {
+			result.Rarities = append(result.Rarities, *stat)
+		}
+	}
+
+	for i, spin := range checkpoints {
+		result.Curve = append(result.Curve, summariseCheckpoint(spin, issued[i], balances[i]))
+	}
+
+	return result, nil
+}
+
+// checkpointSpins spreads up to n checkpoints evenly over spins, always including the last spin
+func checkpointSpins(spins, n int) []int {
+	if n > spins {
+		n = spins
+	}
+	var checkpoints []int
+	for i := 1; i <= n; i++ {
+		spin := spins * i / n
+		if len(checkpoints) == 0 || checkpoints[len(checkpoints)-1] != spin {
+			checkpoints = append(checkpoints, spin)
+		}
+	}
+	return checkpoints
+}
+
+func summariseCheckpoint(spin int, issued int64, balances []int) curvePoint {
+	sort.Ints(balances)
+	total := int64(0)
+	for _, balance := range balances {
+		total += int64(balance)
+	}
+	return curvePoint{
+		Spin:         spin,
+		PointsIssued: issued,
+		MeanNet:      float64(total) / float64(len(balances)),
+		P10:          percentile(balances, 0.10),
+		P50:          percentile(balances, 0.50),
+		P90:          percentile(balances, 0.90),
+	}
+}
+
+// percentile uses the nearest-rank method on sorted values
+func percentile(sorted []int, p float64) int {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
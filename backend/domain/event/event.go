@@ -0,0 +1,186 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+// Event is something that happened in the domain that other features may react to
+type Event interface {
+	EventName() string
+}
+
+// UserEvent is an event concerning a single user
+type UserEvent interface {
+	Event
+	EventUserID() int
+}
+
+// Handler reacts to a published event
+type Handler func(ctx context.Context, e Event) error
+
+// Publisher delivers events to subscribers
+type Publisher interface {
+	Publish(ctx context.Context, events ...Event) error
+}
+
+type idKey struct{}
+
+// WithID attaches the ID of the stored event being delivered to ctx so
+// subscribers can detect redelivery
+func WithID(ctx context.Context, id int64) context.Context {
+	return context.WithValue(ctx, idKey{}, id)
+}
+
+// IDFromContext returns the ID set by WithID
+func IDFromContext(ctx context.Context) (int64, bool) {
+	id, ok := ctx.Value(idKey{}).(int64)
+	return id, ok
+}
+
+// Decode rebuilds an event from its name and JSON payload
+func Decode(name string, payload []byte) (Event, error) {
+	var e Event
+	switch name {
+	case NameGachaExecuted:
+		var executed GachaExecuted
+		if err := json.Unmarshal(payload, &executed); err != nil {
+			return nil, err
+		}
+		e = executed
+	case NamePointsCredited:
+		var credited PointsCredited
+		if err := json.Unmarshal(payload, &credited); err != nil {
+			return nil, err
+		}
+		e = credited
+	case NamePointsDebited:
+		var debited PointsDebited
+		if err := json.Unmarshal(payload, &debited); err != nil {
+			return nil, err
+		}
+		e = debited
+	case NameUserCreated:
+		var created UserCreated
+		if err := json.Unmarshal(payload, &created); err != nil {
+			return nil, err
+		}
+		e = created
+	case NameAchievementUnlocked:
+		var unlocked AchievementUnlocked
+		if err := json.Unmarshal(payload, &unlocked); err != nil {
+			return nil, err
+		}
+		e = unlocked
+	default:
+		return nil, fmt.Errorf("unknown event %q", name)
+	}
+	return e, nil
+}
+
+const (
+	NameGachaExecuted       = "gacha.executed"
+	NamePointsCredited      = "points.credited"
+	NamePointsDebited       = "points.debited"
+	NameUserCreated         = "user.created"
+	NameAchievementUnlocked = "achievement.unlocked"
+)
+
+type GachaExecuted struct {
+	ResultID     int          `json:"result_id"`
+	UserID       int          `json:"user_id"`
+	ItemID       int          `json:"item_id"`
+	ItemName     string       `json:"item_name"`
+	Rarity       model.Rarity `json:"rarity"`
+	PointsEarned int          `json:"points_earned"`
+	OccurredAt   time.Time    `json:"occurred_at"`
+}
+
+func (e GachaExecuted) EventName() string { return NameGachaExecuted }
+func (e GachaExecuted) EventUserID() int  { return e.UserID }
+
+// NewGachaExecuted describes a saved gacha result
+func NewGachaExecuted(result *model.GachaResult) GachaExecuted {
+	return GachaExecuted{
+		ResultID:     result.ID,
+		UserID:       result.UserID,
+		ItemID:       result.ItemID,
+		ItemName:     result.ItemName,
+		Rarity:       result.Rarity,
+		PointsEarned: result.PointsEarned,
+		OccurredAt:   result.CreatedAt,
+	}
+}
+
+// Result rebuilds the gacha result the event describes
+func (e GachaExecuted) Result() *model.GachaResult {
+	return &model.GachaResult{
+		ID:           e.ResultID,
+		UserID:       e.UserID,
+		ItemID:       e.ItemID,
+		ItemName:     e.ItemName,
+		Rarity:       e.Rarity,
+		PointsEarned: e.PointsEarned,
+		CreatedAt:    e.OccurredAt,
+	}
+}
+
+// PointsChanged is the payload shared by PointsCredited and PointsDebited.
+// Amount is signed like PointTransaction.Amount.
+type PointsChanged struct {
+	TransactionID int                   `json:"transaction_id"`
+	UserID        int                   `json:"user_id"`
+	Amount        int                   `json:"amount"`
+	Type          model.TransactionType `json:"type"`
+	BalanceAfter  int                   `json:"balance_after"`
+	OccurredAt    time.Time             `json:"occurred_at"`
+}
+
+type PointsCredited struct{ PointsChanged }
+
+func (e PointsCredited) EventName() string { return NamePointsCredited }
+func (e PointsCredited) EventUserID() int  { return e.UserID }
+
+type PointsDebited struct{ PointsChanged }
+
+func (e PointsDebited) EventName() string { return NamePointsDebited }
+func (e PointsDebited) EventUserID() int  { return e.UserID }
+
+// NewPointsChanged describes a saved point transaction as a credit or debit
+func NewPointsChanged(tx *model.PointTransaction) UserEvent {
+	changed := PointsChanged{
+		TransactionID: tx.ID,
+		UserID:        tx.UserID,
+		Amount:        tx.Amount,
+		Type:          tx.Type,
+		BalanceAfter:  tx.BalanceAfter,
+		OccurredAt:    tx.CreatedAt,
+	}
+	if tx.Amount < 0 {
+		return PointsDebited{changed}
+	}
+	return PointsCredited{changed}
+}
+
+type UserCreated struct {
+	UserID     int       `json:"user_id"`
+	Name       string    `json:"name"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (e UserCreated) EventName() string { return NameUserCreated }
+func (e UserCreated) EventUserID() int  { return e.UserID }
+
+type AchievementUnlocked struct {
+	UserID       int       `json:"user_id"`
+	Code         string    `json:"code"`
+	RewardPoints int       `json:"reward_points"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
+
+func (e AchievementUnlocked) EventName() string { return NameAchievementUnlocked }
+func (e AchievementUnlocked) EventUserID() int  { return e.UserID }
@@ -0,0 +1,253 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrSpinThrottled is returned when a flagged user spins faster than allowed
+	ErrSpinThrottled = errors.New("spinning too fast")
+	// ErrAbuseFlagReviewed is returned when reviewing a flag that is already closed
+	ErrAbuseFlagReviewed = errors.New("abuse flag has already been reviewed")
+)
+
+// AbuseSignals is the activity of one user that the abuse rules score
+type AbuseSignals struct {
+	UserID     int
+	AccountAge time.Duration
+	// SpinTimes are the user's most recent spins, newest first
+	SpinTimes []time.Time
+	// ClientRecorded is false for accounts created before signup IPs and user
+	// agents were recorded; the multi-accounting rules skip them
+	ClientRecorded bool
+	// AccountsFromIP and AccountsWithUserAgent count accounts, including this
+	// one, that signed up from the same IP or user agent within the window
+	AccountsFromIP        int
+	AccountsWithUserAgent int
+	UserAgent             string
+	// NewAccountSenders counts the distinct accounts younger than the window
+	// that sent this user points within the window
+	NewAccountSenders int
+	// PointsEarned and PointsSent are gacha winnings and transfers out within the window
+	PointsEarned int
+	PointsSent   int
+}
+
+// AbuseRules are the thresholds the score is built from
+type AbuseRules struct {
+	// Window bounds the activity that is looked at
+	Window time.Duration
+
+	// Scripted spinning: at least MinSpinsForCadence recent spins whose
+	// intervals vary less than RegularCadenceVariation (coefficient of
+	// variation), or whose median interval is below RapidSpinInterval
+	MinSpinsForCadence      int
+	RegularCadenceVariation float64
+	RapidSpinInterval       time.Duration
+
+	// Multi-accounting
+	MaxAccountsPerIP        int
+	MaxAccountsPerUserAgent int
+	ScriptedUserAgents      []string
+
+	// Transfer graph: collecting from many new accounts, or a new account
+	// forwarding most of what it earned
+	MaxNewAccountSenders int
+	ForwardedShare       float64
+
+	// FlagScore puts the user in the review queue; ThrottleScore also limits
+	// them to one spin per ThrottleInterval until reviewed
+	FlagScore        int
+	ThrottleScore    int
+	ThrottleInterval time.Duration
+}
+
+func DefaultAbuseRules() AbuseRules {
+	return AbuseRules{
+		Window:                  7 * 24 * time.Hour,
+		MinSpinsForCadence:      20,
+		RegularCadenceVariation: 0.1,
+		RapidSpinInterval:       time.Second,
+		MaxAccountsPerIP:        5,
+		MaxAccountsPerUserAgent: 20,
+		ScriptedUserAgents:      []string{"curl", "wget", "python", "go-http-client", "okhttp", "httpclient", "axios", "node-fetch", "postman", "headless"},
+		MaxNewAccountSenders:    5,
+		ForwardedShare:          0.8,
+		FlagScore:               40,
+		ThrottleScore:           70,
+		ThrottleInterval:        30 * time.Second,
+	}
+}
+
+// AbuseAssessment is a score from 0 to 100 with the reasons behind it
+type AbuseAssessment struct {
+	Score   int
+	Reasons []string
+}
+
+func (a *AbuseAssessment) add(points int, reason string) {
+	a.Score += points
+	a.Reasons = append(a.Reasons, reason)
+}
+
+// Assess scores the signals. Each rule that fires adds a fixed number of points.
+func (r AbuseRules) Assess(signals AbuseSignals) AbuseAssessment {
+	var assessment AbuseAssessment
+
+	if len(signals.SpinTimes) >= r.MinSpinsForCadence && len(signals.SpinTimes) > 1 {
+		median, variation := spinCadence(signals.SpinTimes)
+		if variation < r.RegularCadenceVariation {
+			assessment.add(30, fmt.Sprintf("machine-regular spin cadence (variation %.2f)", variation))
+		}
+		if median < r.RapidSpinInterval {
+			assessment.add(20, fmt.Sprintf("rapid spinning (median interval %s)", median))
+		}
+	}
+
+	if signals.ClientRecorded {
+		if signals.AccountsFromIP > r.MaxAccountsPerIP {
+			assessment.add(25, fmt.Sprintf("%d accounts signed up from the same IP", signals.AccountsFromIP))
+		}
+		if signals.AccountsWithUserAgent > r.MaxAccountsPerUserAgent {
+			assessment.add(15, fmt.Sprintf("%d accounts signed up with the same user agent", signals.AccountsWithUserAgent))
+		}
+		if r.isScriptedUserAgent(signals.UserAgent) {
+			assessment.add(20, "signed up with a scripted or empty user agent")
+		}
+	}
+
+	if signals.NewAccountSenders > r.MaxNewAccountSenders {
+		assessment.add(30, fmt.Sprintf("received points from %d new accounts", signals.NewAccountSenders))
+	}
+	if signals.AccountAge < r.Window && signals.PointsEarned > 0 &&
+		float64(signals.PointsSent) >= r.ForwardedShare*float64(signals.PointsEarned) {
+		assessment.add(25, fmt.Sprintf("new account forwarded %d of %d points earned", signals.PointsSent, signals.PointsEarned))
+	}
+
+	if assessment.Score > 100 {
+		assessment.Score = 100
+	}
+	return assessment
+}
+
+func (r AbuseRules) isScriptedUserAgent(userAgent string) bool {
+	userAgent = strings.ToLower(strings.TrimSpace(userAgent))
+	if userAgent == "" {
+		return true
+	}
+	for _, token := range r.ScriptedUserAgents {
+		if strings.Contains(userAgent, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// spinCadence returns the median interval between spins and the coefficient
+// of variation of the intervals
+func spinCadence(times []time.Time) (time.Duration, float64) {
+	intervals := make([]float64, 0, len(times)-1)
+	for i := 1; i < len(times); i++ {
+		intervals = append(intervals, math.Abs(times[i-1].Sub(times[i]).Seconds()))
+	}
+
+	mean := 0.0
+	for _, interval := range intervals {
+		mean += interval
+	}
+	mean /= float64(len(intervals))
+
+	variance := 0.0
+	for _, interval := range intervals {
+		variance += (interval - mean) * (interval - mean)
+	}
+	variance /= float64(len(intervals))
+
+	sort.Float64s(intervals)
+	median := time.Duration(intervals[len(intervals)/2] * float64(time.Second))
+
+	if mean == 0 {
+		return median, 0
+	}
+	return median, math.Sqrt(variance) / mean
+}
+
+type AbuseFlagStatus string
+
+const (
+	AbuseFlagOpen      AbuseFlagStatus = "open"
+	AbuseFlagDismissed AbuseFlagStatus = "dismissed"
+	AbuseFlagFrozen    AbuseFlagStatus = "frozen"
+)
+
+// AbuseFlag is a suspicious user waiting for, or after, admin review
+type AbuseFlag struct {
+	ID         int
+	UserID     int
+	Score      int
+	Reasons    []string
+	Throttled  bool
+	Status     AbuseFlagStatus
+	ReviewedBy string
+	ReviewNote string
+	ReviewedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// NewAbuseFlag opens a flag for an assessment at or above the flag score
+func NewAbuseFlag(userID int, assessment AbuseAssessment, rules AbuseRules, now time.Time) *AbuseFlag {
+	return &AbuseFlag{
+		UserID:    userID,
+		Score:     assessment.Score,
+		Reasons:   assessment.Reasons,
+		Throttled: assessment.Score >= rules.ThrottleScore,
+		Status:    AbuseFlagOpen,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Reassess updates an open flag with a newer assessment and reports whether it changed
+func (f *AbuseFlag) Reassess(assessment AbuseAssessment, rules AbuseRules, now time.Time) bool {
+	throttled := f.Throttled || assessment.Score >= rules.ThrottleScore
+	if assessment.Score <= f.Score && throttled == f.Throttled {
+		return false
+	}
+	if assessment.Score > f.Score {
+		f.Score = assessment.Score
+		f.Reasons = assessment.Reasons
+	}
+	f.Throttled = throttled
+	f.UpdatedAt = now
+	return true
+}
+
+// Resolve closes an open flag; reviewing lifts any throttle
+func (f *AbuseFlag) Resolve(status AbuseFlagStatus, adminID, note string, now time.Time) error {
+	if f.Status != AbuseFlagOpen {
+		return ErrAbuseFlagReviewed
+	}
+	if status != AbuseFlagDismissed && status != AbuseFlagFrozen {
+		return errors.New("review action must be dismiss or freeze")
+	}
+	if strings.TrimSpace(adminID) == "" {
+		return errors.New("acting admin is required")
+	}
+	if len(note) > 500 {
+		return errors.New("review note must be at most 500 characters long")
+	}
+
+	f.Status = status
+	f.Throttled = false
+	f.ReviewedBy = adminID
+	f.ReviewNote = strings.TrimSpace(note)
+	f.ReviewedAt = &now
+	f.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,136 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+// AchievementMetric is the statistic an achievement rule is evaluated against
+type AchievementMetric string
+
+const (
+	AchievementMetricLegendaryPulls AchievementMetric = "legendary_pulls"
+	AchievementMetricTotalSpins     AchievementMetric = "total_spins"
+	AchievementMetricPointLevel     AchievementMetric = "point_level"
+	AchievementMetricSpinStreakDays AchievementMetric = "spin_streak_days"
+)
+
+// AchievementRule declares when an achievement unlocks and what it pays out
+type AchievementRule struct {
+	Code        string
+	Name        string
+	Description string
+	Metric      AchievementMetric
+	// Threshold is the minimum value of Metric; unused for point_level
+	Threshold int
+	// Level is the minimum GetPointLevel result for point_level rules
+	Level        string
+	RewardPoints int
+}
+
+// DefaultAchievementRules returns the achievements available to every user
+func DefaultAchievementRules() []AchievementRule {
+	return []AchievementRule{
+		{Code: "first_legendary", Name: "First Legendary", Description: "Pull your first Legendary item", Metric: AchievementMetricLegendaryPulls, Threshold: 1, RewardPoints: 500},
+		{Code: "spins_100", Name: "Centurion", Description: "Spin the gacha 100 times", Metric: AchievementMetricTotalSpins, Threshold: 100, RewardPoints: 200},
+		{Code: "level_gold", Name: "Gold Standard", Description: "Reach the Gold point level", Metric: AchievementMetricPointLevel, Level: "Gold"},
+		{Code: "streak_7", Name: "Weekly Devotion", Description: "Spin on 7 consecutive days", Metric: AchievementMetricSpinStreakDays, Threshold: 7, RewardPoints: 300},
+	}
+}
+
+// Validate validates the rule according to business rules
+func (r AchievementRule) Validate() error {
+	if r.Code == "" || r.Name == "" {
+		return errors.New("achievement code and name are required")
+	}
+
+	switch r.Metric {
+	case AchievementMetricLegendaryPulls, AchievementMetricTotalSpins, AchievementMetricSpinStreakDays:
+		if r.Threshold <= 0 {
+			return errors.New("achievement threshold must be positive")
+		}
+	case AchievementMetricPointLevel:
+		if PointLevelRank(r.Level) == 0 {
+			return errors.New("invalid achievement point level")
+		}
+	default:
+		return errors.New("invalid achievement metric")
+	}
+
+	if r.RewardPoints < 0 || r.RewardPoints > MaxTransactionAmount {
+		return errors.New("achievement reward is outside allowed range")
+	}
+
+	return nil
+}
+
+// IsSatisfied checks whether the progress meets the rule
+func (r AchievementRule) IsSatisfied(progress AchievementProgress) bool {
+	switch r.Metric {
+	case AchievementMetricLegendaryPulls:
+		return progress.LegendaryPulls >= r.Threshold
+	case AchievementMetricTotalSpins:
+		return progress.TotalSpins >= r.Threshold
+	case AchievementMetricPointLevel:
+		userPoint := UserPoint{Balance: progress.Balance}
+		return PointLevelRank(userPoint.GetPointLevel()) >= PointLevelRank(r.Level)
+	case AchievementMetricSpinStreakDays:
+		return progress.StreakDays >= r.Threshold
+	default:
+		return false
+	}
+}
+
+// AchievementProgress is the snapshot of a user's statistics rules are evaluated against
+type AchievementProgress struct {
+	TotalSpins     int
+	LegendaryPulls int
+	Balance        int
+	StreakDays     int
+}
+
+// SpinStreakDays counts consecutive days with at least one spin, ending
+// today or yesterday. days are UTC dates, in any order.
+func SpinStreakDays(days []time.Time, now time.Time) int {
+	spun := make(map[string]bool, len(days))
+	for _, day := range days {
+		spun[day.UTC().Format("2006-01-02")] = true
+	}
+
+	day := StartOfDay(now)
+	if !spun[day.Format("2006-01-02")] {
+		// 今日まだ回していなくても、昨日までの連続記録は途切れていない
+		day = day.AddDate(0, 0, -1)
+	}
+
+	streak := 0
+	for spun[day.Format("2006-01-02")] {
+		streak++
+		day = day.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+// UserAchievement is an achievement unlocked by a user
+type UserAchievement struct {
+	ID                  int
+	UserID              int
+	Code                string
+	UnlockedAt          time.Time
+	RewardTransactionID int // 0 when the achievement pays no reward
+}
+
+// NewUserAchievement creates a new unlock record
+func NewUserAchievement(userID int, rule AchievementRule) *UserAchievement {
+	return &UserAchievement{
+		UserID:     userID,
+		Code:       rule.Code,
+		UnlockedAt: time.Now(),
+	}
+}
+
+// AchievementStatus pairs a rule with the user's unlock, if any
+type AchievementStatus struct {
+	Rule     AchievementRule
+	Unlocked *UserAchievement
+}
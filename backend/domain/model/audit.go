@@ -0,0 +1,119 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AuditActorSystem is the actor of changes no user or admin asked for
+const AuditActorSystem = "system"
+
+// AuditEntry is one record of the append-only audit log. Entries form a hash
+// chain: each Hash covers the entry and the previous entry's hash, so editing
+// or deleting a stored entry breaks every hash after it.
+type AuditEntry struct {
+	Seq        int64 // position in the chain, starting at 1
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   string
+	// Before and After are JSON snapshots of the target; nil when it did
+	// not exist before or after the change
+	Before    json.RawMessage
+	After     json.RawMessage
+	RequestID string
+	IP        string
+	CreatedAt time.Time
+	PrevHash  string
+	Hash      string
+	// QueueID identifies the entry while it waits to be chained
+	QueueID int64
+}
+
+// AuditChainHead is the last entry of the chain
+type AuditChainHead struct {
+	Seq  int64
+	Hash string
+}
+
+// NewAuditEntry validates an entry before it is chained. CreatedAt is kept
+// to the second, the precision it is stored with, so the hash can be
+// recomputed from the stored row.
+func NewAuditEntry(actor, action, targetType, targetID string, before, after json.RawMessage, requestID, ip string, now time.Time) (*AuditEntry, error) {
+	if strings.TrimSpace(actor) == "" {
+		return nil, errors.New("audit actor is required")
+	}
+	if strings.TrimSpace(action) == "" || strings.TrimSpace(targetType) == "" {
+		return nil, errors.New("audit action and target type are required")
+	}
+
+	return &AuditEntry{
+		Actor:      actor,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Before:     before,
+		After:      after,
+		RequestID:  requestID,
+		IP:         ip,
+		CreatedAt:  now.UTC().Truncate(time.Second),
+	}, nil
+}
+
+// Chain appends the entry after head
+func (e *AuditEntry) Chain(head AuditChainHead) {
+	e.Seq = head.Seq + 1
+	e.PrevHash = head.Hash
+	e.Hash = e.ComputeHash()
+}
+
+// ComputeHash hashes the entry's fields together with PrevHash
+func (e *AuditEntry) ComputeHash() string {
+	// 文字列の配列として JSON 化し、フィールドの区切りを曖昧にしない
+	fields, _ := json.Marshal([]string{
+		strconv.FormatInt(e.Seq, 10),
+		e.PrevHash,
+		e.Actor,
+		e.Action,
+		e.TargetType,
+		e.TargetID,
+		string(e.Before),
+		string(e.After),
+		e.RequestID,
+		e.IP,
+		e.CreatedAt.UTC().Format(time.RFC3339),
+	})
+	sum := sha256.Sum256(fields)
+	return hex.EncodeToString(sum[:])
+}
+
+// VerifyAuditChain checks entries in order after head and returns the new
+// head, or the sequence number of the first entry that does not fit
+func VerifyAuditChain(head AuditChainHead, entries []*AuditEntry) (AuditChainHead, int64, bool) {
+	for _, entry := range entries {
+		if entry.Seq != head.Seq+1 || entry.PrevHash != head.Hash || entry.Hash != entry.ComputeHash() {
+			return head, entry.Seq, false
+		}
+		head = AuditChainHead{Seq: entry.Seq, Hash: entry.Hash}
+	}
+	return head, 0, true
+}
+
+// AuditFilter selects audit entries; zero fields match everything
+type AuditFilter struct {
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   string
+	RequestID  string
+	Since      *time.Time
+	Until      *time.Time
+	// BeforeSeq pages backwards: only entries with a smaller Seq match
+	BeforeSeq int64
+	Limit     int
+}
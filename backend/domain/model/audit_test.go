@@ -0,0 +1,134 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// auditChain builds n chained entries the way the audit-chain job does
+func auditChain(t *testing.T, n int) []*AuditEntry {
+	t.Helper()
+
+	var entries []*AuditEntry
+	head := AuditChainHead{}
+	for i := 1; i <= n; i++ {
+		after := json.RawMessage(fmt.Sprintf(`{"balance":%d}`, i*100))
+		entry, err := NewAuditEntry("admin:ops", "points.adjust", "user", "1", nil, after, "req-1", "203.0.113.7", time.Date(2026, 5, 1, 9, 0, i, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry.Chain(head)
+		head = AuditChainHead{Seq: entry.Seq, Hash: entry.Hash}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestVerifyAuditChainAcceptsIntactChain(t *testing.T) {
+	entries := auditChain(t, 5)
+
+	head, brokenAt, ok := VerifyAuditChain(AuditChainHead{}, entries)
+	if !ok {
+		t.Fatalf("intact chain reported broken at %d", brokenAt)
+	}
+	if last := entries[len(entries)-1]; head.Seq != last.Seq || head.Hash != last.Hash {
+		t.Errorf("head = %+v, want the last entry", head)
+	}
+
+	// 途中の先頭から続きを検証できる
+	resumed := AuditChainHead{Seq: entries[1].Seq, Hash: entries[1].Hash}
+	if _, brokenAt, ok := VerifyAuditChain(resumed, entries[2:]); !ok {
+		t.Errorf("chain resumed after entry 2 reported broken at %d", brokenAt)
+	}
+}
+
+func TestVerifyAuditChainDetectsTampering(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(entries []*AuditEntry) []*AuditEntry
+		want   int64
+	}{
+		{
+			name: "edited snapshot",
+			tamper: func(entries []*AuditEntry) []*AuditEntry {
+				entries[2].After = json.RawMessage(`{"balance":999999}`)
+				return entries
+			},
+			want: 3,
+		},
+		{
+			name: "edited actor",
+			tamper: func(entries []*AuditEntry) []*AuditEntry {
+				entries[0].Actor = "admin:someone-else"
+				return entries
+			},
+			want: 1,
+		},
+		{
+			name: "edited entry with its hash recomputed",
+			tamper: func(entries []*AuditEntry) []*AuditEntry {
+				entries[2].After = json.RawMessage(`{"balance":999999}`)
+				entries[2].Hash = entries[2].ComputeHash()
+				return entries
+			},
+			want: 4,
+		},
+		{
+			name: "deleted entry",
+			tamper: func(entries []*AuditEntry) []*AuditEntry {
+				return append(entries[:2:2], entries[3:]...)
+			},
+			want: 4,
+		},
+		{
+			name: "deleted entry with the sequence renumbered",
+			tamper: func(entries []*AuditEntry) []*AuditEntry {
+				entries = append(entries[:2:2], entries[3:]...)
+				for i, entry := range entries[2:] {
+					entry.Seq = int64(i + 3)
+				}
+				return entries
+			},
+			want: 3,
+		},
+		{
+			name: "swapped entries",
+			tamper: func(entries []*AuditEntry) []*AuditEntry {
+				entries[1], entries[2] = entries[2], entries[1]
+				return entries
+			},
+			want: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := tt.tamper(auditChain(t, 5))
+			_, brokenAt, ok := VerifyAuditChain(AuditChainHead{}, entries)
+			if ok {
+				t.Fatal("tampered chain verified")
+			}
+			if brokenAt != tt.want {
+				t.Errorf("broken at %d, want %d", brokenAt, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuditHashSeparatesFields(t *testing.T) {
+	a, err := NewAuditEntry("admin:ops", "points.adjust", "user", "12", nil, nil, "", "", time.Unix(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewAuditEntry("admin:ops", "points.adjust", "user1", "2", nil, nil, "", "", time.Unix(0, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.Chain(AuditChainHead{})
+	b.Chain(AuditChainHead{})
+	if a.Hash == b.Hash {
+		t.Error("moving characters between fields does not change the hash")
+	}
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// Days and weeks are UTC everywhere, so the daily transfer cap, the spending
+// limits, the leaderboard windows and spin streaks all roll over together.
+
+// StartOfDay returns midnight UTC of the day containing t
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.UTC().Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+// StartOfWeek returns midnight UTC of the Monday of the week containing t
+func StartOfWeek(t time.Time) time.Time {
+	day := StartOfDay(t)
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset)
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowsStartInUTC(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	// 東京では月曜の朝だが、UTC ではまだ日曜
+	now := time.Date(2026, 6, 8, 7, 30, 0, 0, tokyo)
+
+	wantDay := time.Date(2026, 6, 7, 0, 0, 0, 0, time.UTC)
+	wantWeek := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	if got := StartOfDay(now); !got.Equal(wantDay) || got.Location() != time.UTC {
+		t.Errorf("StartOfDay = %s, want %s", got, wantDay)
+	}
+	if got := StartOfWeek(now); !got.Equal(wantWeek) || got.Location() != time.UTC {
+		t.Errorf("StartOfWeek = %s, want %s", got, wantWeek)
+	}
+
+	// ランキングの期間は上限と同じ境界を使う
+	if got := LeaderboardPeriodDaily.Start(now); !got.Equal(StartOfDay(now)) {
+		t.Errorf("daily leaderboard starts %s, daily caps %s", got, StartOfDay(now))
+	}
+	if got := LeaderboardPeriodWeekly.Start(now); !got.Equal(StartOfWeek(now)) {
+		t.Errorf("weekly leaderboard starts %s, weekly caps %s", got, StartOfWeek(now))
+	}
+}
+
+func TestStartOfWeekOnMonday(t *testing.T) {
+	monday := time.Date(2026, 6, 8, 0, 0, 0, 0, time.UTC)
+	for _, now := range []time.Time{monday, monday.Add(36 * time.Hour), monday.AddDate(0, 0, 7).Add(-time.Nanosecond)} {
+		if got := StartOfWeek(now); !got.Equal(monday) {
+			t.Errorf("StartOfWeek(%s) = %s, want %s", now, got, monday)
+		}
+	}
+}
+
+func TestEveryLeaderboardMetricHasEveryPeriod(t *testing.T) {
+	for _, metric := range []LeaderboardMetric{LeaderboardMetricBalance, LeaderboardMetricGachaPoints, LeaderboardMetricLegendaryPulls} {
+		for _, period := range LeaderboardPeriods() {
+			if err := ValidateLeaderboard(metric, period); err != nil {
+				t.Errorf("%s %s: %v", metric, period, err)
+			}
+		}
+	}
+	if err := ValidateLeaderboard(LeaderboardMetricBalance, "monthly"); err == nil {
+		t.Error("unknown period accepted")
+	}
+}
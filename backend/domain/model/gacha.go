@@ -2,97 +2,41 @@ package model
 
 import (
 	"errors"
+	"fmt"
 	"math"
 )
 
-type Rarity int
-
-const (
-	RarityCommon Rarity = iota + 1
-	RarityRare
-	RarityEpic
-	RarityLegendary
-)
+// WeightScale is the sum of the weights of a pool: weights are parts per million
+const WeightScale = 1000000
 
 type GachaItem struct {
-	ID          int
-	Name        string
-	Rarity      Rarity
-	Points      int
-	Probability float64
-}
-
-func (r Rarity) String() string {
-	switch r {
-	case RarityCommon:
-		return "Common"
-	case RarityRare:
-		return "Rare"
-	case RarityEpic:
-		return "Epic"
-	case RarityLegendary:
-		return "Legendary"
-	default:
-		return "Unknown"
-	}
-}
-
-// IsValid checks if the rarity is valid
-func (r Rarity) IsValid() bool {
-	return r >= RarityCommon && r <= RarityLegendary
-}
-
-// GetMinPoints returns the minimum points for this rarity level
-func (r Rarity) GetMinPoints() int {
-	switch r {
-	case RarityCommon:
-		return 1
-	case RarityRare:
-		return 10
-	case RarityEpic:
-		return 100
-	case RarityLegendary:
-		return 500
-	default:
-		return 0
-	}
-}
-
-// GetMaxPoints returns the maximum points for this rarity level
-func (r Rarity) GetMaxPoints() int {
-	switch r {
-	case RarityCommon:
-		return 50
-	case RarityRare:
-		return 200
-	case RarityEpic:
-		return 1000
-	case RarityLegendary:
-		return 5000
-	default:
-		return 0
-	}
+	ID     int
+	Name   string
+	Rarity Rarity
+	Points int
+	Weight int // parts per million of spins that draw this item
 }
 
-// NewGachaItem creates a new gacha item with validation
-func NewGachaItem(id int, name string, rarity Rarity, points int, probability float64) (*GachaItem, error) {
+// NewGachaItem creates a new gacha item with validation against the rarity tiers
+func NewGachaItem(tiers *RarityRegistry, id int, name string, rarity Rarity, points int, weight int) (*GachaItem, error) {
 	item := &GachaItem{
-		ID:          id,
-		Name:        name,
-		Rarity:      rarity,
-		Points:      points,
-		Probability: probability,
+		ID:     id,
+		Name:   name,
+		Rarity: rarity,
+		Points: points,
+		Weight: weight,
 	}
 	
-	if err := item.Validate(); err != nil {
+	if err := item.Validate(tiers); err != nil {
 		return nil, err
 	}
 	
 	return item, nil
 }
 
-// Validate validates the gacha item according to business rules
-func (gi *GachaItem) Validate() error {
+// Validate validates the gacha item according to business rules; its rarity
+// must be one of the tiers and its points within the tier's range
+func (gi *GachaItem) Validate(tiers *RarityRegistry) error {
 	if gi.ID <= 0 {
 		return errors.New("gacha item ID must be positive")
 	}
@@ -101,66 +45,66 @@ func (gi *GachaItem) Validate() error {
 		return errors.New("gacha item name cannot be empty")
 	}
 	
-	if !gi.Rarity.IsValid() {
+	tier, ok := tiers.Tier(gi.Rarity)
+	if !ok {
 		return errors.New("invalid rarity level")
 	}
 	
-	minPoints := gi.Rarity.GetMinPoints()
-	maxPoints := gi.Rarity.GetMaxPoints()
-	
-	if gi.Points < minPoints || gi.Points > maxPoints {
+	if gi.Points < tier.MinPoints || gi.Points > tier.MaxPoints {
 		return errors.New("points value doesn't match rarity constraints")
 	}
 	
-	if gi.Probability < 0 || gi.Probability > 1 {
-		return errors.New("probability must be between 0 and 1")
+	if gi.Weight < 0 || gi.Weight > WeightScale {
+		return errors.New("weight must be between 0 and 1000000")
 	}
 	
 	return nil
 }
 
-// GetGachaItems returns the configured gacha items with business rule validation
-func GetGachaItems() ([]GachaItem, error) {
-	items := []GachaItem{
-		{ID: 1, Name: "Bronze Coin", Rarity: RarityCommon, Points: 10, Probability: 0.60},
-		{ID: 2, Name: "Silver Coin", Rarity: RarityRare, Points: 50, Probability: 0.30},
-		{ID: 3, Name: "Gold Coin", Rarity: RarityEpic, Points: 200, Probability: 0.08},
-		{ID: 4, Name: "Diamond", Rarity: RarityLegendary, Points: 1000, Probability: 0.02},
+// Probability returns the item's weight as a probability
+func (gi GachaItem) Probability() float64 {
+	return float64(gi.Weight) / WeightScale
+}
+
+// WeightFromProbability converts a probability to parts per million, rounding to the nearest part
+func WeightFromProbability(probability float64) int {
+	return int(math.Round(probability * WeightScale))
+}
+
+// ValidateGachaItems validates every item of a pool and that their weights sum to exactly WeightScale
+func ValidateGachaItems(tiers *RarityRegistry, items []GachaItem) error {
+	if len(items) == 0 {
+		return errors.New("gacha pool must contain items")
 	}
-	
+
 	// Validate all items
 	for _, item := range items {
-		if err := item.Validate(); err != nil {
-			return nil, err
+		if err := item.Validate(tiers); err != nil {
+			return err
 		}
 	}
 	
-	// Validate that probabilities sum to approximately 1.0
-	totalProbability := 0.0
+	// Validate that weights sum to exactly 100%
+	totalWeight := 0
 	for _, item := range items {
-		totalProbability += item.Probability
+		totalWeight += item.Weight
 	}
 	
-	if math.Abs(totalProbability-1.0) > 0.001 {
-		return nil, errors.New("gacha item probabilities must sum to 1.0")
+	if totalWeight != WeightScale {
+		return fmt.Errorf("gacha item weights must sum to %d, got %d", WeightScale, totalWeight)
 	}
 	
-	return items, nil
+	return nil
 }
 
-// GetItemByRarity returns all items of a specific rarity
-func GetItemsByRarity(rarity Rarity) ([]GachaItem, error) {
-	allItems, err := GetGachaItems()
-	if err != nil {
-		return nil, err
-	}
-	
+// ItemsOfRarity returns the items of a specific rarity, keeping their order
+func ItemsOfRarity(items []GachaItem, rarity Rarity) []GachaItem {
 	var filteredItems []GachaItem
-	for _, item := range allItems {
+	for _, item := range items {
 		if item.Rarity == rarity {
 			filteredItems = append(filteredItems, item)
 		}
 	}
 	
-	return filteredItems, nil
+	return filteredItems
 }
\ No newline at end of file
@@ -0,0 +1,134 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrGachaRestricted is returned when a regional policy does not let the user spin
+var ErrGachaRestricted = errors.New("gacha is not available")
+
+// GachaPolicy is what one jurisdiction allows
+type GachaPolicy struct {
+	Region string // ISO 3166-1 alpha-2 code; empty for the default policy
+	// Blocked disables the gacha entirely
+	Blocked bool
+	// RequireRateDisclosure only allows spins while the odds are published
+	RequireRateDisclosure bool
+	// MinAge requires a verified age of at least MinAge when positive
+	MinAge int
+}
+
+func (p GachaPolicy) Validate() error {
+	if p.Region != "" && !IsRegionCode(p.Region) {
+		return fmt.Errorf("invalid region code %q", p.Region)
+	}
+	if p.MinAge < 0 {
+		return fmt.Errorf("minimum age for %q cannot be negative", p.Region)
+	}
+	return nil
+}
+
+// CheckAccess reports why the user may not spin under this policy, if anything
+func (p GachaPolicy) CheckAccess(user *User, now time.Time) error {
+	if p.Blocked {
+		if p.Region == "" {
+			return fmt.Errorf("%w until the user's region is verified", ErrGachaRestricted)
+		}
+		return fmt.Errorf("%w in region %s", ErrGachaRestricted, p.Region)
+	}
+	if p.MinAge > 0 {
+		age, verified := user.VerifiedAge(now)
+		if !verified {
+			return fmt.Errorf("%w: age verification is required", ErrGachaRestricted)
+		}
+		if age < p.MinAge {
+			return fmt.Errorf("%w: users must be at least %d", ErrGachaRestricted, p.MinAge)
+		}
+	}
+	return nil
+}
+
+// GachaPolicies maps regions to their policy. Verified regions without a
+// policy get the default; users whose region is not verified get the
+// unverified policy, so that not declaring a region never loosens the rules.
+type GachaPolicies struct {
+	defaultPolicy    GachaPolicy
+	unverifiedPolicy GachaPolicy
+	regions          map[string]GachaPolicy
+}
+
+// NewGachaPolicies validates the policies. Each regional policy replaces
+// the default as a whole rather than overriding single fields. Without an
+// unverified policy, unverified users get every restriction of the default
+// and regional policies combined.
+func NewGachaPolicies(defaultPolicy GachaPolicy, unverified *GachaPolicy, regional []GachaPolicy) (*GachaPolicies, error) {
+	defaultPolicy.Region = ""
+	if err := defaultPolicy.Validate(); err != nil {
+		return nil, err
+	}
+
+	policies := &GachaPolicies{
+		defaultPolicy: defaultPolicy,
+		regions:       make(map[string]GachaPolicy, len(regional)),
+	}
+	for _, policy := range regional {
+		if policy.Region == "" {
+			return nil, errors.New("regional policies need a region code")
+		}
+		if err := policy.Validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := policies.regions[policy.Region]; ok {
+			return nil, fmt.Errorf("duplicate policy for region %s", policy.Region)
+		}
+		policies.regions[policy.Region] = policy
+	}
+
+	if unverified != nil {
+		policies.unverifiedPolicy = *unverified
+		policies.unverifiedPolicy.Region = ""
+		if err := policies.unverifiedPolicy.Validate(); err != nil {
+			return nil, err
+		}
+	} else {
+		policies.unverifiedPolicy = strictestGachaPolicy(defaultPolicy, regional)
+	}
+	return policies, nil
+}
+
+// strictestGachaPolicy combines the restrictions of every policy
+func strictestGachaPolicy(defaultPolicy GachaPolicy, regional []GachaPolicy) GachaPolicy {
+	strictest := defaultPolicy
+	for _, policy := range regional {
+		strictest.Blocked = strictest.Blocked || policy.Blocked
+		strictest.RequireRateDisclosure = strictest.RequireRateDisclosure || policy.RequireRateDisclosure
+		if policy.MinAge > strictest.MinAge {
+			strictest.MinAge = policy.MinAge
+		}
+	}
+	strictest.Region = ""
+	return strictest
+}
+
+// DefaultGachaPolicies allows everything everywhere, for running without a policy file
+func DefaultGachaPolicies() *GachaPolicies {
+	return &GachaPolicies{
+		regions: map[string]GachaPolicy{},
+	}
+}
+
+// For returns the policy in effect for a verified region; an empty region
+// gets the unverified policy
+func (p *GachaPolicies) For(region string) GachaPolicy {
+	if region == "" {
+		return p.unverifiedPolicy
+	}
+	if policy, ok := p.regions[region]; ok {
+		return policy
+	}
+	policy := p.defaultPolicy
+	policy.Region = region
+	return policy
+}
@@ -0,0 +1,149 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+)
+
+// RarityRate is how often a pool draws a rarity. Within the rarity, the
+// featured (rate-up) items share FeaturedShare of its draws equally and the
+// other items share the rest equally.
+type RarityRate struct {
+	Rarity        Rarity
+	Weight        int // parts per million of spins that draw this rarity
+	FeaturedIDs   []int
+	FeaturedShare int // parts per million of this rarity's draws
+}
+
+// GachaPool is configured rarity first: a spin draws a rarity by its rate and
+// then an item of that rarity
+type GachaPool struct {
+	Rates []RarityRate
+	// Items have the weights the two stages give them, derived by NewGachaPool
+	Items []GachaItem
+}
+
+// NewGachaPool validates the rates and items against the rarity tiers and
+// derives each item's weight. Rates must split into whole parts per million
+// per item so the odds can be disclosed exactly.
+func NewGachaPool(tiers *RarityRegistry, rates []RarityRate, items []GachaItem) (*GachaPool, error) {
+	if len(rates) == 0 {
+		return nil, errors.New("gacha pool must have rarity rates")
+	}
+
+	byRarity := make(map[Rarity][]int) // item indexes
+	ids := make(map[int]bool)
+	for i, item := range items {
+		if ids[item.ID] {
+			return nil, fmt.Errorf("duplicate gacha item ID %d", item.ID)
+		}
+		ids[item.ID] = true
+		byRarity[item.Rarity] = append(byRarity[item.Rarity], i)
+	}
+
+	pool := &GachaPool{
+		Rates: rates,
+		Items: append([]GachaItem{}, items...),
+	}
+	seen := make(map[Rarity]bool)
+	for _, rate := range rates {
+		if !tiers.IsValid(rate.Rarity) {
+			return nil, errors.New("invalid rarity level")
+		}
+		if seen[rate.Rarity] {
+			return nil, fmt.Errorf("duplicate rate for %s", tiers.Name(rate.Rarity))
+		}
+		seen[rate.Rarity] = true
+
+		if err := pool.deriveWeights(tiers.Name(rate.Rarity), rate, byRarity[rate.Rarity]); err != nil {
+			return nil, err
+		}
+	}
+	for rarity := range byRarity {
+		if !seen[rarity] {
+			return nil, fmt.Errorf("%s items have no rate", tiers.Name(rarity))
+		}
+	}
+
+	// 導出した重みの合計が WeightScale ちょうどになることもここで確かめる
+	if err := ValidateGachaItems(tiers, pool.Items); err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+func (p *GachaPool) deriveWeights(name string, rate RarityRate, indexes []int) error {
+	if rate.Weight < 0 || rate.Weight > WeightScale {
+		return fmt.Errorf("%s rate must be between 0 and %d", name, WeightScale)
+	}
+	if rate.FeaturedShare < 0 || rate.FeaturedShare > WeightScale {
+		return fmt.Errorf("%s featured share must be between 0 and %d", name, WeightScale)
+	}
+	if len(indexes) == 0 {
+		return fmt.Errorf("%s has a rate but no items", name)
+	}
+
+	featured := make(map[int]bool)
+	for _, id := range rate.FeaturedIDs {
+		featured[id] = true
+	}
+	var featuredIndexes, otherIndexes []int
+	for _, i := range indexes {
+		if featured[p.Items[i].ID] {
+			featuredIndexes = append(featuredIndexes, i)
+			delete(featured, p.Items[i].ID)
+		} else {
+			otherIndexes = append(otherIndexes, i)
+		}
+	}
+	if len(featured) > 0 {
+		return fmt.Errorf("featured items of %s must be %s items of the pool", name, name)
+	}
+	if len(featuredIndexes) == 0 && rate.FeaturedShare > 0 {
+		return fmt.Errorf("%s has a featured share but no featured items", name)
+	}
+	if len(otherIndexes) == 0 && rate.FeaturedShare < WeightScale {
+		return fmt.Errorf("%s items are all featured, so the featured share must be %d", name, WeightScale)
+	}
+
+	featuredTotal := int64(rate.Weight) * int64(rate.FeaturedShare)
+	otherTotal := int64(rate.Weight)*WeightScale - featuredTotal
+	if err := p.splitWeight(name, featuredIndexes, featuredTotal); err != nil {
+		return err
+	}
+	return p.splitWeight(name, otherIndexes, otherTotal)
+}
+
+// splitWeight shares total, in millionths of a part per million, equally among items
+func (p *GachaPool) splitWeight(name string, indexes []int, total int64) error {
+	if len(indexes) == 0 {
+		return nil
+	}
+	share := int64(len(indexes)) * WeightScale
+	if total%share != 0 {
+		return fmt.Errorf("%s rate does not split into whole parts per million per item", name)
+	}
+	for _, i := range indexes {
+		p.Items[i].Weight = int(total / share)
+	}
+	return nil
+}
+
+// IsFeatured reports whether an item is a rate-up item of its rarity
+func (p *GachaPool) IsFeatured(item GachaItem) bool {
+	return isFeatured(p.Rates, item)
+}
+
+func isFeatured(rates []RarityRate, item GachaItem) bool {
+	for _, rate := range rates {
+		if rate.Rarity != item.Rarity {
+			continue
+		}
+		for _, id := range rate.FeaturedIDs {
+			if id == item.ID {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package model
+
+import "math"
+
+const (
+	// StatsConfidenceLevel is the confidence level of the reported intervals
+	StatsConfidenceLevel = 0.95
+	// statsZScore is the two-sided standard normal quantile for StatsConfidenceLevel
+	statsZScore = 1.959963984540054
+)
+
+// RateStat compares a configured probability with the frequency observed in
+// SampleSize spins
+type RateStat struct {
+	Configured float64
+	Count      int
+	SampleSize int
+	Observed   float64
+	// Lower and Upper bound the Wilson score interval of the observed rate
+	Lower float64
+	Upper float64
+}
+
+func NewRateStat(configured float64, count, sampleSize int) RateStat {
+	stat := RateStat{
+		Configured: configured,
+		Count:      count,
+		SampleSize: sampleSize,
+		Upper:      1,
+	}
+	if sampleSize > 0 {
+		stat.Observed = float64(count) / float64(sampleSize)
+		stat.Lower, stat.Upper = WilsonInterval(count, sampleSize, statsZScore)
+	}
+	return stat
+}
+
+// Consistent reports whether the configured rate lies within the interval,
+// i.e. the observations give no evidence against it
+func (s RateStat) Consistent() bool {
+	return s.Configured >= s.Lower && s.Configured <= s.Upper
+}
+
+// WilsonInterval returns the Wilson score interval for successes out of n
+// trials. Unlike the normal approximation it stays within [0, 1] and behaves
+// well for rare outcomes and small samples.
+func WilsonInterval(successes, n int, z float64) (float64, float64) {
+	if n <= 0 {
+		return 0, 1
+	}
+	p := float64(successes) / float64(n)
+	trials := float64(n)
+	z2 := z * z
+
+	center := (p + z2/(2*trials)) / (1 + z2/trials)
+	margin := z / (1 + z2/trials) * math.Sqrt(p*(1-p)/trials+z2/(4*trials*trials))
+	return math.Max(0, center-margin), math.Min(1, center+margin)
+}
+
+type ItemRateStat struct {
+	Item GachaItem
+	RateStat
+}
+
+type RarityRateStat struct {
+	Rarity Rarity
+	RateStat
+}
+
+// GachaStats is the observed distribution of a set of spins
+type GachaStats struct {
+	SampleSize int
+	Items      []ItemRateStat
+	Rarities   []RarityRateStat
+}
+
+// BuildGachaStats compares the configured items with result counts keyed by
+// item ID, listing rarities in the tiers' rank order. Results for items no
+// longer configured only count toward the sample size.
+func BuildGachaStats(tiers *RarityRegistry, items []GachaItem, countsByItem map[int]int) GachaStats {
+	stats := GachaStats{}
+	for _, count := range countsByItem {
+		stats.SampleSize += count
+	}
+
+	rarityWeight := make(map[Rarity]int)
+	rarityCount := make(map[Rarity]int)
+	for _, item := range items {
+		count := countsByItem[item.ID]
+		stats.Items = append(stats.Items, ItemRateStat{
+			Item:     item,
+			RateStat: NewRateStat(item.Probability(), count, stats.SampleSize),
+		})
+		rarityWeight[item.Rarity] += item.Weight
+		rarityCount[item.Rarity] += count
+	}
+
+	for _, tier := range tiers.Tiers() {
+		rarity := tier.Rarity
+		if _, ok := rarityWeight[rarity]; !ok {
+			continue
+		}
+		stats.Rarities = append(stats.Rarities, RarityRateStat{
+			Rarity:   rarity,
+			RateStat: NewRateStat(float64(rarityWeight[rarity])/WeightScale, rarityCount[rarity], stats.SampleSize),
+		})
+	}
+
+	return stats
+}
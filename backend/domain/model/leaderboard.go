@@ -0,0 +1,124 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+type LeaderboardMetric string
+
+const (
+	LeaderboardMetricBalance        LeaderboardMetric = "balance"
+	LeaderboardMetricGachaPoints    LeaderboardMetric = "gacha_points"
+	LeaderboardMetricLegendaryPulls LeaderboardMetric = "legendary_pulls"
+)
+
+// IsValid checks if the metric is known
+func (m LeaderboardMetric) IsValid() bool {
+	switch m {
+	case LeaderboardMetricBalance, LeaderboardMetricGachaPoints, LeaderboardMetricLegendaryPulls:
+		return true
+	default:
+		return false
+	}
+}
+
+type LeaderboardPeriod string
+
+const (
+	LeaderboardPeriodAllTime LeaderboardPeriod = "all_time"
+	LeaderboardPeriodWeekly  LeaderboardPeriod = "weekly"
+	LeaderboardPeriodDaily   LeaderboardPeriod = "daily"
+)
+
+// LeaderboardPeriods lists every ranking window
+func LeaderboardPeriods() []LeaderboardPeriod {
+	return []LeaderboardPeriod{LeaderboardPeriodAllTime, LeaderboardPeriodWeekly, LeaderboardPeriodDaily}
+}
+
+// IsValid checks if the period is known
+func (p LeaderboardPeriod) IsValid() bool {
+	switch p {
+	case LeaderboardPeriodAllTime, LeaderboardPeriodWeekly, LeaderboardPeriodDaily:
+		return true
+	default:
+		return false
+	}
+}
+
+// Start returns the beginning of the window containing t: the same UTC day
+// and week as the spending caps, and the epoch for all-time.
+func (p LeaderboardPeriod) Start(t time.Time) time.Time {
+	switch p {
+	case LeaderboardPeriodDaily:
+		return StartOfDay(t)
+	case LeaderboardPeriodWeekly:
+		return StartOfWeek(t)
+	default:
+		return time.Unix(0, 0).UTC()
+	}
+}
+
+// ValidateLeaderboard checks that the metric can be ranked over the period.
+// Every metric has every period: all-time balance ranks the current balance
+// and weekly or daily balance the net change over the window.
+func ValidateLeaderboard(metric LeaderboardMetric, period LeaderboardPeriod) error {
+	if !metric.IsValid() {
+		return errors.New("invalid leaderboard metric")
+	}
+	if !period.IsValid() {
+		return errors.New("invalid leaderboard period")
+	}
+	return nil
+}
+
+// LeaderboardScoreDelta is an incremental change to a materialized score
+type LeaderboardScoreDelta struct {
+	Metric      LeaderboardMetric
+	Period      LeaderboardPeriod
+	PeriodStart time.Time
+	UserID      int
+	Delta       int
+}
+
+// LeaderboardDeltas returns the score changes caused by a gacha result
+func LeaderboardDeltas(result *GachaResult) []LeaderboardScoreDelta {
+	var deltas []LeaderboardScoreDelta
+	for _, period := range LeaderboardPeriods() {
+		start := period.Start(result.CreatedAt)
+		deltas = append(deltas, LeaderboardScoreDelta{
+			Metric:      LeaderboardMetricGachaPoints,
+			Period:      period,
+			PeriodStart: start,
+			UserID:      result.UserID,
+			Delta:       result.PointsEarned,
+		})
+		if result.Rarity == RarityLegendary {
+			deltas = append(deltas, LeaderboardScoreDelta{
+				Metric:      LeaderboardMetricLegendaryPulls,
+				Period:      period,
+				PeriodStart: start,
+				UserID:      result.UserID,
+				Delta:       1,
+			})
+		}
+	}
+	return deltas
+}
+
+// LeaderboardEntry is a ranked user. Tied scores share a rank.
+type LeaderboardEntry struct {
+	Rank     int
+	UserID   int
+	UserName string
+	Score    int
+}
+
+type Leaderboard struct {
+	Metric      LeaderboardMetric
+	Period      LeaderboardPeriod
+	PeriodStart time.Time
+	Entries     []LeaderboardEntry
+	// Caller is the requesting user's position, even outside the top entries
+	Caller *LeaderboardEntry
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LedgerAccount identifies one side of a double-entry posting.
+// User wallets are "user:<id>"; everything else is a system account.
+type LedgerAccount string
+
+const (
+	AccountGachaHouse       LedgerAccount = "system:gacha_house"
+	AccountShop             LedgerAccount = "system:shop"
+	AccountTransferClearing LedgerAccount = "system:transfer_clearing"
+	AccountExpired          LedgerAccount = "system:expired"
+	AccountAdjustments      LedgerAccount = "system:adjustments"
+	AccountAchievements     LedgerAccount = "system:achievements"
+)
+
+const userAccountPrefix = "user:"
+
+// UserWalletAccount returns the ledger account holding a user's points
+func UserWalletAccount(userID int) LedgerAccount {
+	return LedgerAccount(userAccountPrefix + strconv.Itoa(userID))
+}
+
+// UserID returns the owner of a user wallet account
+func (a LedgerAccount) UserID() (int, bool) {
+	if !strings.HasPrefix(string(a), userAccountPrefix) {
+		return 0, false
+	}
+	userID, err := strconv.Atoi(strings.TrimPrefix(string(a), userAccountPrefix))
+	if err != nil {
+		return 0, false
+	}
+	return userID, true
+}
+
+// LedgerEntry is one leg of a point movement. Positive amounts add points to
+// the account, negative amounts remove them; the legs of a transaction sum to zero.
+type LedgerEntry struct {
+	ID            int
+	TransactionID int
+	Account       LedgerAccount
+	Amount        int
+	CreatedAt     time.Time
+}
+
+// CounterAccount returns the system account on the other side of a user's transaction
+func (t TransactionType) CounterAccount() LedgerAccount {
+	switch t {
+	case TransactionTypeGacha:
+		return AccountGachaHouse
+	case TransactionTypeSpend:
+		return AccountShop
+	case TransactionTypeTransferOut, TransactionTypeTransferIn:
+		return AccountTransferClearing
+	case TransactionTypeExpire:
+		return AccountExpired
+	case TransactionTypeAchievement:
+		return AccountAchievements
+	default:
+		return AccountAdjustments
+	}
+}
+
+// LedgerEntries returns the balanced postings for the transaction: the user
+// wallet moves by Amount and the counter account by the opposite.
+func (pt *PointTransaction) LedgerEntries() []LedgerEntry {
+	return []LedgerEntry{
+		{TransactionID: pt.ID, Account: UserWalletAccount(pt.UserID), Amount: pt.Amount, CreatedAt: pt.CreatedAt},
+		{TransactionID: pt.ID, Account: pt.Type.CounterAccount(), Amount: -pt.Amount, CreatedAt: pt.CreatedAt},
+	}
+}
+
+// CheckLedgerBalanced verifies the double-entry invariant: every
+// transaction's entries sum to zero
+func CheckLedgerBalanced(entries []LedgerEntry) error {
+	sums := make(map[int]int)
+	for _, entry := range entries {
+		sums[entry.TransactionID] += entry.Amount
+	}
+	for transactionID, sum := range sums {
+		if sum != 0 {
+			return fmt.Errorf("ledger entries for transaction %d sum to %d", transactionID, sum)
+		}
+	}
+	return nil
+}
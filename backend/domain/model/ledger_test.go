@@ -0,0 +1,96 @@
+package model
+
+import "testing"
+
+// ledgerTransactions builds one transaction of every type the application
+// records, the way the use cases build them
+func ledgerTransactions(t *testing.T) []*PointTransaction {
+	t.Helper()
+
+	var transactions []*PointTransaction
+	add := func(transaction *PointTransaction, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("building transaction: %v", err)
+		}
+		transaction.ID = len(transactions) + 1
+		transactions = append(transactions, transaction)
+	}
+
+	add(NewPointTransaction(1, 500, TransactionTypeGacha, "Gacha reward: Gold Coin"))
+	add(NewPointTransaction(1, 120, TransactionTypeSpend, "Fortune telling"))
+	add(NewPointTransaction(2, 300, TransactionTypeAchievement, "Achievement: First spin"))
+	add(NewExpireTransaction(2, 40))
+	add(NewAdjustmentTransaction(3, -15, "Reconciliation"))
+
+	transfer, err := NewPointTransfer(1, 2, 200, "Team gift")
+	if err != nil {
+		t.Fatalf("building transfer: %v", err)
+	}
+	out, in, err := transfer.Transactions()
+	add(out, err)
+	add(in, err)
+
+	for _, amount := range []int{2500, -800, MaxTransactionAmount + 1} {
+		adjustment, err := NewPointAdjustment(3, amount, AdjustmentReasonIncident, "Double charge", "ops", true)
+		if err != nil {
+			t.Fatalf("building adjustment: %v", err)
+		}
+		add(adjustment.Transaction(), nil)
+	}
+
+	return transactions
+}
+
+func TestLedgerEntriesPostToCounterAccounts(t *testing.T) {
+	covered := make(map[TransactionType]bool)
+	for _, transaction := range ledgerTransactions(t) {
+		covered[transaction.Type] = true
+
+		entries := transaction.LedgerEntries()
+		if len(entries) != 2 {
+			t.Fatalf("%s: got %d entries, want 2", transaction.Type, len(entries))
+		}
+		wallet, counter := entries[0], entries[1]
+		if wallet.Account != UserWalletAccount(transaction.UserID) || wallet.Amount != transaction.Amount {
+			t.Errorf("%s: wallet entry %+v does not move user %d by %d", transaction.Type, wallet, transaction.UserID, transaction.Amount)
+		}
+		if counter.Account != transaction.Type.CounterAccount() {
+			t.Errorf("%s: counter entry posted to %s, want %s", transaction.Type, counter.Account, transaction.Type.CounterAccount())
+		}
+		if _, ok := counter.Account.UserID(); ok {
+			t.Errorf("%s: counter account %s is a user wallet", transaction.Type, counter.Account)
+		}
+		if transaction.Type.IsDebit() && transaction.Amount >= 0 {
+			t.Errorf("%s: debit stored with non-negative amount %d", transaction.Type, transaction.Amount)
+		}
+	}
+
+	for _, transactionType := range []TransactionType{
+		TransactionTypeGacha,
+		TransactionTypeSpend,
+		TransactionTypeTransferOut,
+		TransactionTypeTransferIn,
+		TransactionTypeExpire,
+		TransactionTypeAdjustment,
+		TransactionTypeAdminAdjustment,
+		TransactionTypeAchievement,
+	} {
+		if !transactionType.IsValid() {
+			t.Errorf("%s is not a valid transaction type", transactionType)
+		}
+		if !covered[transactionType] {
+			t.Errorf("%s is not covered by the ledger test", transactionType)
+		}
+	}
+}
+
+func TestCheckLedgerBalancedRejectsUnbalancedEntries(t *testing.T) {
+	entries := []LedgerEntry{
+		{TransactionID: 1, Account: UserWalletAccount(1), Amount: 100},
+		{TransactionID: 1, Account: AccountGachaHouse, Amount: -90},
+	}
+	if err := CheckLedgerBalanced(entries); err == nil {
+		t.Fatal("unbalanced transaction was accepted")
+	}
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	// MaxOutboxAttempts is how many deliveries are tried before an event is marked failed
+	MaxOutboxAttempts = 10
+
+	outboxBaseRetryDelay = time.Second
+	outboxMaxRetryDelay  = 10 * time.Minute
+)
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending   OutboxStatus = "pending"
+	OutboxStatusDelivered OutboxStatus = "delivered"
+	OutboxStatusFailed    OutboxStatus = "failed"
+)
+
+// OutboxEvent is a domain event stored in the same transaction as the change
+// it describes, waiting to be delivered to subscribers
+type OutboxEvent struct {
+	ID            int64
+	Name          string
+	Payload       []byte // JSON encoded event
+	Status        OutboxStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     string
+	CreatedAt     time.Time
+	DeliveredAt   *time.Time
+}
+
+func NewOutboxEvent(name string, payload []byte) (*OutboxEvent, error) {
+	if name == "" {
+		return nil, errors.New("event name is required")
+	}
+	if len(payload) == 0 {
+		return nil, errors.New("event payload is required")
+	}
+
+	now := time.Now()
+	return &OutboxEvent{
+		Name:          name,
+		Payload:       payload,
+		Status:        OutboxStatusPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// MarkDelivered records a successful delivery
+func (e *OutboxEvent) MarkDelivered(now time.Time) {
+	e.Status = OutboxStatusDelivered
+	e.Attempts++
+	e.LastError = ""
+	e.DeliveredAt = &now
+}
+
+// MarkAttemptFailed records a failed delivery and schedules the next attempt
+// with exponential backoff, giving up after MaxOutboxAttempts
+func (e *OutboxEvent) MarkAttemptFailed(now time.Time, cause error) {
+	e.Attempts++
+	e.LastError = cause.Error()
+	if e.Attempts >= MaxOutboxAttempts {
+		e.Status = OutboxStatusFailed
+		return
+	}
+	e.NextAttemptAt = now.Add(OutboxRetryDelay(e.Attempts))
+}
+
+// OutboxRetryDelay is the wait before retrying after the given number of failed attempts
+func OutboxRetryDelay(attempts int) time.Duration {
+	return ExponentialBackoff(attempts, outboxBaseRetryDelay, outboxMaxRetryDelay)
+}
+
+// OutboxStats summarizes the state of the outbox for monitoring
+type OutboxStats struct {
+	Pending        int
+	Delivered      int
+	Failed         int
+	OldestPending  *time.Time
+	RecentFailures []*OutboxEvent
+}
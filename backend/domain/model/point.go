@@ -2,6 +2,7 @@ package model
 
 import (
 	"errors"
+	"strings"
 	"time"
 )
 
@@ -10,31 +11,104 @@ const (
 	MaxPointBalance     = 1000000  // Maximum points a user can hold
 	MinTransactionAmount = 1       // Minimum transaction amount
 	MaxTransactionAmount = 10000   // Maximum single transaction amount
+	MaxDailyTransferAmount = 20000 // Maximum points a user can send per day
 )
 
 type UserPoint struct {
-	ID        int
-	UserID    int
-	Balance   int
-	UpdatedAt time.Time
+	ID      int
+	UserID  int
+	Balance int
+	// FrozenAt is set while an admin has frozen the account; the balance can
+	// then only change through admin adjustments and expiry
+	FrozenAt     *time.Time
+	FrozenReason string
+	UpdatedAt    time.Time
 }
 
+// ErrPointsFrozen is returned for point movements on a frozen account
+var ErrPointsFrozen = errors.New("point account is frozen")
+
+// ErrAlreadyFrozen is returned when freezing an account that is already frozen
+var ErrAlreadyFrozen = errors.New("point account is already frozen")
+
+// ErrInsufficientPoints is returned when a debit is larger than the balance
+var ErrInsufficientPoints = errors.New("insufficient points")
+
+// ErrMaxBalanceExceeded is returned when a credit would take the balance over MaxPointBalance
+var ErrMaxBalanceExceeded = errors.New("transaction would exceed maximum point balance")
+
 type PointTransaction struct {
-	ID          int
-	UserID      int
-	Amount      int
-	Type        TransactionType
-	Description string
-	CreatedAt   time.Time
+	ID           int
+	UserID       int
+	Amount       int // signed: positive credits the user's wallet, negative debits it
+	Type         TransactionType
+	Description  string
+	TransferID   string // set only for transfer_out / transfer_in pairs
+	BalanceAfter int    // the user's balance once the transaction is applied
+	CreatedAt    time.Time
 }
 
 type TransactionType string
 
 const (
-	TransactionTypeGacha TransactionType = "gacha"
-	TransactionTypeSpend TransactionType = "spend"
+	TransactionTypeGacha           TransactionType = "gacha"
+	TransactionTypeSpend           TransactionType = "spend"
+	TransactionTypeTransferOut     TransactionType = "transfer_out"
+	TransactionTypeTransferIn      TransactionType = "transfer_in"
+	TransactionTypeExpire          TransactionType = "expire"
+	TransactionTypeAdjustment      TransactionType = "adjustment"
+	TransactionTypeAdminAdjustment TransactionType = "admin_adjustment"
+	TransactionTypeAchievement     TransactionType = "achievement"
 )
 
+// IsValid checks if the transaction type is known
+func (t TransactionType) IsValid() bool {
+	switch t {
+	case TransactionTypeGacha, TransactionTypeSpend, TransactionTypeTransferOut, TransactionTypeTransferIn, TransactionTypeExpire,
+		TransactionTypeAdjustment, TransactionTypeAdminAdjustment, TransactionTypeAchievement:
+		return true
+	default:
+		return false
+	}
+}
+
+// IsSigned reports whether the caller supplies the direction of the amount
+func (t TransactionType) IsSigned() bool {
+	return t == TransactionTypeAdjustment || t == TransactionTypeAdminAdjustment
+}
+
+// IsDebit reports whether transactions of this type take points away from the user.
+// Signed types carry their own direction instead.
+func (t TransactionType) IsDebit() bool {
+	switch t {
+	case TransactionTypeSpend, TransactionTypeTransferOut, TransactionTypeExpire:
+		return true
+	default:
+		return false
+	}
+}
+
+// RequiresActiveAccount reports whether a frozen or banned account is kept
+// out of transactions of this type. It covers what users do themselves and
+// transfers they receive; expiries, achievement rewards and adjustments
+// still apply to a suspended account.
+func (t TransactionType) RequiresActiveAccount() bool {
+	switch t {
+	case TransactionTypeGacha, TransactionTypeSpend, TransactionTypeTransferOut, TransactionTypeTransferIn:
+		return true
+	default:
+		return false
+	}
+}
+
+// Magnitude returns the number of points moved regardless of direction
+func (pt *PointTransaction) Magnitude() int {
+	if pt.Amount < 0 {
+		return -pt.Amount
+	}
+	return pt.Amount
+}
+
 // NewUserPoint creates a new UserPoint with validation
 func NewUserPoint(userID int) (*UserPoint, error) {
 	if userID <= 0 {
@@ -50,6 +124,10 @@ func NewUserPoint(userID int) (*UserPoint, error) {
 
 // AddPoints adds points with business rule validation
 func (up *UserPoint) AddPoints(amount int) error {
+	if up.IsFrozen() {
+		return ErrPointsFrozen
+	}
+
 	if amount <= 0 {
 		return errors.New("amount must be positive")
 	}
@@ -64,7 +142,7 @@ func (up *UserPoint) AddPoints(amount int) error {
 	
 	newBalance := up.Balance + amount
 	if newBalance > MaxPointBalance {
-		return errors.New("transaction would exceed maximum point balance")
+		return ErrMaxBalanceExceeded
 	}
 	
 	up.Balance = newBalance
@@ -74,6 +152,10 @@ func (up *UserPoint) AddPoints(amount int) error {
 
 // SpendPoints spends points with business rule validation
 func (up *UserPoint) SpendPoints(amount int) error {
+	if up.IsFrozen() {
+		return ErrPointsFrozen
+	}
+
 	if amount <= 0 {
 		return errors.New("amount must be positive")
 	}
@@ -87,7 +169,7 @@ func (up *UserPoint) SpendPoints(amount int) error {
 	}
 	
 	if up.Balance < amount {
-		return errors.New("insufficient points")
+		return ErrInsufficientPoints
 	}
 	
 	up.Balance -= amount
@@ -95,6 +177,80 @@ func (up *UserPoint) SpendPoints(amount int) error {
 	return nil
 }
 
+// ExpirePoints removes expired points from the balance. The single
+// transaction limit does not apply since a whole lot may expire at once.
+// It returns the amount actually removed, which never exceeds the balance.
+func (up *UserPoint) ExpirePoints(amount int) int {
+	if amount <= 0 {
+		return 0
+	}
+
+	if amount > up.Balance {
+		amount = up.Balance
+	}
+
+	up.Balance -= amount
+	up.UpdatedAt = time.Now()
+	return amount
+}
+
+// Adjust applies a signed amount. MaxTransactionAmount applies unless
+// overrideLimit is set; the balance must stay within 0 and MaxPointBalance.
+func (up *UserPoint) Adjust(amount int, overrideLimit bool) error {
+	if amount == 0 {
+		return errors.New("amount cannot be zero")
+	}
+	
+	magnitude := amount
+	if magnitude < 0 {
+		magnitude = -magnitude
+	}
+	if magnitude > MaxTransactionAmount && !overrideLimit {
+		return ErrAdjustmentOverLimit
+	}
+	
+	newBalance := up.Balance + amount
+	if newBalance < 0 {
+		return ErrInsufficientPoints
+	}
+	if newBalance > MaxPointBalance {
+		return ErrMaxBalanceExceeded
+	}
+	
+	up.Balance = newBalance
+	up.UpdatedAt = time.Now()
+	return nil
+}
+
+// Freeze stops the user from earning, spending or transferring points
+func (up *UserPoint) Freeze(reason string, now time.Time) error {
+	if up.IsFrozen() {
+		return ErrAlreadyFrozen
+	}
+	if strings.TrimSpace(reason) == "" {
+		return errors.New("freeze reason is required")
+	}
+	up.FrozenAt = &now
+	up.FrozenReason = strings.TrimSpace(reason)
+	up.UpdatedAt = now
+	return nil
+}
+
+// Unfreeze lifts a freeze
+func (up *UserPoint) Unfreeze(now time.Time) error {
+	if !up.IsFrozen() {
+		return errors.New("point account is not frozen")
+	}
+	up.FrozenAt = nil
+	up.FrozenReason = ""
+	up.UpdatedAt = now
+	return nil
+}
+
+func (up *UserPoint) IsFrozen() bool {
+	return up.FrozenAt != nil
+}
+
 // CanAfford checks if the user can afford a specific amount
 func (up *UserPoint) CanAfford(amount int) bool {
 	return up.Balance >= amount && amount > 0
@@ -114,7 +270,25 @@ func (up *UserPoint) GetPointLevel() string {
 	}
 }
 
-// NewPointTransaction creates a new point transaction with validation
+// PointLevelRank orders the levels returned by GetPointLevel, Bronze lowest.
+// Unknown levels rank 0.
+func PointLevelRank(level string) int {
+	switch level {
+	case "Bronze":
+		return 1
+	case "Silver":
+		return 2
+	case "Gold":
+		return 3
+	case "Diamond":
+		return 4
+	default:
+		return 0
+	}
+}
+
+// NewPointTransaction creates a new point transaction with validation.
+// amount is the number of points moved; it is stored negated for debit types.
 func NewPointTransaction(userID int, amount int, transactionType TransactionType, description string) (*PointTransaction, error) {
 	if userID <= 0 {
 		return nil, errors.New("user ID must be positive")
@@ -128,7 +302,7 @@ func NewPointTransaction(userID int, amount int, transactionType TransactionType
 		return nil, errors.New("transaction amount is outside allowed range")
 	}
 	
-	if transactionType != TransactionTypeGacha && transactionType != TransactionTypeSpend {
+	if !transactionType.IsValid() || transactionType.IsSigned() {
 		return nil, errors.New("invalid transaction type")
 	}
 	
@@ -136,6 +310,10 @@ func NewPointTransaction(userID int, amount int, transactionType TransactionType
 		return nil, errors.New("transaction description cannot be empty")
 	}
 	
+	if transactionType.IsDebit() {
+		amount = -amount
+	}
+	
 	return &PointTransaction{
 		UserID:      userID,
 		Amount:      amount,
@@ -143,4 +321,48 @@ func NewPointTransaction(userID int, amount int, transactionType TransactionType
 		Description: description,
 		CreatedAt:   time.Now(),
 	}, nil
+}
+
+// NewExpireTransaction creates the ledger row for amount points removed by
+// expiry. Unlike NewPointTransaction it is not bound by MaxTransactionAmount.
+func NewExpireTransaction(userID int, amount int) (*PointTransaction, error) {
+	if userID <= 0 {
+		return nil, errors.New("user ID must be positive")
+	}
+
+	if amount <= 0 {
+		return nil, errors.New("transaction amount must be positive")
+	}
+
+	return &PointTransaction{
+		UserID:      userID,
+		Amount:      -amount,
+		Type:        TransactionTypeExpire,
+		Description: "Points expired",
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// NewAdjustmentTransaction creates a corrective ledger row. The amount is
+// signed: positive adds to the ledger balance, negative removes from it.
+func NewAdjustmentTransaction(userID int, amount int, description string) (*PointTransaction, error) {
+	if userID <= 0 {
+		return nil, errors.New("user ID must be positive")
+	}
+
+	if amount == 0 {
+		return nil, errors.New("adjustment amount cannot be zero")
+	}
+
+	if description == "" {
+		return nil, errors.New("transaction description cannot be empty")
+	}
+
+	return &PointTransaction{
+		UserID:      userID,
+		Amount:      amount,
+		Type:        TransactionTypeAdjustment,
+		Description: description,
+		CreatedAt:   time.Now(),
+	}, nil
 }
\ No newline at end of file
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// AdjustmentReasonCode classifies why support staff changed a balance
+type AdjustmentReasonCode string
+
+const (
+	AdjustmentReasonIncident   AdjustmentReasonCode = "incident_compensation"
+	AdjustmentReasonGoodwill   AdjustmentReasonCode = "goodwill"
+	AdjustmentReasonCorrection AdjustmentReasonCode = "correction"
+	AdjustmentReasonFraud      AdjustmentReasonCode = "fraud_reversal"
+	AdjustmentReasonOther      AdjustmentReasonCode = "other"
+)
+
+// IsValid checks if the reason code is known
+func (c AdjustmentReasonCode) IsValid() bool {
+	switch c {
+	case AdjustmentReasonIncident, AdjustmentReasonGoodwill, AdjustmentReasonCorrection, AdjustmentReasonFraud, AdjustmentReasonOther:
+		return true
+	default:
+		return false
+	}
+}
+
+var (
+	// ErrInvalidAdjustment is returned for adjustments that fail validation
+	ErrInvalidAdjustment = errors.New("invalid adjustment")
+	// ErrAdjustmentOverLimit is returned for adjustments above
+	// MaxTransactionAmount made without the override
+	ErrAdjustmentOverLimit = errors.New("amount exceeds maximum transaction amount; set override to confirm")
+)
+
+// PointAdjustment is a manual balance change made by an admin
+type PointAdjustment struct {
+	ID            int
+	TransactionID int
+	UserID        int
+	Amount        int // signed
+	ReasonCode    AdjustmentReasonCode
+	Reason        string
+	AdminID       string
+	LimitOverride bool
+	CreatedAt     time.Time
+}
+
+// NewPointAdjustment creates a new adjustment with validation. Amounts
+// above MaxTransactionAmount are only accepted with overrideLimit set.
+func NewPointAdjustment(userID, amount int, reasonCode AdjustmentReasonCode, reason, adminID string, overrideLimit bool) (*PointAdjustment, error) {
+	if userID <= 0 {
+		return nil, fmt.Errorf("%w: user ID must be positive", ErrInvalidAdjustment)
+	}
+
+	if amount == 0 {
+		return nil, fmt.Errorf("%w: adjustment amount cannot be zero", ErrInvalidAdjustment)
+	}
+
+	if !reasonCode.IsValid() {
+		return nil, fmt.Errorf("%w: invalid adjustment reason code", ErrInvalidAdjustment)
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, fmt.Errorf("%w: adjustment reason is required", ErrInvalidAdjustment)
+	}
+	if len(reason) > 500 {
+		return nil, fmt.Errorf("%w: adjustment reason must be at most 500 characters long", ErrInvalidAdjustment)
+	}
+
+	if strings.TrimSpace(adminID) == "" {
+		return nil, fmt.Errorf("%w: acting admin is required", ErrInvalidAdjustment)
+	}
+
+	magnitude := amount
+	if magnitude < 0 {
+		magnitude = -magnitude
+	}
+	if magnitude > MaxTransactionAmount && !overrideLimit {
+		return nil, ErrAdjustmentOverLimit
+	}
+
+	return &PointAdjustment{
+		UserID:        userID,
+		Amount:        amount,
+		ReasonCode:    reasonCode,
+		Reason:        reason,
+		AdminID:       strings.TrimSpace(adminID),
+		LimitOverride: overrideLimit,
+		CreatedAt:     time.Now(),
+	}, nil
+}
+
+// Transaction returns the ledger row recording the adjustment
+func (a *PointAdjustment) Transaction() *PointTransaction {
+	return &PointTransaction{
+		UserID:      a.UserID,
+		Amount:      a.Amount,
+		Type:        TransactionTypeAdminAdjustment,
+		Description: fmt.Sprintf("Admin adjustment (%s) by %s: %s", a.ReasonCode, a.AdminID, a.Reason),
+		CreatedAt:   a.CreatedAt,
+	}
+}
@@ -0,0 +1,39 @@
+package model
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPointAdjustmentErrorsAreTyped(t *testing.T) {
+	tests := []struct {
+		name       string
+		amount     int
+		reasonCode AdjustmentReasonCode
+		override   bool
+		want       error
+	}{
+		{name: "unknown reason code", amount: 100, reasonCode: "bonus", want: ErrInvalidAdjustment},
+		{name: "zero amount", amount: 0, reasonCode: AdjustmentReasonGoodwill, want: ErrInvalidAdjustment},
+		{name: "over the limit without override", amount: -(MaxTransactionAmount + 1), reasonCode: AdjustmentReasonFraud, want: ErrAdjustmentOverLimit},
+		{name: "over the limit with override", amount: -(MaxTransactionAmount + 1), reasonCode: AdjustmentReasonFraud, override: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPointAdjustment(1, tt.amount, tt.reasonCode, "ticket 42", "ops", tt.override)
+			if !errors.Is(err, tt.want) || (tt.want == nil) != (err == nil) {
+				t.Errorf("NewPointAdjustment = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	// 残高の検証も同じ型で返り、ハンドラーが 409 に振り分ける
+	balance := &UserPoint{UserID: 1, Balance: 50}
+	if err := balance.Adjust(-100, false); !errors.Is(err, ErrInsufficientPoints) {
+		t.Errorf("Adjust below zero = %v, want ErrInsufficientPoints", err)
+	}
+	if err := balance.Adjust(MaxTransactionAmount+1, false); !errors.Is(err, ErrAdjustmentOverLimit) {
+		t.Errorf("Adjust over the limit = %v, want ErrAdjustmentOverLimit", err)
+	}
+}
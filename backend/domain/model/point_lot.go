@@ -0,0 +1,98 @@
+package model
+
+import (
+	"errors"
+	"sort"
+	"time"
+)
+
+// DefaultPointExpiryDays is how long earned points stay spendable
+const DefaultPointExpiryDays = 365
+
+// PointLot is a batch of points earned together that expires as a unit.
+// Spending consumes lots in expiry order (oldest first).
+type PointLot struct {
+	ID            int
+	UserID        int
+	TransactionID int // 0 for lots created from pre-existing balances
+	Amount        int
+	Remaining     int
+	EarnedAt      time.Time
+	ExpiresAt     time.Time
+}
+
+// PointExpiration is an amount of points that expires at a given time
+type PointExpiration struct {
+	Amount    int
+	ExpiresAt time.Time
+}
+
+// NewPointLot creates a new lot with validation
+func NewPointLot(userID, transactionID, amount int, earnedAt, expiresAt time.Time) (*PointLot, error) {
+	if userID <= 0 {
+		return nil, errors.New("user ID must be positive")
+	}
+
+	if amount <= 0 {
+		return nil, errors.New("lot amount must be positive")
+	}
+
+	if !expiresAt.After(earnedAt) {
+		return nil, errors.New("lot must expire after it is earned")
+	}
+
+	return &PointLot{
+		UserID:        userID,
+		TransactionID: transactionID,
+		Amount:        amount,
+		Remaining:     amount,
+		EarnedAt:      earnedAt,
+		ExpiresAt:     expiresAt,
+	}, nil
+}
+
+// IsExpired checks if the lot can no longer be spent
+func (l *PointLot) IsExpired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// ConsumeLots takes amount from the lots, earliest expiry first, and
+// returns what was taken from each lot. Lots are updated in place.
+func ConsumeLots(lots []*PointLot, amount int) ([]PointExpiration, error) {
+	if amount <= 0 {
+		return nil, errors.New("amount must be positive")
+	}
+
+	sort.SliceStable(lots, func(i, j int) bool {
+		return lots[i].ExpiresAt.Before(lots[j].ExpiresAt)
+	})
+
+	available := 0
+	for _, lot := range lots {
+		available += lot.Remaining
+	}
+	if available < amount {
+		return nil, errors.New("insufficient points in lots")
+	}
+
+	var consumed []PointExpiration
+	remaining := amount
+	for _, lot := range lots {
+		if remaining == 0 {
+			break
+		}
+		if lot.Remaining == 0 {
+			continue
+		}
+
+		take := lot.Remaining
+		if take > remaining {
+			take = remaining
+		}
+		lot.Remaining -= take
+		remaining -= take
+		consumed = append(consumed, PointExpiration{Amount: take, ExpiresAt: lot.ExpiresAt})
+	}
+
+	return consumed, nil
+}
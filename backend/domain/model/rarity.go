@@ -0,0 +1,153 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Rarity identifies a rarity tier. It is the value stored in
+// gacha_results.rarity, so a tier's ID is never reused for another tier.
+type Rarity int
+
+// Built-in tiers. Further tiers, such as seasonal ones, are configured as data.
+const (
+	RarityCommon Rarity = iota + 1
+	RarityRare
+	RarityEpic
+	RarityLegendary
+)
+
+// RarityTier describes a rarity: its display name and colour, its rank among
+// the tiers and the points its items may award
+type RarityTier struct {
+	Rarity    Rarity
+	Name      string
+	Rank      int    // higher is rarer
+	Color     string // #rrggbb
+	MinPoints int
+	MaxPoints int
+}
+
+var rarityColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+func (t RarityTier) Validate() error {
+	if t.Rarity <= 0 {
+		return errors.New("rarity ID must be positive")
+	}
+	if strings.TrimSpace(t.Name) == "" {
+		return errors.New("rarity name cannot be empty")
+	}
+	if !rarityColorPattern.MatchString(t.Color) {
+		return fmt.Errorf("rarity %s colour must be #rrggbb", t.Name)
+	}
+	if t.MinPoints < 0 || t.MinPoints > t.MaxPoints {
+		return fmt.Errorf("rarity %s point range is invalid", t.Name)
+	}
+	return nil
+}
+
+// builtInRarities must always be configured: rules such as the Legendary
+// leaderboard and the rare-pull feeds refer to them by ID
+var builtInRarities = []Rarity{RarityCommon, RarityRare, RarityEpic, RarityLegendary}
+
+// RarityRegistry is a validated set of rarity tiers, loaded from data. It
+// never changes once built, so it is safe for concurrent use, and everything
+// that names, orders or validates rarities is given one.
+type RarityRegistry struct {
+	byRarity map[Rarity]RarityTier
+	ranked   []RarityTier // lowest rank first
+}
+
+// NewRarityRegistry validates the tiers. IDs, names and ranks must be unique,
+// and the built-in tiers must be present, though they may be renamed,
+// recoloured or reranked.
+func NewRarityRegistry(tiers []RarityTier) (*RarityRegistry, error) {
+	registry := &RarityRegistry{byRarity: make(map[Rarity]RarityTier)}
+	names := make(map[string]bool)
+	ranks := make(map[int]bool)
+	for _, tier := range tiers {
+		if err := tier.Validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := registry.byRarity[tier.Rarity]; ok {
+			return nil, fmt.Errorf("duplicate rarity ID %d", tier.Rarity)
+		}
+		if names[strings.ToLower(tier.Name)] {
+			return nil, fmt.Errorf("duplicate rarity name %s", tier.Name)
+		}
+		if ranks[tier.Rank] {
+			return nil, fmt.Errorf("duplicate rarity rank %d", tier.Rank)
+		}
+		registry.byRarity[tier.Rarity] = tier
+		names[strings.ToLower(tier.Name)] = true
+		ranks[tier.Rank] = true
+		registry.ranked = append(registry.ranked, tier)
+	}
+	for _, builtIn := range builtInRarities {
+		if _, ok := registry.byRarity[builtIn]; !ok {
+			return nil, fmt.Errorf("built-in rarity %d must be configured", builtIn)
+		}
+	}
+
+	sort.Slice(registry.ranked, func(i, j int) bool {
+		return registry.ranked[i].Rank < registry.ranked[j].Rank
+	})
+	return registry, nil
+}
+
+// Tiers returns the tiers, lowest rank first
+func (reg *RarityRegistry) Tiers() []RarityTier {
+	return append([]RarityTier{}, reg.ranked...)
+}
+
+// Tier returns the rarity's tier, if it is configured
+func (reg *RarityRegistry) Tier(r Rarity) (RarityTier, bool) {
+	tier, ok := reg.byRarity[r]
+	return tier, ok
+}
+
+// Name returns the rarity's display name
+func (reg *RarityRegistry) Name(r Rarity) string {
+	if tier, ok := reg.Tier(r); ok {
+		return tier.Name
+	}
+	return "Unknown"
+}
+
+// Parse converts a rarity name such as "Epic" to its Rarity
+func (reg *RarityRegistry) Parse(name string) (Rarity, error) {
+	for _, tier := range reg.ranked {
+		if strings.EqualFold(tier.Name, name) {
+			return tier.Rarity, nil
+		}
+	}
+	return 0, errors.New("unknown rarity: " + name)
+}
+
+// IsValid checks if the rarity is configured
+func (reg *RarityRegistry) IsValid(r Rarity) bool {
+	_, ok := reg.Tier(r)
+	return ok
+}
+
+// Rank orders rarities; higher is rarer. Unknown rarities rank 0.
+func (reg *RarityRegistry) Rank(r Rarity) int {
+	tier, _ := reg.Tier(r)
+	return tier.Rank
+}
+
+// AtLeast reports whether r is as rare as other or rarer
+func (reg *RarityRegistry) AtLeast(r, other Rarity) bool {
+	return reg.Rank(r) >= reg.Rank(other)
+}
+
+// Color returns the rarity's display colour
+func (reg *RarityRegistry) Color(r Rarity) string {
+	if tier, ok := reg.Tier(r); ok {
+		return tier.Color
+	}
+	return "#000000"
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// PityRule guarantees a minimum rarity once a user has gone AfterSpins spins
+// without one
+type PityRule struct {
+	Rarity     Rarity `json:"rarity"`
+	AfterSpins int    `json:"after_spins"`
+}
+
+func (p PityRule) Validate(tiers *RarityRegistry) error {
+	if !tiers.IsValid(p.Rarity) {
+		return errors.New("invalid pity rarity")
+	}
+	if p.AfterSpins <= 0 {
+		return errors.New("pity must trigger after a positive number of spins")
+	}
+	return nil
+}
+
+// RateTable is a published version of the item pool and its odds. A new
+// version is published whenever the configured pool changes, so the odds in
+// effect at any past time can be disclosed.
+type RateTable struct {
+	Version       int
+	EffectiveFrom time.Time
+	Rates         []RarityRate // empty for versions published before rarity rates
+	Items         []GachaItem
+	PityRules     []PityRule
+	Checksum      string // identifies the pool contents regardless of version
+}
+
+// NewRateTable validates a pool for publication. Version is assigned when stored.
+func NewRateTable(tiers *RarityRegistry, pool *GachaPool, pityRules []PityRule, effectiveFrom time.Time) (*RateTable, error) {
+	if err := ValidateGachaItems(tiers, pool.Items); err != nil {
+		return nil, err
+	}
+	for _, rule := range pityRules {
+		if err := rule.Validate(tiers); err != nil {
+			return nil, err
+		}
+	}
+
+	checksum, err := rateTableChecksum(pool.Rates, pool.Items, pityRules)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RateTable{
+		EffectiveFrom: effectiveFrom,
+		Rates:         pool.Rates,
+		Items:         pool.Items,
+		PityRules:     pityRules,
+		Checksum:      checksum,
+	}, nil
+}
+
+func rateTableChecksum(rates []RarityRate, items []GachaItem, pityRules []PityRule) (string, error) {
+	encoded, err := json.Marshal(struct {
+		Rates     []RarityRate
+		Items     []GachaItem
+		PityRules []PityRule
+	}{rates, items, pityRules})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// ExpectedPointsPerSpin is the mean points awarded by one spin, ignoring pity
+func (t *RateTable) ExpectedPointsPerSpin() float64 {
+	// 重みが整数のため、合計は誤差なく計算してから割る
+	total := int64(0)
+	for _, item := range t.Items {
+		total += int64(item.Weight) * int64(item.Points)
+	}
+	return float64(total) / WeightScale
+}
+
+// IsFeatured reports whether an item was a rate-up item of its rarity
+func (t *RateTable) IsFeatured(item GachaItem) bool {
+	return isFeatured(t.Rates, item)
+}
+
+// RarityWeight is the combined weight of every item of a rarity and the share
+// of it that goes to featured items
+type RarityWeight struct {
+	Rarity        Rarity
+	Weight        int
+	FeaturedShare int
+}
+
+func (w RarityWeight) Probability() float64 {
+	return float64(w.Weight) / WeightScale
+}
+
+// RarityWeights returns the weight of each rarity in the pool, lowest rank first
+func (t *RateTable) RarityWeights(tiers *RarityRegistry) []RarityWeight {
+	byRarity := make(map[Rarity]int)
+	for _, item := range t.Items {
+		byRarity[item.Rarity] += item.Weight
+	}
+
+	var weights []RarityWeight
+	for _, tier := range tiers.Tiers() {
+		rarity := tier.Rarity
+		weight, ok := byRarity[rarity]
+		if !ok {
+			continue
+		}
+		rarityWeight := RarityWeight{Rarity: rarity, Weight: weight}
+		for _, rate := range t.Rates {
+			if rate.Rarity == rarity {
+				rarityWeight.FeaturedShare = rate.FeaturedShare
+			}
+		}
+		weights = append(weights, rarityWeight)
+	}
+	return weights
+}
@@ -0,0 +1,42 @@
+package model
+
+import "time"
+
+// BalanceMismatch describes a user whose stored balance disagrees with the ledger
+type BalanceMismatch struct {
+	UserID          int
+	RecordedBalance int
+	LedgerBalance   int
+	Repaired        bool
+}
+
+// Difference returns how far the stored balance is ahead of the ledger
+func (m BalanceMismatch) Difference() int {
+	return m.RecordedBalance - m.LedgerBalance
+}
+
+// ReconciliationReport is the outcome of comparing balances with the ledger
+type ReconciliationReport struct {
+	CheckedUsers int
+	Mismatches   []BalanceMismatch
+	// LedgerTotal is the sum of every ledger entry; double entry requires zero
+	LedgerTotal int
+	// UnbalancedTransactions lists transactions whose entries do not sum to zero
+	UnbalancedTransactions []int
+	StartedAt              time.Time
+	FinishedAt             time.Time
+}
+
+// Unresolved returns the number of problems that were not repaired
+func (r *ReconciliationReport) Unresolved() int {
+	count := len(r.UnbalancedTransactions)
+	if r.LedgerTotal != 0 {
+		count++
+	}
+	for _, mismatch := range r.Mismatches {
+		if !mismatch.Repaired {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// ExponentialBackoff returns base doubled for every failed attempt after the
+// first, capped at max
+func ExponentialBackoff(attempts int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return delay
+}
@@ -0,0 +1,169 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	// MaxCoolOffPeriod is the longest cool-off; longer breaks are self-exclusions
+	MaxCoolOffPeriod = 6 * 7 * 24 * time.Hour
+	// MinSelfExclusionPeriod is the shortest self-exclusion
+	MinSelfExclusionPeriod = 180 * 24 * time.Hour
+	// DefaultLimitIncreaseDelay is how long a raised or removed limit waits before it applies
+	DefaultLimitIncreaseDelay = 24 * time.Hour
+)
+
+var (
+	// ErrSpendLimitReached is returned when a spin or spend is blocked by the user's own limits
+	ErrSpendLimitReached = errors.New("responsible gaming limit reached")
+	// ErrInvalidSpendLimitChange is returned for limit changes that are not allowed
+	ErrInvalidSpendLimitChange = errors.New("invalid limit change")
+)
+
+// SpendTransactionTypes are the point movements counted toward the weekly spend limit
+var SpendTransactionTypes = []TransactionType{TransactionTypeSpend, TransactionTypeTransferOut}
+
+// PendingLimit is a raised limit waiting for EffectiveAt
+type PendingLimit struct {
+	Value       int
+	EffectiveAt time.Time
+}
+
+// SpendLimits are a user's self-imposed responsible-gaming controls.
+// Limits of 0 mean no limit.
+type SpendLimits struct {
+	UserID            int
+	DailySpins        int
+	PendingDailySpins *PendingLimit
+	WeeklySpend       int // points
+	PendingWeekly     *PendingLimit
+	CoolOffUntil      *time.Time
+	SelfExcludedUntil *time.Time
+	UpdatedAt         time.Time
+}
+
+// NewSpendLimits returns the limits of a user who has set none
+func NewSpendLimits(userID int) *SpendLimits {
+	return &SpendLimits{
+		UserID:    userID,
+		UpdatedAt: time.Now(),
+	}
+}
+
+// SpendLimitChange is a requested update; nil fields are left unchanged
+type SpendLimitChange struct {
+	DailySpins        *int
+	WeeklySpend       *int
+	CoolOffUntil      *time.Time
+	SelfExcludedUntil *time.Time
+}
+
+// ApplyDue promotes pending limits whose delay has passed and reports whether any did
+func (l *SpendLimits) ApplyDue(now time.Time) bool {
+	changed := false
+	if l.PendingDailySpins != nil && !now.Before(l.PendingDailySpins.EffectiveAt) {
+		l.DailySpins = l.PendingDailySpins.Value
+		l.PendingDailySpins = nil
+		changed = true
+	}
+	if l.PendingWeekly != nil && !now.Before(l.PendingWeekly.EffectiveAt) {
+		l.WeeklySpend = l.PendingWeekly.Value
+		l.PendingWeekly = nil
+		changed = true
+	}
+	return changed
+}
+
+// Update applies a change. Stricter limits apply immediately; raising or
+// removing a limit only takes effect after delay. Cool-offs and
+// self-exclusions can be started or extended but not shortened.
+func (l *SpendLimits) Update(change SpendLimitChange, now time.Time, delay time.Duration) error {
+	l.ApplyDue(now)
+
+	if change.DailySpins != nil {
+		if err := setLimit(&l.DailySpins, &l.PendingDailySpins, *change.DailySpins, now, delay); err != nil {
+			return err
+		}
+	}
+	if change.WeeklySpend != nil {
+		if err := setLimit(&l.WeeklySpend, &l.PendingWeekly, *change.WeeklySpend, now, delay); err != nil {
+			return err
+		}
+	}
+
+	if change.CoolOffUntil != nil {
+		until := *change.CoolOffUntil
+		if !until.After(now) || until.Sub(now) > MaxCoolOffPeriod {
+			return fmt.Errorf("%w: a cool-off must end within %d days", ErrInvalidSpendLimitChange, MaxCoolOffPeriod/(24*time.Hour))
+		}
+		if l.CoolOffUntil != nil && l.CoolOffUntil.After(until) {
+			return fmt.Errorf("%w: an active cool-off cannot be shortened", ErrInvalidSpendLimitChange)
+		}
+		l.CoolOffUntil = &until
+	}
+	if change.SelfExcludedUntil != nil {
+		until := *change.SelfExcludedUntil
+		if until.Sub(now) < MinSelfExclusionPeriod {
+			return fmt.Errorf("%w: a self-exclusion must last at least %d days", ErrInvalidSpendLimitChange, MinSelfExclusionPeriod/(24*time.Hour))
+		}
+		if l.SelfExcludedUntil != nil && l.SelfExcludedUntil.After(until) {
+			return fmt.Errorf("%w: an active self-exclusion cannot be shortened", ErrInvalidSpendLimitChange)
+		}
+		l.SelfExcludedUntil = &until
+	}
+
+	l.UpdatedAt = now
+	return nil
+}
+
+// setLimit applies a stricter limit at once and schedules a looser one
+func setLimit(current *int, pending **PendingLimit, requested int, now time.Time, delay time.Duration) error {
+	if requested < 0 {
+		return fmt.Errorf("%w: limits cannot be negative", ErrInvalidSpendLimitChange)
+	}
+
+	switch {
+	case requested == *current:
+		*pending = nil
+	case requested != 0 && (*current == 0 || requested < *current):
+		*current = requested
+		*pending = nil
+	default:
+		*pending = &PendingLimit{Value: requested, EffectiveAt: now.Add(delay)}
+	}
+	return nil
+}
+
+// CheckSpin reports whether another spin is allowed after spinsToday spins
+func (l *SpendLimits) CheckSpin(now time.Time, spinsToday int) error {
+	if err := l.checkBreak(now); err != nil {
+		return err
+	}
+	if l.DailySpins > 0 && spinsToday >= l.DailySpins {
+		return fmt.Errorf("%w: daily limit of %d spins", ErrSpendLimitReached, l.DailySpins)
+	}
+	return nil
+}
+
+// CheckSpend reports whether amount points may be spent after spentThisWeek
+func (l *SpendLimits) CheckSpend(now time.Time, spentThisWeek, amount int) error {
+	if err := l.checkBreak(now); err != nil {
+		return err
+	}
+	if l.WeeklySpend > 0 && spentThisWeek+amount > l.WeeklySpend {
+		return fmt.Errorf("%w: weekly limit of %d points", ErrSpendLimitReached, l.WeeklySpend)
+	}
+	return nil
+}
+
+func (l *SpendLimits) checkBreak(now time.Time) error {
+	if l.SelfExcludedUntil != nil && now.Before(*l.SelfExcludedUntil) {
+		return fmt.Errorf("%w: self-excluded until %s", ErrSpendLimitReached, l.SelfExcludedUntil.Format(time.RFC3339))
+	}
+	if l.CoolOffUntil != nil && now.Before(*l.CoolOffUntil) {
+		return fmt.Errorf("%w: cooling off until %s", ErrSpendLimitReached, l.CoolOffUntil.Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PointTransfer is a movement of points from one user to another.
+// It is recorded as a transfer_out / transfer_in transaction pair sharing ID.
+type PointTransfer struct {
+	ID          string
+	FromUserID  int
+	ToUserID    int
+	Amount      int
+	Description string
+	CreatedAt   time.Time
+}
+
+var (
+	// ErrInvalidTransfer is returned for transfer requests that fail validation
+	ErrInvalidTransfer = errors.New("invalid transfer")
+	// ErrDailyTransferLimit is returned when a transfer would take the sender
+	// over MaxDailyTransferAmount
+	ErrDailyTransferLimit = errors.New("transfer would exceed daily transfer limit")
+)
+
+// NewPointTransfer creates a new transfer with validation
+func NewPointTransfer(fromUserID, toUserID, amount int, description string) (*PointTransfer, error) {
+	if fromUserID <= 0 || toUserID <= 0 {
+		return nil, fmt.Errorf("%w: user ID must be positive", ErrInvalidTransfer)
+	}
+
+	if fromUserID == toUserID {
+		return nil, fmt.Errorf("%w: cannot transfer points to yourself", ErrInvalidTransfer)
+	}
+
+	if amount < MinTransactionAmount || amount > MaxTransactionAmount {
+		return nil, fmt.Errorf("%w: transaction amount is outside allowed range", ErrInvalidTransfer)
+	}
+
+	if len(description) > 200 {
+		return nil, fmt.Errorf("%w: transfer description must be at most 200 characters long", ErrInvalidTransfer)
+	}
+
+	id, err := newTransferID()
+	if err != nil {
+		return nil, err
+	}
+
+	return &PointTransfer{
+		ID:          id,
+		FromUserID:  fromUserID,
+		ToUserID:    toUserID,
+		Amount:      amount,
+		Description: description,
+		CreatedAt:   time.Now(),
+	}, nil
+}
+
+// Transactions returns the ledger rows recorded for the transfer
+func (t *PointTransfer) Transactions() (out *PointTransaction, in *PointTransaction, err error) {
+	note := t.Description
+	if note != "" {
+		note = ": " + note
+	}
+
+	out, err = NewPointTransaction(t.FromUserID, t.Amount, TransactionTypeTransferOut, fmt.Sprintf("Transfer to user %d%s", t.ToUserID, note))
+	if err != nil {
+		return nil, nil, err
+	}
+	in, err = NewPointTransaction(t.ToUserID, t.Amount, TransactionTypeTransferIn, fmt.Sprintf("Transfer from user %d%s", t.FromUserID, note))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out.TransferID = t.ID
+	in.TransferID = t.ID
+	out.CreatedAt = t.CreatedAt
+	in.CreatedAt = t.CreatedAt
+	return out, in, nil
+}
+
+func newTransferID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
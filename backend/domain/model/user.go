@@ -1,14 +1,44 @@
 package model
 
 import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"errors"
 	"strings"
 	"time"
 )
 
+var (
+	// ErrUserNotFound is returned when the requested user does not exist
+	ErrUserNotFound = errors.New("user not found")
+	// ErrInvalidUserToken is returned when a request acting for a user does
+	// not present that user's token
+	ErrInvalidUserToken = errors.New("invalid user token")
+)
+
 type User struct {
-	ID        int
-	Name      string
+	ID   int
+	Name string
+	// Region is an ISO 3166-1 alpha-2 country code; empty when unknown. It
+	// decides the gacha policy only once RegionVerifiedAt is set.
+	Region           string
+	RegionVerifiedAt *time.Time
+	// BirthDate counts toward age checks only once AgeVerifiedAt is set
+	BirthDate     *time.Time
+	AgeVerifiedAt *time.Time
+	// SignupIP and SignupUserAgent describe the client that created the
+	// account, for abuse detection
+	SignupIP        string
+	SignupUserAgent string
+	// Status is set by support; see EffectiveStatus for expiring suspensions
+	Status       UserStatus
+	StatusReason string
+	StatusUntil  *time.Time
+	// TokenHash is the SHA-256 of the secret token issued to the user; empty
+	// for accounts that have not been issued one
+	TokenHash string `json:"-"`
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -17,6 +47,7 @@ type User struct {
 func NewUser(name string) (*User, error) {
 	user := &User{
 		Name:      name,
+		Status:    UserStatusActive,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
@@ -77,4 +108,105 @@ func (u *User) UpdateName(newName string) error {
 // IsNewUser checks if the user is newly created (within last 24 hours)
 func (u *User) IsNewUser() bool {
 	return time.Since(u.CreatedAt) < 24*time.Hour
+}
+
+// RecordSignup keeps the client that created the account
+func (u *User) RecordSignup(ip, userAgent string) {
+	u.SignupIP = ip
+	if len(userAgent) > maxSignupUserAgentLength {
+		userAgent = userAgent[:maxSignupUserAgentLength]
+	}
+	u.SignupUserAgent = userAgent
+}
+
+const maxSignupUserAgentLength = 255
+
+// VerifyRegion records a region confirmed by an admin, normalised to upper
+// case. An empty region clears it.
+func (u *User) VerifyRegion(region string, now time.Time) error {
+	region = strings.ToUpper(strings.TrimSpace(region))
+	if region != "" && !IsRegionCode(region) {
+		return errors.New("region must be an ISO 3166-1 alpha-2 country code")
+	}
+	u.Region = region
+	u.RegionVerifiedAt = nil
+	if region != "" {
+		u.RegionVerifiedAt = &now
+	}
+	u.UpdatedAt = now
+	return nil
+}
+
+// VerifiedRegion returns the user's region, or "" when it is not verified
+func (u *User) VerifiedRegion() string {
+	if u.RegionVerifiedAt == nil {
+		return ""
+	}
+	return u.Region
+}
+
+// VerifyAge records a birth date confirmed by an identity check
+func (u *User) VerifyAge(birthDate time.Time, now time.Time) error {
+	if birthDate.After(now) {
+		return errors.New("birth date cannot be in the future")
+	}
+	u.BirthDate = &birthDate
+	u.AgeVerifiedAt = &now
+	u.UpdatedAt = now
+	return nil
+}
+
+// VerifiedAge returns the user's age at now, or false when the age is not verified
+func (u *User) VerifiedAge(now time.Time) (int, bool) {
+	if u.BirthDate == nil || u.AgeVerifiedAt == nil {
+		return 0, false
+	}
+	age := now.Year() - u.BirthDate.Year()
+	if now.Month() < u.BirthDate.Month() || (now.Month() == u.BirthDate.Month() && now.Day() < u.BirthDate.Day()) {
+		age--
+	}
+	return age, true
+}
+
+// IsRegionCode reports whether code has the form of an ISO 3166-1 alpha-2 code
+func IsRegionCode(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// IssueToken generates a new secret token for the user, replacing any
+// previous one. Only its hash is kept, so the token must be handed to the
+// user now.
+func (u *User) IssueToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+	u.TokenHash = hashUserToken(token)
+	u.UpdatedAt = time.Now()
+	return token, nil
+}
+
+// Authenticate checks that token is the user's current token
+func (u *User) Authenticate(token string) error {
+	if u.TokenHash == "" || token == "" {
+		return ErrInvalidUserToken
+	}
+	if subtle.ConstantTimeCompare([]byte(hashUserToken(token)), []byte(u.TokenHash)) != 1 {
+		return ErrInvalidUserToken
+	}
+	return nil
+}
+
+func hashUserToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
\ No newline at end of file
@@ -0,0 +1,142 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// UserStatus is whether support has suspended an account
+type UserStatus string
+
+const (
+	UserStatusActive UserStatus = "active"
+	// UserStatusFrozen is a hold while support investigates
+	UserStatusFrozen UserStatus = "frozen"
+	// UserStatusBanned is the outcome of an investigation
+	UserStatusBanned UserStatus = "banned"
+)
+
+func (s UserStatus) IsValid() bool {
+	switch s {
+	case UserStatusActive, UserStatusFrozen, UserStatusBanned:
+		return true
+	default:
+		return false
+	}
+}
+
+var (
+	// ErrAccountSuspended matches every AccountStatusError with errors.Is
+	ErrAccountSuspended = errors.New("account is suspended")
+	// ErrInvalidStatusChange is returned for status changes that are not allowed
+	ErrInvalidStatusChange = errors.New("invalid status change")
+)
+
+// AccountStatusError is returned when a frozen or banned account tries to
+// spin, spend or transfer
+type AccountStatusError struct {
+	UserID int
+	Status UserStatus
+	Reason string
+	Until  *time.Time // nil when the suspension does not expire
+}
+
+func (e *AccountStatusError) Error() string {
+	message := fmt.Sprintf("account %d is %s", e.UserID, e.Status)
+	if e.Until != nil {
+		message += " until " + e.Until.UTC().Format(time.RFC3339)
+	}
+	if e.Reason != "" {
+		message += ": " + e.Reason
+	}
+	return message
+}
+
+func (e *AccountStatusError) Is(target error) bool {
+	return target == ErrAccountSuspended
+}
+
+// UserStatusChange is the audit record of a status change made by an admin
+type UserStatusChange struct {
+	ID         int
+	UserID     int
+	FromStatus UserStatus
+	ToStatus   UserStatus
+	Reason     string
+	Until      *time.Time
+	AdminID    string
+	CreatedAt  time.Time
+}
+
+// EffectiveStatus is the user's status at now; a suspension past its expiry is active
+func (u *User) EffectiveStatus(now time.Time) UserStatus {
+	if u.Status == "" {
+		return UserStatusActive
+	}
+	if u.Status != UserStatusActive && u.StatusUntil != nil && !now.Before(*u.StatusUntil) {
+		return UserStatusActive
+	}
+	return u.Status
+}
+
+// CheckActive returns an *AccountStatusError unless the account is active at now
+func (u *User) CheckActive(now time.Time) error {
+	status := u.EffectiveStatus(now)
+	if status == UserStatusActive {
+		return nil
+	}
+	return &AccountStatusError{
+		UserID: u.ID,
+		Status: status,
+		Reason: u.StatusReason,
+		Until:  u.StatusUntil,
+	}
+}
+
+// ChangeStatus sets the user's status and returns the audit record of the
+// change. Suspensions need a reason and may expire at until; activating
+// clears both.
+func (u *User) ChangeStatus(status UserStatus, reason string, until *time.Time, adminID string, now time.Time) (*UserStatusChange, error) {
+	if !status.IsValid() {
+		return nil, fmt.Errorf("%w: status must be active, frozen or banned", ErrInvalidStatusChange)
+	}
+	reason = strings.TrimSpace(reason)
+	if len(reason) > 500 {
+		return nil, fmt.Errorf("%w: reason must be at most 500 characters long", ErrInvalidStatusChange)
+	}
+	if strings.TrimSpace(adminID) == "" {
+		return nil, fmt.Errorf("%w: acting admin is required", ErrInvalidStatusChange)
+	}
+
+	if status == UserStatusActive {
+		until = nil
+	} else {
+		if reason == "" {
+			return nil, fmt.Errorf("%w: a reason is required to freeze or ban an account", ErrInvalidStatusChange)
+		}
+		if until != nil && !until.After(now) {
+			return nil, fmt.Errorf("%w: until must be in the future", ErrInvalidStatusChange)
+		}
+	}
+
+	change := &UserStatusChange{
+		UserID:     u.ID,
+		FromStatus: u.EffectiveStatus(now),
+		ToStatus:   status,
+		Reason:     reason,
+		Until:      until,
+		AdminID:    adminID,
+		CreatedAt:  now,
+	}
+
+	u.Status = status
+	u.StatusReason = reason
+	u.StatusUntil = until
+	if status == UserStatusActive {
+		u.StatusReason = ""
+	}
+	u.UpdatedAt = now
+	return change, nil
+}
@@ -0,0 +1,317 @@
+package model
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxWebhookAttempts is how many deliveries are tried before a delivery is dead-lettered
+	MaxWebhookAttempts = 8
+
+	webhookBaseRetryDelay = 5 * time.Second
+	webhookMaxRetryDelay  = 30 * time.Minute
+	webhookSecretBytes    = 32
+	maxWebhookURLLength   = 2048
+
+	// WebhookTestEventName is sent by the admin test endpoint
+	WebhookTestEventName = "webhook.test"
+)
+
+// WebhookSubscription sends events matching EventNames and Filter to URL
+type WebhookSubscription struct {
+	ID         int
+	URL        string
+	Secret     string // HMAC-SHA256 key for the payload signature
+	EventNames []string
+	Filter     WebhookFilter
+	Active     bool
+	CreatedBy  string
+	CreatedAt  time.Time
+}
+
+// NewWebhookSubscription validates a subscription, with rarity conditions
+// checked against the tiers. A random secret is generated when none is given.
+func NewWebhookSubscription(rawURL, secret string, eventNames []string, filter, createdBy string, tiers *RarityRegistry) (*WebhookSubscription, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return nil, errors.New("webhook URL must be an absolute http or https URL")
+	}
+	if len(rawURL) > maxWebhookURLLength {
+		return nil, errors.New("webhook URL is too long")
+	}
+	if len(eventNames) == 0 {
+		return nil, errors.New("at least one event name is required")
+	}
+	for _, name := range eventNames {
+		if strings.TrimSpace(name) == "" {
+			return nil, errors.New("event names cannot be empty")
+		}
+	}
+	if createdBy == "" {
+		return nil, errors.New("admin ID is required")
+	}
+
+	parsedFilter, err := ParseWebhookFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+	if err := parsedFilter.Validate(tiers); err != nil {
+		return nil, err
+	}
+
+	if secret == "" {
+		buf := make([]byte, webhookSecretBytes)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		secret = hex.EncodeToString(buf)
+	}
+
+	return &WebhookSubscription{
+		URL:        rawURL,
+		Secret:     secret,
+		EventNames: eventNames,
+		Filter:     parsedFilter,
+		Active:     true,
+		CreatedBy:  createdBy,
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// Matches reports whether an event with the given name and decoded JSON payload should be sent
+func (s *WebhookSubscription) Matches(eventName string, payload map[string]interface{}, tiers *RarityRegistry) bool {
+	if !s.Active {
+		return false
+	}
+	for _, name := range s.EventNames {
+		if name == eventName || name == "*" {
+			return s.Filter.Matches(payload, tiers)
+		}
+	}
+	return false
+}
+
+// WebhookFilter is a comma separated list of conditions on payload fields
+// that must all hold, e.g. "rarity>=Epic,points_earned>=500". Operators are
+// ==, !=, >, >=, < and <=. Rarity fields compare by rank.
+type WebhookFilter struct {
+	Raw        string
+	conditions []webhookCondition
+}
+
+type webhookCondition struct {
+	field string
+	op    string
+	value string
+}
+
+var webhookFilterOperators = []string{">=", "<=", "!=", "==", ">", "<"}
+
+// ParseWebhookFilter checks the filter's syntax. Rarity names are resolved
+// against the tiers by Validate and Matches, so a stored filter can be parsed
+// without them.
+func ParseWebhookFilter(raw string) (WebhookFilter, error) {
+	filter := WebhookFilter{Raw: strings.TrimSpace(raw)}
+	if filter.Raw == "" {
+		return filter, nil
+	}
+
+	for _, part := range strings.Split(filter.Raw, ",") {
+		part = strings.TrimSpace(part)
+		condition, err := parseWebhookCondition(part)
+		if err != nil {
+			return WebhookFilter{}, err
+		}
+		filter.conditions = append(filter.conditions, condition)
+	}
+	return filter, nil
+}
+
+func parseWebhookCondition(part string) (webhookCondition, error) {
+	for _, op := range webhookFilterOperators {
+		index := strings.Index(part, op)
+		if index <= 0 {
+			continue
+		}
+		condition := webhookCondition{
+			field: strings.TrimSpace(part[:index]),
+			op:    op,
+			value: strings.TrimSpace(part[index+len(op):]),
+		}
+		if condition.field == "" || condition.value == "" {
+			break
+		}
+		return condition, nil
+	}
+	return webhookCondition{}, fmt.Errorf("invalid filter condition %q", part)
+}
+
+// Validate checks that every rarity condition names one of the tiers
+func (f WebhookFilter) Validate(tiers *RarityRegistry) error {
+	for _, condition := range f.conditions {
+		if condition.field != "rarity" {
+			continue
+		}
+		if _, err := tiers.Parse(condition.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Matches reports whether every condition holds for the payload. A missing
+// field never matches.
+func (f WebhookFilter) Matches(payload map[string]interface{}, tiers *RarityRegistry) bool {
+	for _, condition := range f.conditions {
+		if !condition.matches(payload[condition.field], tiers) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c webhookCondition) matches(actual interface{}, tiers *RarityRegistry) bool {
+	if c.field == "rarity" {
+		// レアリティは ID ではなくランクで比較する
+		expected, err := tiers.Parse(c.value)
+		if err != nil {
+			return false
+		}
+		value, ok := actual.(float64)
+		if !ok || !tiers.IsValid(Rarity(value)) {
+			return false
+		}
+		return compareNumbers(c.op, float64(tiers.Rank(Rarity(value))), float64(tiers.Rank(expected)))
+	}
+
+	switch value := actual.(type) {
+	case float64:
+		expected, err := strconv.ParseFloat(c.value, 64)
+		if err != nil {
+			return false
+		}
+		return compareNumbers(c.op, value, expected)
+	case string:
+		switch c.op {
+		case "==":
+			return value == c.value
+		case "!=":
+			return value != c.value
+		}
+	case bool:
+		switch c.op {
+		case "==":
+			return strconv.FormatBool(value) == c.value
+		case "!=":
+			return strconv.FormatBool(value) != c.value
+		}
+	}
+	return false
+}
+
+func compareNumbers(op string, value, expected float64) bool {
+	switch op {
+	case "==":
+		return value == expected
+	case "!=":
+		return value != expected
+	case ">":
+		return value > expected
+	case ">=":
+		return value >= expected
+	case "<":
+		return value < expected
+	case "<=":
+		return value <= expected
+	}
+	return false
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryDead      WebhookDeliveryStatus = "dead"
+)
+
+// WebhookDelivery is one event queued for one subscription
+type WebhookDelivery struct {
+	ID             int64
+	SubscriptionID int
+	EventID        int64 // outbox event ID; 0 when the event was not stored
+	EventName      string
+	Payload        []byte // JSON encoded event
+	Status         WebhookDeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastStatusCode int
+	LastError      string
+	CreatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+func NewWebhookDelivery(subscriptionID int, eventID int64, eventName string, payload []byte) *WebhookDelivery {
+	now := time.Now()
+	return &WebhookDelivery{
+		SubscriptionID: subscriptionID,
+		EventID:        eventID,
+		EventName:      eventName,
+		Payload:        payload,
+		Status:         WebhookDeliveryPending,
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+	}
+}
+
+// MarkDelivered records a 2xx response
+func (d *WebhookDelivery) MarkDelivered(now time.Time, statusCode int) {
+	d.Status = WebhookDeliveryDelivered
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.LastError = ""
+	d.DeliveredAt = &now
+}
+
+// MarkAttemptFailed schedules a retry with exponential backoff and moves the
+// delivery to the dead-letter list after MaxWebhookAttempts
+func (d *WebhookDelivery) MarkAttemptFailed(now time.Time, statusCode int, cause error) {
+	d.Attempts++
+	d.LastStatusCode = statusCode
+	d.LastError = cause.Error()
+	if d.Attempts >= MaxWebhookAttempts {
+		d.Status = WebhookDeliveryDead
+		return
+	}
+	d.NextAttemptAt = now.Add(ExponentialBackoff(d.Attempts, webhookBaseRetryDelay, webhookMaxRetryDelay))
+}
+
+// Replay requeues a dead delivery with a fresh set of attempts
+func (d *WebhookDelivery) Replay(now time.Time) error {
+	if d.Status != WebhookDeliveryDead {
+		return errors.New("only dead deliveries can be replayed")
+	}
+	d.Status = WebhookDeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = now
+	return nil
+}
+
+// SignWebhookPayload returns the hex HMAC-SHA256 of "timestamp.body". Receivers
+// recompute it from the X-FortuneSpinner-Timestamp header and the raw body.
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
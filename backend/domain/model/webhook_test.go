@@ -0,0 +1,155 @@
+package model
+
+import "testing"
+
+// testRarityTiers are the tiers seeded by the migrations
+func testRarityTiers() []RarityTier {
+	return []RarityTier{
+		{Rarity: RarityCommon, Name: "Common", Rank: 1, Color: "#808080", MinPoints: 1, MaxPoints: 50},
+		{Rarity: RarityRare, Name: "Rare", Rank: 2, Color: "#0066ff", MinPoints: 10, MaxPoints: 200},
+		{Rarity: RarityEpic, Name: "Epic", Rank: 3, Color: "#8b00ff", MinPoints: 100, MaxPoints: 1000},
+		{Rarity: RarityLegendary, Name: "Legendary", Rank: 4, Color: "#ff6600", MinPoints: 500, MaxPoints: 5000},
+	}
+}
+
+func newTestRarityRegistry(t *testing.T, tiers []RarityTier) *RarityRegistry {
+	t.Helper()
+	registry, err := NewRarityRegistry(tiers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return registry
+}
+
+// parseValidFilter parses a filter the way a new subscription does
+func parseValidFilter(raw string, tiers *RarityRegistry) (WebhookFilter, error) {
+	filter, err := ParseWebhookFilter(raw)
+	if err != nil {
+		return WebhookFilter{}, err
+	}
+	return filter, filter.Validate(tiers)
+}
+
+func TestWebhookFilterRarityAtLeastEpic(t *testing.T) {
+	tiers := newTestRarityRegistry(t, testRarityTiers())
+	filter, err := parseValidFilter("rarity>=Epic", tiers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		payload map[string]interface{}
+		want    bool
+	}{
+		{"common", map[string]interface{}{"rarity": float64(RarityCommon)}, false},
+		{"rare", map[string]interface{}{"rarity": float64(RarityRare)}, false},
+		{"epic", map[string]interface{}{"rarity": float64(RarityEpic)}, true},
+		{"legendary", map[string]interface{}{"rarity": float64(RarityLegendary)}, true},
+		{"unknown rarity", map[string]interface{}{"rarity": float64(99)}, false},
+		{"rarity name instead of ID", map[string]interface{}{"rarity": "Legendary"}, false},
+		{"missing rarity", map[string]interface{}{"points_earned": float64(1000)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := filter.Matches(tt.payload, tiers); got != tt.want {
+				t.Errorf("Matches(%v) = %v, want %v", tt.payload, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebhookFilterComparesRarityByRank(t *testing.T) {
+	// ID の順ではなくランクの順で比較される
+	reranked := testRarityTiers()
+	reranked[1].Rank, reranked[3].Rank = reranked[3].Rank, reranked[1].Rank
+	tiers := newTestRarityRegistry(t, reranked)
+
+	filter, err := parseValidFilter("rarity>=Epic", tiers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !filter.Matches(map[string]interface{}{"rarity": float64(RarityRare)}, tiers) {
+		t.Error("Rare ranked above Epic does not match rarity>=Epic")
+	}
+	if filter.Matches(map[string]interface{}{"rarity": float64(RarityLegendary)}, tiers) {
+		t.Error("Legendary ranked below Epic matches rarity>=Epic")
+	}
+}
+
+func TestWebhookFilterUsesConfiguredTiers(t *testing.T) {
+	// データで追加したティアもコードの変更なしにフィルタで使える
+	mythic := RarityTier{Rarity: 5, Name: "Mythic", Rank: 5, Color: "#ff0033", MinPoints: 2000, MaxPoints: 10000}
+	tiers := newTestRarityRegistry(t, append(testRarityTiers(), mythic))
+
+	filter, err := parseValidFilter("rarity>=Mythic", tiers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !filter.Matches(map[string]interface{}{"rarity": float64(5)}, tiers) {
+		t.Error("Mythic does not match rarity>=Mythic")
+	}
+	if filter.Matches(map[string]interface{}{"rarity": float64(RarityLegendary)}, tiers) {
+		t.Error("Legendary matches rarity>=Mythic")
+	}
+
+	if _, err := parseValidFilter("rarity>=Mythic", newTestRarityRegistry(t, testRarityTiers())); err == nil {
+		t.Error("rarity>=Mythic accepted without a Mythic tier")
+	}
+}
+
+func TestWebhookFilterConditions(t *testing.T) {
+	tiers := newTestRarityRegistry(t, testRarityTiers())
+	payload := map[string]interface{}{
+		"rarity":        float64(RarityLegendary),
+		"points_earned": float64(1000),
+		"type":          "transfer_in",
+		"featured":      true,
+	}
+
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{"", true},
+		{" rarity >= epic ", true},
+		{"rarity==Legendary", true},
+		{"rarity!=Legendary", false},
+		{"rarity<Epic", false},
+		{"rarity<=Legendary", true},
+		{"rarity>Legendary", false},
+		{"rarity>=Epic,points_earned>=500", true},
+		{"rarity>=Epic,points_earned>=5000", false},
+		{"points_earned<1000", false},
+		{"type==transfer_in", true},
+		{"type!=transfer_in", false},
+		{"type>transfer_in", false},
+		{"featured==true", true},
+	}
+	for _, tt := range tests {
+		filter, err := parseValidFilter(tt.filter, tiers)
+		if err != nil {
+			t.Errorf("parse %q: %v", tt.filter, err)
+			continue
+		}
+		if got := filter.Matches(payload, tiers); got != tt.want {
+			t.Errorf("%q matches = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestParseWebhookFilterRejectsInvalidConditions(t *testing.T) {
+	tiers := newTestRarityRegistry(t, testRarityTiers())
+	for _, raw := range []string{
+		"rarity",
+		">=Epic",
+		"rarity>=",
+		"rarity>=Mythic",
+		"rarity>=Epic,",
+		"points_earned=500",
+	} {
+		if _, err := parseValidFilter(raw, tiers); err == nil {
+			t.Errorf("%q accepted as a valid filter", raw)
+		}
+	}
+}
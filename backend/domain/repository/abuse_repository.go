@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+type AbuseRepository interface {
+	SaveFlag(ctx context.Context, flag *model.AbuseFlag) error
+	UpdateFlag(ctx context.Context, flag *model.AbuseFlag) error
+	FindFlagByIDForUpdate(ctx context.Context, id int) (*model.AbuseFlag, error)
+	// FindLatestFlagByUserID returns the user's newest flag, nil when never flagged
+	FindLatestFlagByUserID(ctx context.Context, userID int) (*model.AbuseFlag, error)
+	// FindFlags returns flags with the given status, highest score first
+	FindFlags(ctx context.Context, status model.AbuseFlagStatus, limit int) ([]*model.AbuseFlag, error)
+
+	// CountSignupsFromIP counts accounts created from ip since the given time
+	CountSignupsFromIP(ctx context.Context, ip string, since time.Time) (int, error)
+	// CountSignupsWithUserAgent counts accounts created with userAgent since the given time
+	CountSignupsWithUserAgent(ctx context.Context, userAgent string, since time.Time) (int, error)
+	// CountNewAccountSenders counts distinct senders of transfers to userID
+	// since the given time whose accounts were created since createdSince
+	CountNewAccountSenders(ctx context.Context, userID int, since, createdSince time.Time) (int, error)
+}
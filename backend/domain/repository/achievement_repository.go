@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+type AchievementRepository interface {
+	FindByUserID(ctx context.Context, userID int) ([]*model.UserAchievement, error)
+	// Unlock stores the achievement and reports false if the user already had it
+	Unlock(ctx context.Context, achievement *model.UserAchievement) (bool, error)
+	SetRewardTransaction(ctx context.Context, achievement *model.UserAchievement) error
+	// GetProgress gathers the user's statistics; spin days are collected from since onwards
+	GetProgress(ctx context.Context, userID int, since time.Time) (model.AchievementProgress, []time.Time, error)
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+// AuditRepository stores the audit log. There are no update or delete
+// methods: entries are only ever appended.
+type AuditRepository interface {
+	// Enqueue stores an entry to be chained later. It takes no lock other
+	// writers wait for.
+	Enqueue(ctx context.Context, entry *model.AuditEntry) error
+	// ClaimQueued returns the oldest queued entries and locks them until the
+	// surrounding transaction ends
+	ClaimQueued(ctx context.Context, limit int) ([]*model.AuditEntry, error)
+	// LockHead returns the last entry of the chain and locks it until the
+	// surrounding transaction ends, so chaining runs one batch at a time
+	LockHead(ctx context.Context) (model.AuditChainHead, error)
+	// Append stores an entry chained after the locked head, moves the head
+	// to it and removes it from the queue
+	Append(ctx context.Context, entry *model.AuditEntry) error
+	GetHead(ctx context.Context) (model.AuditChainHead, error)
+	// FindEntries returns matching entries, newest first
+	FindEntries(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error)
+	// FindEntriesAfter returns entries with Seq greater than afterSeq, oldest first
+	FindEntriesAfter(ctx context.Context, afterSeq int64, limit int) ([]*model.AuditEntry, error)
+}
@@ -0,0 +1,14 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+type GachaPoolRepository interface {
+	// FindRates returns the rate of each rarity with its featured item IDs
+	FindRates(ctx context.Context) ([]model.RarityRate, error)
+	// FindItems returns the items of the pool without weights
+	FindItems(ctx context.Context) ([]model.GachaItem, error)
+}
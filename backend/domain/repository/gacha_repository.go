@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
 )
 
@@ -9,4 +11,8 @@ type GachaRepository interface {
 	SaveResult(ctx context.Context, result *model.GachaResult) error
 	FindResultsByUserID(ctx context.Context, userID int, limit int) ([]*model.GachaResult, error)
 	FindResultByID(ctx context.Context, id int) (*model.GachaResult, error)
+	// CountResultsByItem counts results per item ID; userID 0 counts every user
+	CountResultsByItem(ctx context.Context, userID int) (map[int]int, error)
+	// CountResultsSince counts the user's results created at or after since
+	CountResultsSince(ctx context.Context, userID int, since time.Time) (int, error)
 }
\ No newline at end of file
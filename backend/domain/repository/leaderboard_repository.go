@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+type LeaderboardRepository interface {
+	// ApplyDeltas incrementally updates the materialized scores
+	ApplyDeltas(ctx context.Context, deltas []model.LeaderboardScoreDelta) error
+	// RebuildScores recomputes a window from gacha_results
+	RebuildScores(ctx context.Context, metric model.LeaderboardMetric, period model.LeaderboardPeriod, periodStart time.Time) error
+	// FindTop returns the highest scores, ranks left unset
+	FindTop(ctx context.Context, metric model.LeaderboardMetric, period model.LeaderboardPeriod, periodStart time.Time, limit int) ([]model.LeaderboardEntry, error)
+	// FindUserRank returns the user's ranked entry, or nil if the user has no score
+	FindUserRank(ctx context.Context, metric model.LeaderboardMetric, period model.LeaderboardPeriod, periodStart time.Time, userID int) (*model.LeaderboardEntry, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+type OutboxRepository interface {
+	// Append stores events, joining the caller's transaction when there is one
+	Append(ctx context.Context, events ...*model.OutboxEvent) error
+	// ClaimDue returns pending events whose next attempt is due and leases
+	// them until now+lease so concurrent relays skip them
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.OutboxEvent, error)
+	// UpdateDelivery saves the status, attempts and error of an event
+	UpdateDelivery(ctx context.Context, event *model.OutboxEvent) error
+	// MarkConsumed records that consumer handled the event and returns false
+	// if it already had
+	MarkConsumed(ctx context.Context, eventID int64, consumer string) (bool, error)
+	GetStats(ctx context.Context, failureLimit int) (*model.OutboxStats, error)
+}
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+type PointAdjustmentRepository interface {
+	Save(ctx context.Context, adjustment *model.PointAdjustment) error
+	// FindAdjustments returns the newest adjustments first; userID 0 covers every user
+	FindAdjustments(ctx context.Context, userID int, limit int) ([]*model.PointAdjustment, error)
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+type PointLotRepository interface {
+	CreateLot(ctx context.Context, lot *model.PointLot) error
+	UpdateLot(ctx context.Context, lot *model.PointLot) error
+	// FindActiveLotsForUpdate returns lots with points remaining, earliest expiry first, locked until the transaction ends
+	FindActiveLotsForUpdate(ctx context.Context, userID int) ([]*model.PointLot, error)
+	FindUserIDsWithExpiredLots(ctx context.Context, now time.Time, limit int) ([]int, error)
+	FindUpcomingExpirations(ctx context.Context, userID int, from, until time.Time) ([]model.PointExpiration, error)
+}
@@ -2,13 +2,26 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
 )
 
 type PointRepository interface {
 	GetUserPoint(ctx context.Context, userID int) (*model.UserPoint, error)
+	// GetUserPointForUpdate locks the row until the surrounding transaction ends
+	GetUserPointForUpdate(ctx context.Context, userID int) (*model.UserPoint, error)
 	CreateUserPoint(ctx context.Context, userPoint *model.UserPoint) error
 	UpdateUserPoint(ctx context.Context, userPoint *model.UserPoint) error
 	SaveTransaction(ctx context.Context, transaction *model.PointTransaction) error
 	FindTransactionsByUserID(ctx context.Context, userID int, limit int) ([]*model.PointTransaction, error)
+	SumTransactionsSince(ctx context.Context, userID int, transactionType model.TransactionType, since time.Time) (int, error)
+	FindAllUserPoints(ctx context.Context) ([]*model.UserPoint, error)
+
+	// Balances derived from ledger_entries rather than the cached user_points.balance
+	GetLedgerBalance(ctx context.Context, account model.LedgerAccount) (int, error)
+	FindUserLedgerBalances(ctx context.Context) (map[int]int, error)
+	// SumLedger returns the total of every ledger entry, which must be zero
+	SumLedger(ctx context.Context) (int, error)
+	FindUnbalancedTransactionIDs(ctx context.Context, limit int) ([]int, error)
 }
\ No newline at end of file
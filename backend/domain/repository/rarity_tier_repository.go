@@ -0,0 +1,11 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+type RarityTierRepository interface {
+	FindAll(ctx context.Context) ([]model.RarityTier, error)
+}
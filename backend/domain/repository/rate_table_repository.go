@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+type RateTableRepository interface {
+	// PublishIfChanged stores table as the next version unless the latest
+	// version has the same checksum, and returns the table now in effect
+	PublishIfChanged(ctx context.Context, table *model.RateTable) (*model.RateTable, error)
+	FindByVersion(ctx context.Context, version int) (*model.RateTable, error)
+	// FindEffectiveAt returns the version in effect at t, or nil before the first version
+	FindEffectiveAt(ctx context.Context, t time.Time) (*model.RateTable, error)
+	FindAll(ctx context.Context) ([]*model.RateTable, error)
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+type SpendLimitRepository interface {
+	// FindByUserID returns nil when the user has never set limits
+	FindByUserID(ctx context.Context, userID int) (*model.SpendLimits, error)
+	// FindByUserIDForUpdate also locks the row until the transaction ends
+	FindByUserIDForUpdate(ctx context.Context, userID int) (*model.SpendLimits, error)
+	// Save inserts or replaces the user's limits
+	Save(ctx context.Context, limits *model.SpendLimits) error
+}
@@ -0,0 +1,9 @@
+package repository
+
+import "context"
+
+// Transactor runs a unit of work atomically. Repository calls made with the
+// context passed to fn participate in the same database transaction.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
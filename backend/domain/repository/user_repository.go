@@ -8,5 +8,11 @@ import (
 type UserRepository interface {
 	Create(ctx context.Context, user *model.User) error
 	FindByID(ctx context.Context, id int) (*model.User, error)
+	// FindByIDForUpdate locks the row until the surrounding transaction ends
+	FindByIDForUpdate(ctx context.Context, id int) (*model.User, error)
+	// Update saves the profile; the status is only written by UpdateStatus
 	Update(ctx context.Context, user *model.User) error
+	UpdateStatus(ctx context.Context, user *model.User) error
+	// UpdateToken saves a newly issued token hash
+	UpdateToken(ctx context.Context, user *model.User) error
 }
\ No newline at end of file
@@ -0,0 +1,13 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+type UserStatusRepository interface {
+	SaveChange(ctx context.Context, change *model.UserStatusChange) error
+	// FindChangesByUserID returns the user's status changes, newest first
+	FindChangesByUserID(ctx context.Context, userID int, limit int) ([]*model.UserStatusChange, error)
+}
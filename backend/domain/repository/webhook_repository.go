@@ -0,0 +1,24 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription *model.WebhookSubscription) error
+	FindSubscriptionByID(ctx context.Context, id int) (*model.WebhookSubscription, error)
+	FindSubscriptions(ctx context.Context, activeOnly bool) ([]*model.WebhookSubscription, error)
+	// EnqueueDelivery stores a pending delivery. It returns false when the
+	// subscription already has a delivery for the same stored event.
+	EnqueueDelivery(ctx context.Context, delivery *model.WebhookDelivery) (bool, error)
+	// ClaimDueDeliveries returns pending deliveries whose next attempt is due
+	// and leases them until now+lease
+	ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.WebhookDelivery, error)
+	FindDeliveryByID(ctx context.Context, id int64) (*model.WebhookDelivery, error)
+	// FindDeadDeliveries lists the dead-letter list, newest first; subscriptionID 0 means all
+	FindDeadDeliveries(ctx context.Context, subscriptionID int, limit int) ([]*model.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+}
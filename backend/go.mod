@@ -1,5 +1,5 @@
 module github.com/Amane-Fujiwara11/FortuneSpinner/backend
 
-go 1.20
+go 1.21
 
 require github.com/go-sql-driver/mysql v1.7.1
@@ -0,0 +1,154 @@
+package infrastructure
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/infrastructure/mysql"
+)
+
+// Config holds the application settings needed to build the container
+type Config struct {
+	Database mysql.Config
+
+	// Port is the HTTP port the API server listens on
+	Port string
+	// AdminTokens maps each admin's bearer token to their admin ID; the
+	// /api/admin endpoints are disabled when it is empty
+	AdminTokens map[string]string
+
+	// TrustProxyHeaders takes client IPs from X-Real-IP; only enable it when
+	// the API is reachable solely through the frontend proxy
+	TrustProxyHeaders bool
+
+	// PointExpiryDays is how long earned points remain spendable
+	PointExpiryDays int
+	// PointExpiryInterval is how often expired point lots are swept
+	PointExpiryInterval time.Duration
+
+	// GachaPolicyFile holds the regional gacha policies; GachaPolicyFileSet
+	// is false when the default path is used
+	GachaPolicyFile    string
+	GachaPolicyFileSet bool
+
+	// LimitIncreaseDelay is how long a user waits before a raised spending limit applies
+	LimitIncreaseDelay time.Duration
+
+	// OutboxRelayInterval is how often pending events are delivered to subscribers
+	OutboxRelayInterval time.Duration
+
+	// WebhookDeliveryInterval is how often queued webhook deliveries are sent
+	WebhookDeliveryInterval time.Duration
+	// WebhookTimeout bounds each webhook HTTP request
+	WebhookTimeout time.Duration
+
+	// AuditChainInterval is how often queued audit entries are chained
+	AuditChainInterval time.Duration
+
+	// WinsMaxConnections caps concurrent /api/ws/wins connections
+	WinsMaxConnections int
+
+	// LogFormat is "json" or "text"; LogLevel is the least severe level logged
+	LogFormat string
+	LogLevel  slog.Level
+}
+
+// LoadConfig reads the configuration from environment variables
+func LoadConfig() Config {
+	return Config{
+		Database: mysql.Config{
+			Host:     getEnv("DB_HOST", "localhost"),
+			Port:     getEnv("DB_PORT", "3306"),
+			User:     getEnv("DB_USER", "root"),
+			Password: getEnv("DB_PASSWORD", "rootpassword"),
+			Database: getEnv("DB_NAME", "fortunespinner"),
+		},
+		Port:                    getEnv("PORT", "8080"),
+		AdminTokens:             getEnvAdminTokens("ADMIN_TOKENS"),
+		TrustProxyHeaders:       os.Getenv("TRUST_PROXY_HEADERS") == "true",
+		PointExpiryDays:         getEnvInt("POINT_EXPIRY_DAYS", 365),
+		PointExpiryInterval:     getEnvDuration("POINT_EXPIRY_INTERVAL", time.Hour),
+		GachaPolicyFile:         getEnv("GACHA_POLICY_FILE", "config/gacha_policies.json"),
+		GachaPolicyFileSet:      os.Getenv("GACHA_POLICY_FILE") != "",
+		LimitIncreaseDelay:      getEnvDuration("LIMIT_INCREASE_DELAY", model.DefaultLimitIncreaseDelay),
+		OutboxRelayInterval:     getEnvDuration("OUTBOX_RELAY_INTERVAL", time.Second),
+		WebhookDeliveryInterval: getEnvDuration("WEBHOOK_DELIVERY_INTERVAL", 2*time.Second),
+		WebhookTimeout:          getEnvDuration("WEBHOOK_TIMEOUT", 5*time.Second),
+		AuditChainInterval:      getEnvDuration("AUDIT_CHAIN_INTERVAL", time.Second),
+		WinsMaxConnections:      getEnvInt("WINS_MAX_CONNECTIONS", 1000),
+		LogFormat:               getEnv("LOG_FORMAT", "json"),
+		LogLevel:                getEnvLevel("LOG_LEVEL", slog.LevelInfo),
+	}
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+		slog.Warn("Invalid config value, using default", "key", key, "default", defaultValue)
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			return parsed
+		}
+		slog.Warn("Invalid config value, using default", "key", key, "default", defaultValue.String())
+	}
+	return defaultValue
+}
+
+func getEnvLevel(key string, defaultValue slog.Level) slog.Level {
+	if value := os.Getenv(key); value != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(value)); err == nil {
+			return level
+		}
+		slog.Warn("Invalid config value, using default", "key", key, "default", defaultValue.String())
+	}
+	return defaultValue
+}
+
+// getEnvAdminTokens parses comma-separated "admin-id:token" pairs into a map
+// from token to admin ID. Malformed entries are skipped, and so is a token
+// shared by two admins, since the token alone has to identify the admin.
+func getEnvAdminTokens(key string) map[string]string {
+	tokens := make(map[string]string)
+	shared := make(map[string]bool)
+	value := os.Getenv(key)
+	if value == "" {
+		return tokens
+	}
+
+	for i, entry := range strings.Split(value, ",") {
+		adminID, token, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		adminID, token = strings.TrimSpace(adminID), strings.TrimSpace(token)
+		if !ok || adminID == "" || token == "" {
+			slog.Warn("Invalid admin token entry, skipping", "key", key, "entry", i+1)
+			continue
+		}
+		if other, exists := tokens[token]; exists || shared[token] {
+			// どちらの管理者か特定できないので、共有されたトークンは誰にも認めない
+			slog.Warn("Admin token is shared, skipping", "key", key, "admin_id", adminID, "other_admin_id", other)
+			delete(tokens, token)
+			shared[token] = true
+			continue
+		}
+		tokens[token] = adminID
+	}
+	return tokens
+}
@@ -0,0 +1,46 @@
+package infrastructure
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAdminTokensIdentifyEachAdmin(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  map[string]string
+	}{
+		{name: "unset", value: "", want: map[string]string{}},
+		{
+			name:  "one token per admin",
+			value: "alice:tok-a, bob:tok-b",
+			want:  map[string]string{"tok-a": "alice", "tok-b": "bob"},
+		},
+		{
+			name:  "token containing a colon",
+			value: "alice:tok:a",
+			want:  map[string]string{"tok:a": "alice"},
+		},
+		{
+			name:  "malformed entries skipped",
+			value: "alice,:tok-x,bob:,carol:tok-c",
+			want:  map[string]string{"tok-c": "carol"},
+		},
+		{
+			// 共有トークンではどちらの管理者か分からない
+			name:  "shared token rejected for everyone",
+			value: "alice:tok-s,bob:tok-s,mallory:tok-s,carol:tok-c",
+			want:  map[string]string{"tok-c": "carol"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ADMIN_TOKENS", tt.value)
+			if got := LoadConfig().AdminTokens; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("AdminTokens = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
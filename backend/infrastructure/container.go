@@ -1,14 +1,30 @@
 package infrastructure
 
 import (
+	"context"
 	"database/sql"
 
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/event"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
 	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
 	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/infrastructure/mysql"
 	infraRepo "github.com/Amane-Fujiwara11/FortuneSpinner/backend/infrastructure/repository"
+	infraWebhook "github.com/Amane-Fujiwara11/FortuneSpinner/backend/infrastructure/webhook"
 	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/interface/handler"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/abuse"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/achievement"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/audit"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/eventbus"
 	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/gacha"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/leaderboard"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/outbox"
 	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/point"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/reconcile"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/spendlimit"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/stream"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/user"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/webhook"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/wins"
 )
 
 // Container holds all dependencies
@@ -17,24 +33,65 @@ type Container struct {
 	DB *sql.DB
 
 	// Repositories
-	UserRepository  repository.UserRepository
-	GachaRepository repository.GachaRepository
-	PointRepository repository.PointRepository
+	UserRepository            repository.UserRepository
+	GachaRepository           repository.GachaRepository
+	RateTableRepository       repository.RateTableRepository
+	RarityTierRepository      repository.RarityTierRepository
+	GachaPoolRepository       repository.GachaPoolRepository
+	PointRepository           repository.PointRepository
+	PointLotRepository        repository.PointLotRepository
+	PointAdjustmentRepository repository.PointAdjustmentRepository
+	SpendLimitRepository      repository.SpendLimitRepository
+	AbuseRepository           repository.AbuseRepository
+	UserStatusRepository      repository.UserStatusRepository
+	LeaderboardRepository     repository.LeaderboardRepository
+	AchievementRepository     repository.AchievementRepository
+	OutboxRepository          repository.OutboxRepository
+	WebhookRepository         repository.WebhookRepository
+	AuditRepository           repository.AuditRepository
+	Transactor                repository.Transactor
+
+	// Events
+	EventBus     *eventbus.Bus
+	StreamBroker *stream.Broker
+	WinsHub      *wins.Hub
 
 	// Use Cases
-	GachaUsecase gacha.GachaUsecase
-	PointUsecase point.PointUsecase
+	AuditUsecase       audit.AuditUsecase
+	Wallet             point.Wallet
+	SpendLimitUsecase  spendlimit.SpendLimitUsecase
+	AbuseUsecase       abuse.AbuseUsecase
+	UserUsecase        user.UserUsecase
+	GachaUsecase       gacha.GachaUsecase
+	PointUsecase       point.PointUsecase
+	AdjustmentUsecase  point.AdjustmentUsecase
+	LeaderboardUsecase leaderboard.LeaderboardUsecase
+	AchievementUsecase achievement.AchievementUsecase
+	OutboxUsecase      outbox.OutboxUsecase
+	WebhookUsecase     webhook.WebhookUsecase
+	ReconcileUsecase   reconcile.ReconcileUsecase
 
 	// Handlers
-	UserHandler  *handler.UserHandler
-	GachaHandler *handler.GachaHandler
-	PointHandler *handler.PointHandler
+	UserHandler         *handler.UserHandler
+	GachaHandler        *handler.GachaHandler
+	PointHandler        *handler.PointHandler
+	SpendLimitHandler   *handler.SpendLimitHandler
+	AdminPointHandler   *handler.AdminPointHandler
+	AdminUserHandler    *handler.AdminUserHandler
+	AdminAbuseHandler   *handler.AdminAbuseHandler
+	LeaderboardHandler  *handler.LeaderboardHandler
+	AchievementHandler  *handler.AchievementHandler
+	OutboxHandler       *handler.OutboxHandler
+	AdminWebhookHandler *handler.AdminWebhookHandler
+	AdminAuditHandler   *handler.AdminAuditHandler
+	StreamHandler       *handler.StreamHandler
+	WinsHandler         *handler.WinsHandler
 }
 
 // NewContainer creates and initializes all dependencies
-func NewContainer(dbConfig mysql.Config) (*Container, error) {
+func NewContainer(config Config) (*Container, error) {
 	// Initialize database connection
-	db, err := mysql.NewDB(dbConfig)
+	db, err := mysql.NewDB(config.Database)
 	if err != nil {
 		return nil, err
 	}
@@ -42,31 +99,161 @@ func NewContainer(dbConfig mysql.Config) (*Container, error) {
 	// Initialize repositories
 	userRepo := infraRepo.NewUserRepository(db)
 	gachaRepo := infraRepo.NewGachaRepository(db)
+	rateTableRepo := infraRepo.NewRateTableRepository(db)
+	rarityTierRepo := infraRepo.NewRarityTierRepository(db)
+	gachaPoolRepo := infraRepo.NewGachaPoolRepository(db)
 	pointRepo := infraRepo.NewPointRepository(db)
+	pointLotRepo := infraRepo.NewPointLotRepository(db)
+	pointAdjustmentRepo := infraRepo.NewPointAdjustmentRepository(db)
+	spendLimitRepo := infraRepo.NewSpendLimitRepository(db)
+	abuseRepo := infraRepo.NewAbuseRepository(db)
+	userStatusRepo := infraRepo.NewUserStatusRepository(db)
+	leaderboardRepo := infraRepo.NewLeaderboardRepository(db)
+	achievementRepo := infraRepo.NewAchievementRepository(db)
+	outboxRepo := infraRepo.NewOutboxRepository(db)
+	webhookRepo := infraRepo.NewWebhookRepository(db)
+	auditRepo := infraRepo.NewAuditRepository(db)
+	transactor := infraRepo.NewTransactor(db)
+
+	// Rarity tiers are data; everything that names, orders or validates
+	// rarities is given the registry loaded here
+	tiers, err := gacha.LoadRarityTiers(context.Background(), rarityTierRepo)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
 
-	// Initialize use cases
-	gachaUsecase := gacha.NewGachaUsecase(gachaRepo, pointRepo, userRepo)
-	pointUsecase := point.NewPointUsecase(pointRepo, userRepo)
+	// The pool is data too; a pool that does not validate fails startup
+	pool, err := gacha.LoadGachaPool(context.Background(), gachaPoolRepo, tiers)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	policies, err := LoadGachaPolicies(config.GachaPolicyFile, config.GachaPolicyFileSet)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	// Initialize events: use cases record events in the outbox within their
+	// transaction and the relay delivers them to the bus subscribers
+	bus := eventbus.NewBus()
+	publisher := outbox.NewPublisher(outboxRepo)
+	streamBroker := stream.NewBroker(tiers)
+	winsHub := wins.NewHub(userRepo, tiers, config.WinsMaxConnections)
+
+	// Initialize use cases. Every use case that changes state records it in
+	// the audit log.
+	auditUsecase := audit.NewAuditUsecase(auditRepo, transactor)
+	wallet := point.NewWallet(pointRepo, pointLotRepo, userRepo, transactor, config.PointExpiryDays)
+	achievementUsecase, err := achievement.NewAchievementUsecase(achievementRepo, userRepo, transactor, wallet, publisher, model.DefaultAchievementRules())
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	spendLimitUsecase := spendlimit.NewSpendLimitUsecase(spendLimitRepo, gachaRepo, pointRepo, userRepo, transactor, auditUsecase, config.LimitIncreaseDelay)
+	abuseUsecase := abuse.NewAbuseUsecase(abuseRepo, userRepo, gachaRepo, pointRepo, transactor, auditUsecase, model.DefaultAbuseRules())
+	userUsecase := user.NewUserUsecase(userRepo, userStatusRepo, transactor, publisher, auditUsecase)
+	gachaUsecase := gacha.NewGachaUsecase(gachaRepo, rateTableRepo, userRepo, transactor, wallet, spendLimitUsecase, abuseUsecase, policies, tiers, pool, publisher, auditUsecase, gacha.GlobalSource)
+	pointUsecase := point.NewPointUsecase(pointRepo, pointLotRepo, userRepo, transactor, wallet, spendLimitUsecase, publisher, auditUsecase)
+	reconcileUsecase := reconcile.NewReconcileUsecase(pointRepo, transactor)
+	adjustmentUsecase := point.NewAdjustmentUsecase(pointAdjustmentRepo, userRepo, transactor, wallet, publisher, auditUsecase)
+	leaderboardUsecase := leaderboard.NewLeaderboardUsecase(leaderboardRepo, auditUsecase)
+	outboxUsecase := outbox.NewOutboxUsecase(outboxRepo, transactor, bus)
+	webhookUsecase := webhook.NewWebhookUsecase(webhookRepo, transactor, auditUsecase, infraWebhook.NewHTTPSender(config.WebhookTimeout), tiers)
+
+	// Subscribe to events. Events may be redelivered, so subscribers that
+	// are not idempotent are wrapped with Once.
+	bus.Subscribe(event.NameGachaExecuted, outboxUsecase.Once("leaderboard", leaderboardUsecase.RecordGachaResult))
+	bus.Subscribe(event.NameGachaExecuted, achievementUsecase.HandleUserEvent)
+	bus.Subscribe(event.NamePointsCredited, achievementUsecase.HandleUserEvent)
+	bus.Subscribe(event.NamePointsDebited, achievementUsecase.HandleUserEvent)
+	bus.Subscribe(event.NameUserCreated, abuseUsecase.HandleUserEvent)
+	bus.Subscribe(event.NameGachaExecuted, abuseUsecase.HandleUserEvent)
+	bus.Subscribe(event.NamePointsCredited, abuseUsecase.HandleUserEvent)
+	bus.Subscribe(event.NamePointsDebited, abuseUsecase.HandleUserEvent)
+	bus.Subscribe(eventbus.AllEvents, webhookUsecase.HandleEvent)
+	bus.Subscribe(event.NameGachaExecuted, streamBroker.HandleEvent)
+	bus.Subscribe(event.NamePointsCredited, streamBroker.HandleEvent)
+	bus.Subscribe(event.NamePointsDebited, streamBroker.HandleEvent)
+	bus.Subscribe(event.NameGachaExecuted, winsHub.HandleEvent)
 
 	// Initialize handlers
-	userHandler := handler.NewUserHandler(userRepo)
-	gachaHandler := handler.NewGachaHandler(gachaUsecase)
+	userHandler := handler.NewUserHandler(userUsecase)
+	gachaHandler := handler.NewGachaHandler(gachaUsecase, tiers)
 	pointHandler := handler.NewPointHandler(pointUsecase)
+	spendLimitHandler := handler.NewSpendLimitHandler(spendLimitUsecase)
+	adminPointHandler := handler.NewAdminPointHandler(adjustmentUsecase)
+	adminUserHandler := handler.NewAdminUserHandler(userUsecase, abuseUsecase)
+	adminAbuseHandler := handler.NewAdminAbuseHandler(abuseUsecase)
+	leaderboardHandler := handler.NewLeaderboardHandler(leaderboardUsecase)
+	achievementHandler := handler.NewAchievementHandler(achievementUsecase)
+	outboxHandler := handler.NewOutboxHandler(outboxUsecase)
+	adminWebhookHandler := handler.NewAdminWebhookHandler(webhookUsecase)
+	adminAuditHandler := handler.NewAdminAuditHandler(auditUsecase)
+	streamHandler := handler.NewStreamHandler(streamBroker)
+	winsHandler := handler.NewWinsHandler(winsHub)
+	userHandler.RegisterSubresource("achievements", achievementHandler.GetUserAchievements)
+	userHandler.RegisterSubresource("limits", spendLimitHandler.HandleLimits)
+	userHandler.RegisterSubresource("gacha-policy", gachaHandler.GetUserPolicy)
 
 	return &Container{
-		DB:              db,
-		UserRepository:  userRepo,
-		GachaRepository: gachaRepo,
-		PointRepository: pointRepo,
-		GachaUsecase:    gachaUsecase,
-		PointUsecase:    pointUsecase,
-		UserHandler:     userHandler,
-		GachaHandler:    gachaHandler,
-		PointHandler:    pointHandler,
+		DB:                        db,
+		UserRepository:            userRepo,
+		GachaRepository:           gachaRepo,
+		RateTableRepository:       rateTableRepo,
+		RarityTierRepository:      rarityTierRepo,
+		GachaPoolRepository:       gachaPoolRepo,
+		PointRepository:           pointRepo,
+		PointLotRepository:        pointLotRepo,
+		PointAdjustmentRepository: pointAdjustmentRepo,
+		SpendLimitRepository:      spendLimitRepo,
+		AbuseRepository:           abuseRepo,
+		UserStatusRepository:      userStatusRepo,
+		LeaderboardRepository:     leaderboardRepo,
+		AchievementRepository:     achievementRepo,
+		OutboxRepository:          outboxRepo,
+		WebhookRepository:         webhookRepo,
+		AuditRepository:           auditRepo,
+		Transactor:                transactor,
+		EventBus:                  bus,
+		StreamBroker:              streamBroker,
+		WinsHub:                   winsHub,
+		AuditUsecase:              auditUsecase,
+		Wallet:                    wallet,
+		SpendLimitUsecase:         spendLimitUsecase,
+		AbuseUsecase:              abuseUsecase,
+		UserUsecase:               userUsecase,
+		GachaUsecase:              gachaUsecase,
+		PointUsecase:              pointUsecase,
+		ReconcileUsecase:          reconcileUsecase,
+		AdjustmentUsecase:         adjustmentUsecase,
+		LeaderboardUsecase:        leaderboardUsecase,
+		AchievementUsecase:        achievementUsecase,
+		OutboxUsecase:             outboxUsecase,
+		WebhookUsecase:            webhookUsecase,
+		UserHandler:               userHandler,
+		GachaHandler:              gachaHandler,
+		PointHandler:              pointHandler,
+		SpendLimitHandler:         spendLimitHandler,
+		AdminPointHandler:         adminPointHandler,
+		AdminUserHandler:          adminUserHandler,
+		AdminAbuseHandler:         adminAbuseHandler,
+		LeaderboardHandler:        leaderboardHandler,
+		AchievementHandler:        achievementHandler,
+		OutboxHandler:             outboxHandler,
+		AdminWebhookHandler:       adminWebhookHandler,
+		AdminAuditHandler:         adminAuditHandler,
+		StreamHandler:             streamHandler,
+		WinsHandler:               winsHandler,
 	}, nil
 }
 
-// Close closes the database connection
+// Close waits for async event subscribers to finish and closes the database connection
 func (c *Container) Close() error {
+	c.StreamBroker.Close()
+	c.WinsHub.Close()
+	c.EventBus.Close()
 	return c.DB.Close()
-}
\ No newline at end of file
+}
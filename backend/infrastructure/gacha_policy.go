@@ -0,0 +1,73 @@
+package infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+// gachaPolicyFile is the JSON layout of GACHA_POLICY_FILE
+type gachaPolicyFile struct {
+	Default    gachaPolicyEntry   `json:"default"`
+	Unverified *gachaPolicyEntry  `json:"unverified"`
+	Regions    []gachaPolicyEntry `json:"regions"`
+}
+
+type gachaPolicyEntry struct {
+	Region                string `json:"region"`
+	Blocked               bool   `json:"blocked"`
+	RequireRateDisclosure bool   `json:"require_rate_disclosure"`
+	MinAge                int    `json:"min_age"`
+}
+
+func (e gachaPolicyEntry) toModel() model.GachaPolicy {
+	return model.GachaPolicy{
+		Region:                e.Region,
+		Blocked:               e.Blocked,
+		RequireRateDisclosure: e.RequireRateDisclosure,
+		MinAge:                e.MinAge,
+	}
+}
+
+// LoadGachaPolicies reads the regional gacha policies from path. When the
+// file does not exist and was not configured explicitly, everything is allowed.
+func LoadGachaPolicies(path string, explicit bool) (*model.GachaPolicies, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && !explicit {
+		slog.Warn("Gacha policy file not found, allowing the gacha in every region", "path", path)
+		return model.DefaultGachaPolicies(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ParseGachaPolicies(data)
+}
+
+// ParseGachaPolicies decodes and validates a policy file. Unknown fields are
+// rejected so that a misspelt restriction cannot be silently ignored.
+func ParseGachaPolicies(data []byte) (*model.GachaPolicies, error) {
+	var file gachaPolicyFile
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid gacha policy file: %w", err)
+	}
+
+	regional := make([]model.GachaPolicy, 0, len(file.Regions))
+	for _, entry := range file.Regions {
+		regional = append(regional, entry.toModel())
+	}
+	var unverified *model.GachaPolicy
+	if file.Unverified != nil {
+		policy := file.Unverified.toModel()
+		unverified = &policy
+	}
+	return model.NewGachaPolicies(file.Default.toModel(), unverified, regional)
+}
@@ -0,0 +1,113 @@
+package infrastructure
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+func TestShippedGachaPolicies(t *testing.T) {
+	policies, err := LoadGachaPolicies("../config/gacha_policies.json", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	adult := now.AddDate(-20, 0, 0)
+	minor := now.AddDate(-16, 0, 0)
+
+	tests := []struct {
+		name           string
+		region         string
+		verifyRegion   bool
+		birthDate      *time.Time
+		wantRegion     string
+		wantDisclosure bool
+		wantMinAge     int
+		wantAllowed    bool
+	}{
+		{name: "BE", region: "BE", verifyRegion: true, wantRegion: "BE", wantAllowed: true},
+		{name: "NL", region: "NL", verifyRegion: true, wantRegion: "NL", wantAllowed: true},
+		{name: "CN without verified age", region: "CN", verifyRegion: true, wantRegion: "CN", wantDisclosure: true, wantMinAge: 18},
+		{name: "CN minor", region: "CN", verifyRegion: true, birthDate: &minor, wantRegion: "CN", wantDisclosure: true, wantMinAge: 18},
+		{name: "CN adult", region: "CN", verifyRegion: true, birthDate: &adult, wantRegion: "CN", wantDisclosure: true, wantMinAge: 18, wantAllowed: true},
+		{name: "KR", region: "KR", verifyRegion: true, wantRegion: "KR", wantDisclosure: true, wantAllowed: true},
+		{name: "JP", region: "JP", verifyRegion: true, wantRegion: "JP", wantDisclosure: true, wantAllowed: true},
+		{name: "default for a region without an entry", region: "US", verifyRegion: true, wantRegion: "US", wantAllowed: true},
+		{name: "unverified without a region", wantDisclosure: true, wantMinAge: 18},
+		{name: "unverified adult", birthDate: &adult, wantDisclosure: true, wantMinAge: 18, wantAllowed: true},
+		{name: "self-declared region stays unverified", region: "US", wantDisclosure: true, wantMinAge: 18},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := model.NewUser("tester")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.verifyRegion {
+				if err := user.VerifyRegion(tt.region, now); err != nil {
+					t.Fatal(err)
+				}
+			} else {
+				user.Region = tt.region
+			}
+			if tt.birthDate != nil {
+				if err := user.VerifyAge(*tt.birthDate, now); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			policy := policies.For(user.VerifiedRegion())
+			if policy.Region != tt.wantRegion {
+				t.Errorf("region = %q, want %q", policy.Region, tt.wantRegion)
+			}
+			if policy.RequireRateDisclosure != tt.wantDisclosure {
+				t.Errorf("require_rate_disclosure = %v, want %v", policy.RequireRateDisclosure, tt.wantDisclosure)
+			}
+			if policy.MinAge != tt.wantMinAge {
+				t.Errorf("min_age = %d, want %d", policy.MinAge, tt.wantMinAge)
+			}
+
+			err = policy.CheckAccess(user, now)
+			if tt.wantAllowed && err != nil {
+				t.Errorf("access denied: %v", err)
+			}
+			if !tt.wantAllowed && !errors.Is(err, model.ErrGachaRestricted) {
+				t.Errorf("access error = %v, want %v", err, model.ErrGachaRestricted)
+			}
+		})
+	}
+}
+
+func TestUnverifiedGachaPolicyCombinesRestrictions(t *testing.T) {
+	policies, err := ParseGachaPolicies([]byte(`{
+		"default": {"min_age": 16},
+		"regions": [
+			{"region": "XA", "blocked": true},
+			{"region": "XB", "require_rate_disclosure": true, "min_age": 21}
+		]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	policy := policies.For("")
+	if !policy.Blocked || !policy.RequireRateDisclosure || policy.MinAge != 21 {
+		t.Errorf("unverified policy %+v does not combine every restriction", policy)
+	}
+
+	policies, err = ParseGachaPolicies([]byte(`{
+		"default": {},
+		"unverified": {"min_age": 18},
+		"regions": [{"region": "XA", "blocked": true}]
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if policy := policies.For(""); policy.Blocked || policy.MinAge != 18 {
+		t.Errorf("configured unverified policy not used, got %+v", policy)
+	}
+}
@@ -0,0 +1,16 @@
+package infrastructure
+
+import (
+	"io"
+	"log/slog"
+)
+
+// NewLogger builds the application logger. It writes JSON lines unless the
+// format is "text", which is easier to read during development.
+func NewLogger(w io.Writer, format string, level slog.Level) *slog.Logger {
+	options := &slog.HandlerOptions{Level: level}
+	if format == "text" {
+		return slog.New(slog.NewTextHandler(w, options))
+	}
+	return slog.New(slog.NewJSONHandler(w, options))
+}
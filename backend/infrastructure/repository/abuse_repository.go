@@ -0,0 +1,177 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+type abuseRepository struct {
+	db *sql.DB
+}
+
+func NewAbuseRepository(db *sql.DB) repository.AbuseRepository {
+	return &abuseRepository{
+		db: db,
+	}
+}
+
+const abuseFlagColumns = `id, user_id, score, reasons, throttled, status, reviewed_by, review_note, reviewed_at, created_at, updated_at`
+
+func (r *abuseRepository) SaveFlag(ctx context.Context, flag *model.AbuseFlag) error {
+	reasons, err := json.Marshal(flag.Reasons)
+	if err != nil {
+		return err
+	}
+
+	query := `INSERT INTO abuse_flags (user_id, score, reasons, throttled, status, reviewed_by, review_note, reviewed_at, created_at, updated_at) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		flag.UserID,
+		flag.Score,
+		reasons,
+		flag.Throttled,
+		flag.Status,
+		flag.ReviewedBy,
+		flag.ReviewNote,
+		nullTime(flag.ReviewedAt),
+		flag.CreatedAt,
+		flag.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	flag.ID = int(id)
+	return nil
+}
+
+func (r *abuseRepository) UpdateFlag(ctx context.Context, flag *model.AbuseFlag) error {
+	reasons, err := json.Marshal(flag.Reasons)
+	if err != nil {
+		return err
+	}
+
+	query := `UPDATE abuse_flags 
+		SET score = ?, reasons = ?, throttled = ?, status = ?, reviewed_by = ?, review_note = ?, reviewed_at = ?, updated_at = ? 
+		WHERE id = ?`
+	_, err = conn(ctx, r.db).ExecContext(ctx, query,
+		flag.Score,
+		reasons,
+		flag.Throttled,
+		flag.Status,
+		flag.ReviewedBy,
+		flag.ReviewNote,
+		nullTime(flag.ReviewedAt),
+		flag.UpdatedAt,
+		flag.ID,
+	)
+	return err
+}
+
+func (r *abuseRepository) FindFlagByIDForUpdate(ctx context.Context, id int) (*model.AbuseFlag, error) {
+	query := `SELECT ` + abuseFlagColumns + ` FROM abuse_flags WHERE id = ? FOR UPDATE`
+	return r.findFlag(ctx, query, id)
+}
+
+func (r *abuseRepository) FindLatestFlagByUserID(ctx context.Context, userID int) (*model.AbuseFlag, error) {
+	query := `SELECT ` + abuseFlagColumns + ` FROM abuse_flags WHERE user_id = ? ORDER BY id DESC LIMIT 1`
+	return r.findFlag(ctx, query, userID)
+}
+
+func (r *abuseRepository) findFlag(ctx context.Context, query string, arg interface{}) (*model.AbuseFlag, error) {
+	flags, err := r.findFlags(ctx, query, arg)
+	if err != nil || len(flags) == 0 {
+		return nil, err
+	}
+	return flags[0], nil
+}
+
+func (r *abuseRepository) FindFlags(ctx context.Context, status model.AbuseFlagStatus, limit int) ([]*model.AbuseFlag, error) {
+	query := `SELECT ` + abuseFlagColumns + ` 
+		FROM abuse_flags 
+		WHERE status = ? 
+		ORDER BY score DESC, id 
+		LIMIT ?`
+	return r.findFlags(ctx, query, status, limit)
+}
+
+func (r *abuseRepository) findFlags(ctx context.Context, query string, args ...interface{}) ([]*model.AbuseFlag, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var flags []*model.AbuseFlag
+	for rows.Next() {
+		var flag model.AbuseFlag
+		var reasons []byte
+		var reviewedAt sql.NullTime
+		err := rows.Scan(
+			&flag.ID,
+			&flag.UserID,
+			&flag.Score,
+			&reasons,
+			&flag.Throttled,
+			&flag.Status,
+			&flag.ReviewedBy,
+			&flag.ReviewNote,
+			&reviewedAt,
+			&flag.CreatedAt,
+			&flag.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(reasons, &flag.Reasons); err != nil {
+			return nil, err
+		}
+		if reviewedAt.Valid {
+			flag.ReviewedAt = &reviewedAt.Time
+		}
+		flags = append(flags, &flag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return flags, nil
+}
+
+func (r *abuseRepository) CountSignupsFromIP(ctx context.Context, ip string, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM users WHERE signup_ip = ? AND created_at >= ?`
+	return r.count(ctx, query, ip, since)
+}
+
+func (r *abuseRepository) CountSignupsWithUserAgent(ctx context.Context, userAgent string, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM users WHERE signup_user_agent = ? AND created_at >= ?`
+	return r.count(ctx, query, userAgent, since)
+}
+
+func (r *abuseRepository) CountNewAccountSenders(ctx context.Context, userID int, since, createdSince time.Time) (int, error) {
+	query := `SELECT COUNT(DISTINCT sent.user_id) 
+		FROM point_transactions received 
+		JOIN point_transactions sent ON sent.transfer_id = received.transfer_id AND sent.type = ? 
+		JOIN users sender ON sender.id = sent.user_id 
+		WHERE received.user_id = ? AND received.type = ? AND received.created_at >= ? AND sender.created_at >= ?`
+	return r.count(ctx, query, model.TransactionTypeTransferOut, userID, model.TransactionTypeTransferIn, since, createdSince)
+}
+
+func (r *abuseRepository) count(ctx context.Context, query string, args ...interface{}) (int, error) {
+	var count int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+type achievementRepository struct {
+	db *sql.DB
+}
+
+func NewAchievementRepository(db *sql.DB) repository.AchievementRepository {
+	return &achievementRepository{
+		db: db,
+	}
+}
+
+func (r *achievementRepository) FindByUserID(ctx context.Context, userID int) ([]*model.UserAchievement, error) {
+	query := `SELECT id, user_id, code, unlocked_at, reward_transaction_id 
+		FROM user_achievements 
+		WHERE user_id = ? 
+		ORDER BY unlocked_at ASC, id ASC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var achievements []*model.UserAchievement
+	for rows.Next() {
+		var achievement model.UserAchievement
+		var rewardTransactionID sql.NullInt64
+		err := rows.Scan(
+			&achievement.ID,
+			&achievement.UserID,
+			&achievement.Code,
+			&achievement.UnlockedAt,
+			&rewardTransactionID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		achievement.RewardTransactionID = int(rewardTransactionID.Int64)
+		achievements = append(achievements, &achievement)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return achievements, nil
+}
+
+func (r *achievementRepository) Unlock(ctx context.Context, achievement *model.UserAchievement) (bool, error) {
+	query := `INSERT IGNORE INTO user_achievements (user_id, code, unlocked_at) VALUES (?, ?, ?)`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, achievement.UserID, achievement.Code, achievement.UnlockedAt)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+
+	achievement.ID = int(id)
+	return true, nil
+}
+
+func (r *achievementRepository) SetRewardTransaction(ctx context.Context, achievement *model.UserAchievement) error {
+	query := `UPDATE user_achievements SET reward_transaction_id = ? WHERE id = ?`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, achievement.RewardTransactionID, achievement.ID)
+	return err
+}
+
+func (r *achievementRepository) GetProgress(ctx context.Context, userID int, since time.Time) (model.AchievementProgress, []time.Time, error) {
+	var progress model.AchievementProgress
+
+	countQuery := `SELECT COUNT(*), COALESCE(SUM(CASE WHEN rarity = ? THEN 1 ELSE 0 END), 0) 
+		FROM gacha_results 
+		WHERE user_id = ?`
+	err := conn(ctx, r.db).QueryRowContext(ctx, countQuery, model.RarityLegendary, userID).Scan(
+		&progress.TotalSpins,
+		&progress.LegendaryPulls,
+	)
+	if err != nil {
+		return progress, nil, err
+	}
+
+	balanceQuery := `SELECT COALESCE(MAX(balance), 0) FROM user_points WHERE user_id = ?`
+	if err := conn(ctx, r.db).QueryRowContext(ctx, balanceQuery, userID).Scan(&progress.Balance); err != nil {
+		return progress, nil, err
+	}
+
+	daysQuery := `SELECT DISTINCT DATE(created_at) 
+		FROM gacha_results 
+		WHERE user_id = ? AND created_at >= ?`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, daysQuery, userID, since)
+	if err != nil {
+		return progress, nil, err
+	}
+	defer rows.Close()
+
+	var days []time.Time
+	for rows.Next() {
+		var day time.Time
+		if err := rows.Scan(&day); err != nil {
+			return progress, nil, err
+		}
+		days = append(days, day)
+	}
+
+	if err = rows.Err(); err != nil {
+		return progress, nil, err
+	}
+
+	return progress, days, nil
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+type auditRepository struct {
+	db *sql.DB
+}
+
+func NewAuditRepository(db *sql.DB) repository.AuditRepository {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+const (
+	auditColumns      = `seq, actor, action, target_type, target_id, before_state, after_state, request_id, ip, created_at, prev_hash, hash`
+	auditQueueColumns = `id, actor, action, target_type, target_id, before_state, after_state, request_id, ip, created_at`
+)
+
+func (r *auditRepository) Enqueue(ctx context.Context, entry *model.AuditEntry) error {
+	query := `INSERT INTO audit_queue (actor, action, target_type, target_id, before_state, after_state, request_id, ip, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		entry.Actor,
+		entry.Action,
+		entry.TargetType,
+		entry.TargetID,
+		nullRawJSON(entry.Before),
+		nullRawJSON(entry.After),
+		entry.RequestID,
+		entry.IP,
+		entry.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	entry.QueueID, err = result.LastInsertId()
+	return err
+}
+
+func (r *auditRepository) ClaimQueued(ctx context.Context, limit int) ([]*model.AuditEntry, error) {
+	query := `SELECT ` + auditQueueColumns + ` FROM audit_queue ORDER BY id LIMIT ? FOR UPDATE`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*model.AuditEntry
+	for rows.Next() {
+		var entry model.AuditEntry
+		var before, after sql.NullString
+		err := rows.Scan(
+			&entry.QueueID,
+			&entry.Actor,
+			&entry.Action,
+			&entry.TargetType,
+			&entry.TargetID,
+			&before,
+			&after,
+			&entry.RequestID,
+			&entry.IP,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if before.Valid {
+			entry.Before = []byte(before.String)
+		}
+		if after.Valid {
+			entry.After = []byte(after.String)
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// audit_chain_head holds a single row, created by the migration
+func (r *auditRepository) LockHead(ctx context.Context) (model.AuditChainHead, error) {
+	return r.head(ctx, `SELECT last_seq, last_hash FROM audit_chain_head WHERE id = 1 FOR UPDATE`)
+}
+
+func (r *auditRepository) GetHead(ctx context.Context) (model.AuditChainHead, error) {
+	return r.head(ctx, `SELECT last_seq, last_hash FROM audit_chain_head WHERE id = 1`)
+}
+
+func (r *auditRepository) head(ctx context.Context, query string) (model.AuditChainHead, error) {
+	var head model.AuditChainHead
+	err := conn(ctx, r.db).QueryRowContext(ctx, query).Scan(&head.Seq, &head.Hash)
+	return head, err
+}
+
+func (r *auditRepository) Append(ctx context.Context, entry *model.AuditEntry) error {
+	query := `INSERT INTO audit_log (` + auditColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		entry.Seq,
+		entry.Actor,
+		entry.Action,
+		entry.TargetType,
+		entry.TargetID,
+		nullRawJSON(entry.Before),
+		nullRawJSON(entry.After),
+		entry.RequestID,
+		entry.IP,
+		entry.CreatedAt,
+		entry.PrevHash,
+		entry.Hash,
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, `UPDATE audit_chain_head SET last_seq = ?, last_hash = ? WHERE id = 1`, entry.Seq, entry.Hash)
+	if err != nil || entry.QueueID == 0 {
+		return err
+	}
+
+	_, err = conn(ctx, r.db).ExecContext(ctx, `DELETE FROM audit_queue WHERE id = ?`, entry.QueueID)
+	return err
+}
+
+func (r *auditRepository) FindEntries(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error) {
+	var conditions []string
+	var args []interface{}
+	for _, field := range []struct {
+		column string
+		value  string
+	}{
+		{"actor", filter.Actor},
+		{"action", filter.Action},
+		{"target_type", filter.TargetType},
+		{"target_id", filter.TargetID},
+		{"request_id", filter.RequestID},
+	} {
+		if field.value != "" {
+			conditions = append(conditions, field.column+" = ?")
+			args = append(args, field.value)
+		}
+	}
+	if filter.Since != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.Since)
+	}
+	if filter.Until != nil {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, *filter.Until)
+	}
+	if filter.BeforeSeq > 0 {
+		conditions = append(conditions, "seq < ?")
+		args = append(args, filter.BeforeSeq)
+	}
+
+	query := `SELECT ` + auditColumns + ` FROM audit_log`
+	if len(conditions) > 0 {
+		query += ` WHERE ` + strings.Join(conditions, " AND ")
+	}
+	query += ` ORDER BY seq DESC LIMIT ?`
+	args = append(args, filter.Limit)
+
+	return r.findEntries(ctx, query, args...)
+}
+
+func (r *auditRepository) FindEntriesAfter(ctx context.Context, afterSeq int64, limit int) ([]*model.AuditEntry, error) {
+	query := `SELECT ` + auditColumns + ` FROM audit_log WHERE seq > ? ORDER BY seq LIMIT ?`
+	return r.findEntries(ctx, query, afterSeq, limit)
+}
+
+func (r *auditRepository) findEntries(ctx context.Context, query string, args ...interface{}) ([]*model.AuditEntry, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*model.AuditEntry
+	for rows.Next() {
+		var entry model.AuditEntry
+		var before, after sql.NullString
+		err := rows.Scan(
+			&entry.Seq,
+			&entry.Actor,
+			&entry.Action,
+			&entry.TargetType,
+			&entry.TargetID,
+			&before,
+			&after,
+			&entry.RequestID,
+			&entry.IP,
+			&entry.CreatedAt,
+			&entry.PrevHash,
+			&entry.Hash,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if before.Valid {
+			entry.Before = []byte(before.String)
+		}
+		if after.Valid {
+			entry.After = []byte(after.String)
+		}
+		entries = append(entries, &entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// nullRawJSON stores snapshots as text rather than JSON so MySQL keeps the
+// exact bytes the hash was computed over
+func nullRawJSON(data []byte) sql.NullString {
+	if data == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: string(data), Valid: true}
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+type gachaPoolRepository struct {
+	db *sql.DB
+}
+
+func NewGachaPoolRepository(db *sql.DB) repository.GachaPoolRepository {
+	return &gachaPoolRepository{
+		db: db,
+	}
+}
+
+func (r *gachaPoolRepository) FindRates(ctx context.Context) ([]model.RarityRate, error) {
+	query := `SELECT rarity, weight, featured_share 
+		FROM gacha_pool_rates 
+		ORDER BY rarity`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rates []model.RarityRate
+	for rows.Next() {
+		var rate model.RarityRate
+		if err := rows.Scan(&rate.Rarity, &rate.Weight, &rate.FeaturedShare); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 注目アイテムは各アイテムの featured フラグから集める
+	featuredQuery := `SELECT id, rarity FROM gacha_items WHERE featured = TRUE ORDER BY id`
+	featuredRows, err := conn(ctx, r.db).QueryContext(ctx, featuredQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer featuredRows.Close()
+
+	for featuredRows.Next() {
+		var id int
+		var rarity model.Rarity
+		if err := featuredRows.Scan(&id, &rarity); err != nil {
+			return nil, err
+		}
+		for i := range rates {
+			if rates[i].Rarity == rarity {
+				rates[i].FeaturedIDs = append(rates[i].FeaturedIDs, id)
+			}
+		}
+	}
+
+	return rates, featuredRows.Err()
+}
+
+func (r *gachaPoolRepository) FindItems(ctx context.Context) ([]model.GachaItem, error) {
+	query := `SELECT id, name, rarity, points 
+		FROM gacha_items 
+		ORDER BY id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []model.GachaItem
+	for rows.Next() {
+		var item model.GachaItem
+		if err := rows.Scan(&item.ID, &item.Name, &item.Rarity, &item.Points); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	return items, rows.Err()
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
 	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
@@ -20,7 +21,7 @@ func NewGachaRepository(db *sql.DB) repository.GachaRepository {
 
 func (r *gachaRepository) SaveResult(ctx context.Context, result *model.GachaResult) error {
 	query := `INSERT INTO gacha_results (user_id, item_id, item_name, rarity, points_earned, created_at) VALUES (?, ?, ?, ?, ?, ?)`
-	res, err := r.db.ExecContext(ctx, query,
+	res, err := conn(ctx, r.db).ExecContext(ctx, query,
 		result.UserID,
 		result.ItemID,
 		result.ItemName,
@@ -48,7 +49,7 @@ func (r *gachaRepository) FindResultsByUserID(ctx context.Context, userID int, l
 		ORDER BY created_at DESC 
 		LIMIT ?`
 
-	rows, err := r.db.QueryContext(ctx, query, userID, limit)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -85,7 +86,7 @@ func (r *gachaRepository) FindResultByID(ctx context.Context, id int) (*model.Ga
 		WHERE id = ?`
 
 	var result model.GachaResult
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
 		&result.ID,
 		&result.UserID,
 		&result.ItemID,
@@ -102,4 +103,44 @@ func (r *gachaRepository) FindResultByID(ctx context.Context, id int) (*model.Ga
 	}
 
 	return &result, nil
+}
+
+func (r *gachaRepository) CountResultsByItem(ctx context.Context, userID int) (map[int]int, error) {
+	query := `SELECT item_id, COUNT(*) FROM gacha_results GROUP BY item_id`
+	args := []interface{}{}
+	if userID != 0 {
+		query = `SELECT item_id, COUNT(*) FROM gacha_results WHERE user_id = ? GROUP BY item_id`
+		args = append(args, userID)
+	}
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[int]int)
+	for rows.Next() {
+		var itemID, count int
+		if err := rows.Scan(&itemID, &count); err != nil {
+			return nil, err
+		}
+		counts[itemID] = count
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return counts, nil
+}
+
+func (r *gachaRepository) CountResultsSince(ctx context.Context, userID int, since time.Time) (int, error) {
+	query := `SELECT COUNT(*) FROM gacha_results WHERE user_id = ? AND created_at >= ?`
+
+	var count int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, userID, since).Scan(&count); err != nil {
+		return 0, err
+	}
+	return count, nil
 }
\ No newline at end of file
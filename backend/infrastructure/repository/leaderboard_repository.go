@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+// windowedBalanceQuery is each user's net change in points since the window start
+const windowedBalanceQuery = `SELECT user_id, SUM(amount) AS score 
+	FROM point_transactions 
+	WHERE created_at >= ? 
+	GROUP BY user_id`
+
+// leaderboardRepository serves gacha based metrics from the materialized
+// leaderboard_scores table and the balance metric straight from user_points
+// (all-time) or point_transactions (the net change over a window).
+type leaderboardRepository struct {
+	db         *sql.DB
+	transactor repository.Transactor
+}
+
+func NewLeaderboardRepository(db *sql.DB) repository.LeaderboardRepository {
+	return &leaderboardRepository{
+		db:         db,
+		transactor: NewTransactor(db),
+	}
+}
+
+func (r *leaderboardRepository) ApplyDeltas(ctx context.Context, deltas []model.LeaderboardScoreDelta) error {
+	query := `INSERT INTO leaderboard_scores (metric, period, period_start, user_id, score, updated_at) 
+		VALUES (?, ?, ?, ?, ?, ?) 
+		ON DUPLICATE KEY UPDATE score = score + VALUES(score), updated_at = VALUES(updated_at)`
+
+	now := time.Now()
+	for _, delta := range deltas {
+		_, err := conn(ctx, r.db).ExecContext(ctx, query,
+			delta.Metric,
+			delta.Period,
+			delta.PeriodStart.Format("2006-01-02"),
+			delta.UserID,
+			delta.Delta,
+			now,
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *leaderboardRepository) RebuildScores(ctx context.Context, metric model.LeaderboardMetric, period model.LeaderboardPeriod, periodStart time.Time) error {
+	var scoreExpr string
+	switch metric {
+	case model.LeaderboardMetricGachaPoints:
+		scoreExpr = `SUM(points_earned)`
+	case model.LeaderboardMetricLegendaryPulls:
+		scoreExpr = fmt.Sprintf(`SUM(CASE WHEN rarity = %d THEN 1 ELSE 0 END)`, model.RarityLegendary)
+	default:
+		return errors.New("leaderboard metric is not materialized")
+	}
+
+	start := periodStart.Format("2006-01-02")
+	return r.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		deleteQuery := `DELETE FROM leaderboard_scores WHERE metric = ? AND period = ? AND period_start = ?`
+		if _, err := conn(ctx, r.db).ExecContext(ctx, deleteQuery, metric, period, start); err != nil {
+			return err
+		}
+
+		insertQuery := `INSERT INTO leaderboard_scores (metric, period, period_start, user_id, score, updated_at) 
+			SELECT ?, ?, ?, user_id, ` + scoreExpr + `, NOW() 
+			FROM gacha_results 
+			WHERE created_at >= ? 
+			GROUP BY user_id 
+			HAVING ` + scoreExpr + ` > 0`
+		_, err := conn(ctx, r.db).ExecContext(ctx, insertQuery, metric, period, start, periodStart)
+		return err
+	})
+}
+
+func (r *leaderboardRepository) FindTop(ctx context.Context, metric model.LeaderboardMetric, period model.LeaderboardPeriod, periodStart time.Time, limit int) ([]model.LeaderboardEntry, error) {
+	var rows *sql.Rows
+	var err error
+	switch {
+	case metric == model.LeaderboardMetricBalance && period == model.LeaderboardPeriodAllTime:
+		query := `SELECT up.user_id, u.name, up.balance 
+			FROM user_points up 
+			JOIN users u ON u.id = up.user_id 
+			ORDER BY up.balance DESC, up.user_id ASC 
+			LIMIT ?`
+		rows, err = conn(ctx, r.db).QueryContext(ctx, query, limit)
+	case metric == model.LeaderboardMetricBalance:
+		query := `SELECT w.user_id, u.name, w.score 
+			FROM (` + windowedBalanceQuery + `) w 
+			JOIN users u ON u.id = w.user_id 
+			ORDER BY w.score DESC, w.user_id ASC 
+			LIMIT ?`
+		rows, err = conn(ctx, r.db).QueryContext(ctx, query, periodStart, limit)
+	default:
+		query := `SELECT ls.user_id, u.name, ls.score 
+			FROM leaderboard_scores ls 
+			JOIN users u ON u.id = ls.user_id 
+			WHERE ls.metric = ? AND ls.period = ? AND ls.period_start = ? 
+			ORDER BY ls.score DESC, ls.user_id ASC 
+			LIMIT ?`
+		rows, err = conn(ctx, r.db).QueryContext(ctx, query, metric, period, periodStart.Format("2006-01-02"), limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []model.LeaderboardEntry
+	for rows.Next() {
+		var entry model.LeaderboardEntry
+		if err := rows.Scan(&entry.UserID, &entry.UserName, &entry.Score); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *leaderboardRepository) FindUserRank(ctx context.Context, metric model.LeaderboardMetric, period model.LeaderboardPeriod, periodStart time.Time, userID int) (*model.LeaderboardEntry, error) {
+	var query string
+	var args []interface{}
+	switch {
+	case metric == model.LeaderboardMetricBalance && period == model.LeaderboardPeriodAllTime:
+		query = `SELECT up.user_id, u.name, up.balance, 
+				(SELECT COUNT(*) FROM user_points higher WHERE higher.balance > up.balance) + 1 
+			FROM user_points up 
+			JOIN users u ON u.id = up.user_id 
+			WHERE up.user_id = ?`
+		args = []interface{}{userID}
+	case metric == model.LeaderboardMetricBalance:
+		// 期間内に取引のないユーザーには順位がない
+		query = `SELECT w.user_id, u.name, w.score, 
+				(SELECT COUNT(*) FROM (` + windowedBalanceQuery + `) higher WHERE higher.score > w.score) + 1 
+			FROM (` + windowedBalanceQuery + `) w 
+			JOIN users u ON u.id = w.user_id 
+			WHERE w.user_id = ?`
+		args = []interface{}{periodStart, periodStart, userID}
+	default:
+		start := periodStart.Format("2006-01-02")
+		query = `SELECT ls.user_id, u.name, ls.score, 
+				(SELECT COUNT(*) FROM leaderboard_scores higher 
+				 WHERE higher.metric = ls.metric AND higher.period = ls.period 
+				   AND higher.period_start = ls.period_start AND higher.score > ls.score) + 1 
+			FROM leaderboard_scores ls 
+			JOIN users u ON u.id = ls.user_id 
+			WHERE ls.metric = ? AND ls.period = ? AND ls.period_start = ? AND ls.user_id = ?`
+		args = []interface{}{metric, period, start, userID}
+	}
+
+	var entry model.LeaderboardEntry
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, args...).Scan(
+		&entry.UserID,
+		&entry.UserName,
+		&entry.Score,
+		&entry.Rank,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
@@ -0,0 +1,197 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+type outboxRepository struct {
+	db         *sql.DB
+	transactor repository.Transactor
+}
+
+func NewOutboxRepository(db *sql.DB) repository.OutboxRepository {
+	return &outboxRepository{
+		db:         db,
+		transactor: NewTransactor(db),
+	}
+}
+
+func (r *outboxRepository) Append(ctx context.Context, events ...*model.OutboxEvent) error {
+	query := `INSERT INTO outbox_events (event_name, payload, status, attempts, next_attempt_at, created_at) VALUES (?, ?, ?, ?, ?, ?)`
+	for _, event := range events {
+		result, err := conn(ctx, r.db).ExecContext(ctx, query,
+			event.Name,
+			event.Payload,
+			event.Status,
+			event.Attempts,
+			event.NextAttemptAt,
+			event.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		event.ID = id
+	}
+	return nil
+}
+
+func (r *outboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.OutboxEvent, error) {
+	var events []*model.OutboxEvent
+	err := r.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		query := `SELECT id, event_name, payload, status, attempts, next_attempt_at, last_error, created_at, delivered_at 
+			FROM outbox_events 
+			WHERE status = ? AND next_attempt_at <= ? 
+			ORDER BY id 
+			LIMIT ? 
+			FOR UPDATE SKIP LOCKED`
+
+		var err error
+		events, err = r.findEvents(ctx, query, model.OutboxStatusPending, now, limit)
+		if err != nil {
+			return err
+		}
+
+		leaseQuery := `UPDATE outbox_events SET next_attempt_at = ? WHERE id = ?`
+		for _, event := range events {
+			if _, err := conn(ctx, r.db).ExecContext(ctx, leaseQuery, now.Add(lease), event.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *outboxRepository) UpdateDelivery(ctx context.Context, event *model.OutboxEvent) error {
+	query := `UPDATE outbox_events 
+		SET status = ?, attempts = ?, next_attempt_at = ?, last_error = ?, delivered_at = ? 
+		WHERE id = ?`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		event.Status,
+		event.Attempts,
+		event.NextAttemptAt,
+		sql.NullString{String: event.LastError, Valid: event.LastError != ""},
+		event.DeliveredAt,
+		event.ID,
+	)
+	return err
+}
+
+func (r *outboxRepository) MarkConsumed(ctx context.Context, eventID int64, consumer string) (bool, error) {
+	query := `INSERT IGNORE INTO outbox_consumptions (event_id, consumer, consumed_at) VALUES (?, ?, ?)`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, eventID, consumer, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+func (r *outboxRepository) GetStats(ctx context.Context, failureLimit int) (*model.OutboxStats, error) {
+	var stats model.OutboxStats
+
+	countQuery := `SELECT status, COUNT(*) FROM outbox_events GROUP BY status`
+	rows, err := conn(ctx, r.db).QueryContext(ctx, countQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var status model.OutboxStatus
+		var count int
+		if err := rows.Scan(&status, &count); err != nil {
+			return nil, err
+		}
+		switch status {
+		case model.OutboxStatusPending:
+			stats.Pending = count
+		case model.OutboxStatusDelivered:
+			stats.Delivered = count
+		case model.OutboxStatusFailed:
+			stats.Failed = count
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	oldestQuery := `SELECT MIN(created_at) FROM outbox_events WHERE status = ?`
+	var oldest sql.NullTime
+	if err := conn(ctx, r.db).QueryRowContext(ctx, oldestQuery, model.OutboxStatusPending).Scan(&oldest); err != nil {
+		return nil, err
+	}
+	if oldest.Valid {
+		stats.OldestPending = &oldest.Time
+	}
+
+	failureQuery := `SELECT id, event_name, payload, status, attempts, next_attempt_at, last_error, created_at, delivered_at 
+		FROM outbox_events 
+		WHERE status = ? 
+		ORDER BY id DESC 
+		LIMIT ?`
+	stats.RecentFailures, err = r.findEvents(ctx, failureQuery, model.OutboxStatusFailed, failureLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stats, nil
+}
+
+func (r *outboxRepository) findEvents(ctx context.Context, query string, args ...interface{}) ([]*model.OutboxEvent, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*model.OutboxEvent
+	for rows.Next() {
+		var event model.OutboxEvent
+		var lastError sql.NullString
+		var deliveredAt sql.NullTime
+		err := rows.Scan(
+			&event.ID,
+			&event.Name,
+			&event.Payload,
+			&event.Status,
+			&event.Attempts,
+			&event.NextAttemptAt,
+			&lastError,
+			&event.CreatedAt,
+			&deliveredAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		event.LastError = lastError.String
+		if deliveredAt.Valid {
+			event.DeliveredAt = &deliveredAt.Time
+		}
+		events = append(events, &event)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+type pointAdjustmentRepository struct {
+	db *sql.DB
+}
+
+func NewPointAdjustmentRepository(db *sql.DB) repository.PointAdjustmentRepository {
+	return &pointAdjustmentRepository{
+		db: db,
+	}
+}
+
+func (r *pointAdjustmentRepository) Save(ctx context.Context, adjustment *model.PointAdjustment) error {
+	query := `INSERT INTO point_adjustments (transaction_id, user_id, amount, reason_code, reason, admin_id, limit_override, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		adjustment.TransactionID,
+		adjustment.UserID,
+		adjustment.Amount,
+		adjustment.ReasonCode,
+		adjustment.Reason,
+		adjustment.AdminID,
+		adjustment.LimitOverride,
+		adjustment.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	adjustment.ID = int(id)
+	return nil
+}
+
+func (r *pointAdjustmentRepository) FindAdjustments(ctx context.Context, userID int, limit int) ([]*model.PointAdjustment, error) {
+	query := `SELECT id, transaction_id, user_id, amount, reason_code, reason, admin_id, limit_override, created_at 
+		FROM point_adjustments 
+		WHERE (? = 0 OR user_id = ?) 
+		ORDER BY created_at DESC, id DESC 
+		LIMIT ?`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var adjustments []*model.PointAdjustment
+	for rows.Next() {
+		var adjustment model.PointAdjustment
+		err := rows.Scan(
+			&adjustment.ID,
+			&adjustment.TransactionID,
+			&adjustment.UserID,
+			&adjustment.Amount,
+			&adjustment.ReasonCode,
+			&adjustment.Reason,
+			&adjustment.AdminID,
+			&adjustment.LimitOverride,
+			&adjustment.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		adjustments = append(adjustments, &adjustment)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return adjustments, nil
+}
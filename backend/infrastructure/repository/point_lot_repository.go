@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+type pointLotRepository struct {
+	db *sql.DB
+}
+
+func NewPointLotRepository(db *sql.DB) repository.PointLotRepository {
+	return &pointLotRepository{
+		db: db,
+	}
+}
+
+func (r *pointLotRepository) CreateLot(ctx context.Context, lot *model.PointLot) error {
+	query := `INSERT INTO point_lots (user_id, transaction_id, amount, remaining, earned_at, expires_at) VALUES (?, ?, ?, ?, ?, ?)`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		lot.UserID,
+		sql.NullInt64{Int64: int64(lot.TransactionID), Valid: lot.TransactionID != 0},
+		lot.Amount,
+		lot.Remaining,
+		lot.EarnedAt,
+		lot.ExpiresAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	lot.ID = int(id)
+	return nil
+}
+
+func (r *pointLotRepository) UpdateLot(ctx context.Context, lot *model.PointLot) error {
+	query := `UPDATE point_lots SET remaining = ? WHERE id = ?`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, lot.Remaining, lot.ID)
+	return err
+}
+
+func (r *pointLotRepository) FindActiveLotsForUpdate(ctx context.Context, userID int) ([]*model.PointLot, error) {
+	query := `SELECT id, user_id, transaction_id, amount, remaining, earned_at, expires_at 
+		FROM point_lots 
+		WHERE user_id = ? AND remaining > 0 
+		ORDER BY expires_at ASC, id ASC 
+		FOR UPDATE`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var lots []*model.PointLot
+	for rows.Next() {
+		var lot model.PointLot
+		var transactionID sql.NullInt64
+		err := rows.Scan(
+			&lot.ID,
+			&lot.UserID,
+			&transactionID,
+			&lot.Amount,
+			&lot.Remaining,
+			&lot.EarnedAt,
+			&lot.ExpiresAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		lot.TransactionID = int(transactionID.Int64)
+		lots = append(lots, &lot)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return lots, nil
+}
+
+func (r *pointLotRepository) FindUserIDsWithExpiredLots(ctx context.Context, now time.Time, limit int) ([]int, error) {
+	query := `SELECT DISTINCT user_id 
+		FROM point_lots 
+		WHERE remaining > 0 AND expires_at <= ? 
+		ORDER BY user_id 
+		LIMIT ?`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, now, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userIDs []int
+	for rows.Next() {
+		var userID int
+		if err := rows.Scan(&userID); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, userID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return userIDs, nil
+}
+
+func (r *pointLotRepository) FindUpcomingExpirations(ctx context.Context, userID int, from, until time.Time) ([]model.PointExpiration, error) {
+	query := `SELECT SUM(remaining), expires_at 
+		FROM point_lots 
+		WHERE user_id = ? AND remaining > 0 AND expires_at > ? AND expires_at <= ? 
+		GROUP BY expires_at 
+		ORDER BY expires_at ASC`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, from, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var expirations []model.PointExpiration
+	for rows.Next() {
+		var expiration model.PointExpiration
+		if err := rows.Scan(&expiration.Amount, &expiration.ExpiresAt); err != nil {
+			return nil, err
+		}
+		expirations = append(expirations, expiration)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return expirations, nil
+}
@@ -3,28 +3,43 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 
 	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
 	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
 )
 
 type pointRepository struct {
-	db *sql.DB
+	db         *sql.DB
+	transactor repository.Transactor
 }
 
 func NewPointRepository(db *sql.DB) repository.PointRepository {
 	return &pointRepository{
-		db: db,
+		db:         db,
+		transactor: NewTransactor(db),
 	}
 }
 
 func (r *pointRepository) GetUserPoint(ctx context.Context, userID int) (*model.UserPoint, error) {
-	query := `SELECT id, user_id, balance, updated_at FROM user_points WHERE user_id = ?`
+	query := `SELECT id, user_id, balance, frozen_at, frozen_reason, updated_at FROM user_points WHERE user_id = ?`
+	return r.findUserPoint(ctx, query, userID)
+}
+
+func (r *pointRepository) GetUserPointForUpdate(ctx context.Context, userID int) (*model.UserPoint, error) {
+	query := `SELECT id, user_id, balance, frozen_at, frozen_reason, updated_at FROM user_points WHERE user_id = ? FOR UPDATE`
+	return r.findUserPoint(ctx, query, userID)
+}
+
+func (r *pointRepository) findUserPoint(ctx context.Context, query string, userID int) (*model.UserPoint, error) {
 	var userPoint model.UserPoint
-	err := r.db.QueryRowContext(ctx, query, userID).Scan(
+	var frozenAt sql.NullTime
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID).Scan(
 		&userPoint.ID,
 		&userPoint.UserID,
 		&userPoint.Balance,
+		&frozenAt,
+		&userPoint.FrozenReason,
 		&userPoint.UpdatedAt,
 	)
 	if err != nil {
@@ -33,12 +48,15 @@ func (r *pointRepository) GetUserPoint(ctx context.Context, userID int) (*model.
 		}
 		return nil, err
 	}
+	if frozenAt.Valid {
+		userPoint.FrozenAt = &frozenAt.Time
+	}
 	return &userPoint, nil
 }
 
 func (r *pointRepository) CreateUserPoint(ctx context.Context, userPoint *model.UserPoint) error {
-	query := `INSERT INTO user_points (user_id, balance, updated_at) VALUES (?, ?, ?)`
-	result, err := r.db.ExecContext(ctx, query, userPoint.UserID, userPoint.Balance, userPoint.UpdatedAt)
+	query := `INSERT INTO user_points (user_id, balance, frozen_at, frozen_reason, updated_at) VALUES (?, ?, ?, ?, ?)`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, userPoint.UserID, userPoint.Balance, nullTime(userPoint.FrozenAt), userPoint.FrozenReason, userPoint.UpdatedAt)
 	if err != nil {
 		return err
 	}
@@ -53,41 +71,52 @@ func (r *pointRepository) CreateUserPoint(ctx context.Context, userPoint *model.
 }
 
 func (r *pointRepository) UpdateUserPoint(ctx context.Context, userPoint *model.UserPoint) error {
-	query := `UPDATE user_points SET balance = ?, updated_at = ? WHERE id = ?`
-	_, err := r.db.ExecContext(ctx, query, userPoint.Balance, userPoint.UpdatedAt, userPoint.ID)
+	query := `UPDATE user_points SET balance = ?, frozen_at = ?, frozen_reason = ?, updated_at = ? WHERE id = ?`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, userPoint.Balance, nullTime(userPoint.FrozenAt), userPoint.FrozenReason, userPoint.UpdatedAt, userPoint.ID)
 	return err
 }
 
+// SaveTransaction stores the transaction together with its balanced ledger entries
 func (r *pointRepository) SaveTransaction(ctx context.Context, transaction *model.PointTransaction) error {
-	query := `INSERT INTO point_transactions (user_id, amount, type, description, created_at) VALUES (?, ?, ?, ?, ?)`
-	result, err := r.db.ExecContext(ctx, query,
-		transaction.UserID,
-		transaction.Amount,
-		transaction.Type,
-		transaction.Description,
-		transaction.CreatedAt,
-	)
-	if err != nil {
-		return err
-	}
+	return r.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		query := `INSERT INTO point_transactions (user_id, amount, type, description, transfer_id, balance_after, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+		result, err := conn(ctx, r.db).ExecContext(ctx, query,
+			transaction.UserID,
+			transaction.Amount,
+			transaction.Type,
+			transaction.Description,
+			sql.NullString{String: transaction.TransferID, Valid: transaction.TransferID != ""},
+			transaction.BalanceAfter,
+			transaction.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
 
-	id, err := result.LastInsertId()
-	if err != nil {
-		return err
-	}
+		id, err := result.LastInsertId()
+		if err != nil {
+			return err
+		}
+		transaction.ID = int(id)
 
-	transaction.ID = int(id)
-	return nil
+		entryQuery := `INSERT INTO ledger_entries (transaction_id, account, amount, created_at) VALUES (?, ?, ?, ?)`
+		for _, entry := range transaction.LedgerEntries() {
+			if _, err := conn(ctx, r.db).ExecContext(ctx, entryQuery, entry.TransactionID, entry.Account, entry.Amount, entry.CreatedAt); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 func (r *pointRepository) FindTransactionsByUserID(ctx context.Context, userID int, limit int) ([]*model.PointTransaction, error) {
-	query := `SELECT id, user_id, amount, type, description, created_at 
+	query := `SELECT id, user_id, amount, type, description, transfer_id, balance_after, created_at 
 		FROM point_transactions 
 		WHERE user_id = ? 
 		ORDER BY created_at DESC 
 		LIMIT ?`
 
-	rows, err := r.db.QueryContext(ctx, query, userID, limit)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, limit)
 	if err != nil {
 		return nil, err
 	}
@@ -96,17 +125,23 @@ func (r *pointRepository) FindTransactionsByUserID(ctx context.Context, userID i
 	var transactions []*model.PointTransaction
 	for rows.Next() {
 		var tx model.PointTransaction
+		var transferID sql.NullString
+		var balanceAfter sql.NullInt64
 		err := rows.Scan(
 			&tx.ID,
 			&tx.UserID,
 			&tx.Amount,
 			&tx.Type,
 			&tx.Description,
+			&transferID,
+			&balanceAfter,
 			&tx.CreatedAt,
 		)
 		if err != nil {
 			return nil, err
 		}
+		tx.TransferID = transferID.String
+		tx.BalanceAfter = int(balanceAfter.Int64)
 		transactions = append(transactions, &tx)
 	}
 
@@ -115,4 +150,132 @@ func (r *pointRepository) FindTransactionsByUserID(ctx context.Context, userID i
 	}
 
 	return transactions, nil
+}
+
+func (r *pointRepository) SumTransactionsSince(ctx context.Context, userID int, transactionType model.TransactionType, since time.Time) (int, error) {
+	query := `SELECT COALESCE(SUM(amount), 0) 
+		FROM point_transactions 
+		WHERE user_id = ? AND type = ? AND created_at >= ?`
+
+	var total int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, userID, transactionType, since).Scan(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *pointRepository) FindAllUserPoints(ctx context.Context) ([]*model.UserPoint, error) {
+	query := `SELECT id, user_id, balance, frozen_at, frozen_reason, updated_at FROM user_points ORDER BY user_id`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var userPoints []*model.UserPoint
+	for rows.Next() {
+		var userPoint model.UserPoint
+		var frozenAt sql.NullTime
+		err := rows.Scan(
+			&userPoint.ID,
+			&userPoint.UserID,
+			&userPoint.Balance,
+			&frozenAt,
+			&userPoint.FrozenReason,
+			&userPoint.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if frozenAt.Valid {
+			userPoint.FrozenAt = &frozenAt.Time
+		}
+		userPoints = append(userPoints, &userPoint)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return userPoints, nil
+}
+
+func (r *pointRepository) GetLedgerBalance(ctx context.Context, account model.LedgerAccount) (int, error) {
+	query := `SELECT COALESCE(SUM(balance), 0) FROM account_balances WHERE account = ?`
+
+	var balance int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query, account).Scan(&balance); err != nil {
+		return 0, err
+	}
+	return balance, nil
+}
+
+func (r *pointRepository) FindUserLedgerBalances(ctx context.Context) (map[int]int, error) {
+	query := `SELECT account, balance FROM account_balances WHERE account LIKE 'user:%'`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	balances := make(map[int]int)
+	for rows.Next() {
+		var account model.LedgerAccount
+		var balance int
+		if err := rows.Scan(&account, &balance); err != nil {
+			return nil, err
+		}
+		if userID, ok := account.UserID(); ok {
+			balances[userID] = balance
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return balances, nil
+}
+
+func (r *pointRepository) SumLedger(ctx context.Context) (int, error) {
+	query := `SELECT COALESCE(SUM(amount), 0) FROM ledger_entries`
+
+	var total int
+	if err := conn(ctx, r.db).QueryRowContext(ctx, query).Scan(&total); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func (r *pointRepository) FindUnbalancedTransactionIDs(ctx context.Context, limit int) ([]int, error) {
+	query := `SELECT pt.id 
+		FROM point_transactions pt 
+		LEFT JOIN ledger_entries le ON le.transaction_id = pt.id 
+		GROUP BY pt.id 
+		HAVING COUNT(le.id) = 0 OR SUM(le.amount) <> 0 
+		ORDER BY pt.id 
+		LIMIT ?`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var transactionIDs []int
+	for rows.Next() {
+		var transactionID int
+		if err := rows.Scan(&transactionID); err != nil {
+			return nil, err
+		}
+		transactionIDs = append(transactionIDs, transactionID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return transactionIDs, nil
 }
\ No newline at end of file
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+type rarityTierRepository struct {
+	db *sql.DB
+}
+
+func NewRarityTierRepository(db *sql.DB) repository.RarityTierRepository {
+	return &rarityTierRepository{
+		db: db,
+	}
+}
+
+func (r *rarityTierRepository) FindAll(ctx context.Context) ([]model.RarityTier, error) {
+	query := `SELECT id, name, tier_rank, color, min_points, max_points 
+		FROM rarity_tiers 
+		ORDER BY tier_rank`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tiers []model.RarityTier
+	for rows.Next() {
+		var tier model.RarityTier
+		if err := rows.Scan(
+			&tier.Rarity,
+			&tier.Name,
+			&tier.Rank,
+			&tier.Color,
+			&tier.MinPoints,
+			&tier.MaxPoints,
+		); err != nil {
+			return nil, err
+		}
+		tiers = append(tiers, tier)
+	}
+
+	return tiers, rows.Err()
+}
@@ -0,0 +1,181 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+const rateTableColumns = `version, effective_from, rarity_rates, items, pity_rules, checksum`
+
+// rateTableItem is the stored form of an item. Versions published before
+// weights were integers store Probability instead of Weight.
+type rateTableItem struct {
+	ID          int
+	Name        string
+	Rarity      model.Rarity
+	Points      int
+	Weight      int
+	Probability float64 `json:",omitempty"`
+}
+
+type rateTableRepository struct {
+	db         *sql.DB
+	transactor repository.Transactor
+}
+
+func NewRateTableRepository(db *sql.DB) repository.RateTableRepository {
+	return &rateTableRepository{
+		db:         db,
+		transactor: NewTransactor(db),
+	}
+}
+
+func (r *rateTableRepository) PublishIfChanged(ctx context.Context, table *model.RateTable) (*model.RateTable, error) {
+	var current *model.RateTable
+	err := r.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// 最新版をロックして、同時に起動したインスタンスが同じ版番号を使わないようにする
+		query := `SELECT ` + rateTableColumns + ` FROM gacha_rate_tables ORDER BY version DESC LIMIT 1 FOR UPDATE`
+		tables, err := r.findTables(ctx, query)
+		if err != nil {
+			return err
+		}
+		if len(tables) > 0 && tables[0].Checksum == table.Checksum {
+			current = tables[0]
+			return nil
+		}
+
+		table.Version = 1
+		if len(tables) > 0 {
+			table.Version = tables[0].Version + 1
+		}
+
+		stored := make([]rateTableItem, 0, len(table.Items))
+		for _, item := range table.Items {
+			stored = append(stored, rateTableItem{
+				ID:     item.ID,
+				Name:   item.Name,
+				Rarity: item.Rarity,
+				Points: item.Points,
+				Weight: item.Weight,
+			})
+		}
+		rates, err := json.Marshal(table.Rates)
+		if err != nil {
+			return err
+		}
+		items, err := json.Marshal(stored)
+		if err != nil {
+			return err
+		}
+		pityRules, err := json.Marshal(table.PityRules)
+		if err != nil {
+			return err
+		}
+
+		insertQuery := `INSERT INTO gacha_rate_tables (version, effective_from, rarity_rates, items, pity_rules, checksum) VALUES (?, ?, ?, ?, ?, ?)`
+		if _, err := conn(ctx, r.db).ExecContext(ctx, insertQuery,
+			table.Version,
+			table.EffectiveFrom,
+			rates,
+			items,
+			pityRules,
+			table.Checksum,
+		); err != nil {
+			return err
+		}
+		current = table
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return current, nil
+}
+
+func (r *rateTableRepository) FindByVersion(ctx context.Context, version int) (*model.RateTable, error) {
+	query := `SELECT ` + rateTableColumns + ` FROM gacha_rate_tables WHERE version = ?`
+	return r.findTable(ctx, query, version)
+}
+
+func (r *rateTableRepository) FindEffectiveAt(ctx context.Context, t time.Time) (*model.RateTable, error) {
+	query := `SELECT ` + rateTableColumns + ` 
+		FROM gacha_rate_tables 
+		WHERE effective_from <= ? 
+		ORDER BY version DESC 
+		LIMIT 1`
+	return r.findTable(ctx, query, t)
+}
+
+func (r *rateTableRepository) FindAll(ctx context.Context) ([]*model.RateTable, error) {
+	query := `SELECT ` + rateTableColumns + ` FROM gacha_rate_tables ORDER BY version DESC`
+	return r.findTables(ctx, query)
+}
+
+func (r *rateTableRepository) findTable(ctx context.Context, query string, args ...interface{}) (*model.RateTable, error) {
+	tables, err := r.findTables(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return nil, nil
+	}
+	return tables[0], nil
+}
+
+func (r *rateTableRepository) findTables(ctx context.Context, query string, args ...interface{}) ([]*model.RateTable, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []*model.RateTable
+	for rows.Next() {
+		table := &model.RateTable{}
+		var rates, items, pityRules []byte
+		if err := rows.Scan(
+			&table.Version,
+			&table.EffectiveFrom,
+			&rates,
+			&items,
+			&pityRules,
+			&table.Checksum,
+		); err != nil {
+			return nil, err
+		}
+		// 2 段階抽選の導入前の版はレアリティ別の確率を持たない
+		if rates != nil {
+			if err := json.Unmarshal(rates, &table.Rates); err != nil {
+				return nil, err
+			}
+		}
+		var stored []rateTableItem
+		if err := json.Unmarshal(items, &stored); err != nil {
+			return nil, err
+		}
+		for _, item := range stored {
+			if item.Weight == 0 && item.Probability > 0 {
+				item.Weight = model.WeightFromProbability(item.Probability)
+			}
+			table.Items = append(table.Items, model.GachaItem{
+				ID:     item.ID,
+				Name:   item.Name,
+				Rarity: item.Rarity,
+				Points: item.Points,
+				Weight: item.Weight,
+			})
+		}
+		if err := json.Unmarshal(pityRules, &table.PityRules); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, rows.Err()
+}
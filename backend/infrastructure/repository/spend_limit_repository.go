@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+type spendLimitRepository struct {
+	db *sql.DB
+}
+
+func NewSpendLimitRepository(db *sql.DB) repository.SpendLimitRepository {
+	return &spendLimitRepository{
+		db: db,
+	}
+}
+
+const spendLimitColumns = `user_id, daily_spins, pending_daily_spins, pending_daily_spins_at, 
+	weekly_spend, pending_weekly_spend, pending_weekly_spend_at, cool_off_until, self_excluded_until, updated_at`
+
+func (r *spendLimitRepository) FindByUserID(ctx context.Context, userID int) (*model.SpendLimits, error) {
+	query := `SELECT ` + spendLimitColumns + ` FROM spend_limits WHERE user_id = ?`
+	return r.find(ctx, query, userID)
+}
+
+func (r *spendLimitRepository) FindByUserIDForUpdate(ctx context.Context, userID int) (*model.SpendLimits, error) {
+	query := `SELECT ` + spendLimitColumns + ` FROM spend_limits WHERE user_id = ? FOR UPDATE`
+	return r.find(ctx, query, userID)
+}
+
+func (r *spendLimitRepository) find(ctx context.Context, query string, userID int) (*model.SpendLimits, error) {
+	var limits model.SpendLimits
+	var pendingDaily, pendingWeekly sql.NullInt64
+	var pendingDailyAt, pendingWeeklyAt, coolOffUntil, selfExcludedUntil sql.NullTime
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, userID).Scan(
+		&limits.UserID,
+		&limits.DailySpins,
+		&pendingDaily,
+		&pendingDailyAt,
+		&limits.WeeklySpend,
+		&pendingWeekly,
+		&pendingWeeklyAt,
+		&coolOffUntil,
+		&selfExcludedUntil,
+		&limits.UpdatedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if pendingDaily.Valid && pendingDailyAt.Valid {
+		limits.PendingDailySpins = &model.PendingLimit{Value: int(pendingDaily.Int64), EffectiveAt: pendingDailyAt.Time}
+	}
+	if pendingWeekly.Valid && pendingWeeklyAt.Valid {
+		limits.PendingWeekly = &model.PendingLimit{Value: int(pendingWeekly.Int64), EffectiveAt: pendingWeeklyAt.Time}
+	}
+	if coolOffUntil.Valid {
+		limits.CoolOffUntil = &coolOffUntil.Time
+	}
+	if selfExcludedUntil.Valid {
+		limits.SelfExcludedUntil = &selfExcludedUntil.Time
+	}
+
+	return &limits, nil
+}
+
+func (r *spendLimitRepository) Save(ctx context.Context, limits *model.SpendLimits) error {
+	query := `INSERT INTO spend_limits (` + spendLimitColumns + `) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?) 
+		ON DUPLICATE KEY UPDATE daily_spins = VALUES(daily_spins), pending_daily_spins = VALUES(pending_daily_spins), 
+		pending_daily_spins_at = VALUES(pending_daily_spins_at), weekly_spend = VALUES(weekly_spend), 
+		pending_weekly_spend = VALUES(pending_weekly_spend), pending_weekly_spend_at = VALUES(pending_weekly_spend_at), 
+		cool_off_until = VALUES(cool_off_until), self_excluded_until = VALUES(self_excluded_until), updated_at = VALUES(updated_at)`
+
+	pendingDaily, pendingDailyAt := pendingLimitColumns(limits.PendingDailySpins)
+	pendingWeekly, pendingWeeklyAt := pendingLimitColumns(limits.PendingWeekly)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		limits.UserID,
+		limits.DailySpins,
+		pendingDaily,
+		pendingDailyAt,
+		limits.WeeklySpend,
+		pendingWeekly,
+		pendingWeeklyAt,
+		nullTime(limits.CoolOffUntil),
+		nullTime(limits.SelfExcludedUntil),
+		limits.UpdatedAt,
+	)
+	return err
+}
+
+func pendingLimitColumns(pending *model.PendingLimit) (sql.NullInt64, sql.NullTime) {
+	if pending == nil {
+		return sql.NullInt64{}, sql.NullTime{}
+	}
+	return sql.NullInt64{Int64: int64(pending.Value), Valid: true}, sql.NullTime{Time: pending.EffectiveAt, Valid: true}
+}
+
+func nullTime(t *time.Time) sql.NullTime {
+	if t == nil {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: *t, Valid: true}
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/logging"
+)
+
+type txKey struct{}
+
+// dbtx is the subset of *sql.DB and *sql.Tx used by the repositories
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// conn returns the transaction bound to ctx, falling back to the shared pool.
+// Failed statements are logged here, with the request's attributes and the
+// statement, so repositories return driver errors unwrapped and do not log
+// them again.
+func conn(ctx context.Context, db *sql.DB) dbtx {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return loggedConn{tx}
+	}
+	return loggedConn{db}
+}
+
+// loggedConn logs the statements of conn that fail
+type loggedConn struct {
+	dbtx
+}
+
+func (c loggedConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	result, err := c.dbtx.ExecContext(ctx, query, args...)
+	logQueryError(ctx, query, err)
+	return result, err
+}
+
+func (c loggedConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	rows, err := c.dbtx.QueryContext(ctx, query, args...)
+	logQueryError(ctx, query, err)
+	return rows, err
+}
+
+func (c loggedConn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	row := c.dbtx.QueryRowContext(ctx, query, args...)
+	// Err は Scan の結果を消費せず、sql.ErrNoRows も返さない
+	logQueryError(ctx, query, row.Err())
+	return row
+}
+
+// logQueryError logs a failed statement. Queries canceled with their
+// request are left to the caller, as the client is already gone.
+func logQueryError(ctx context.Context, query string, err error) {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+	logging.FromContext(ctx).Error("Query failed", "query", strings.Join(strings.Fields(query), " "), logging.KeyError, err)
+}
+
+type transactor struct {
+	db *sql.DB
+}
+
+func NewTransactor(db *sql.DB) repository.Transactor {
+	return &transactor{
+		db: db,
+	}
+}
+
+// WithinTransaction commits when fn succeeds and rolls back otherwise.
+// Nested calls join the outer transaction.
+func (t *transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return fn(ctx)
+	}
+
+	tx, err := t.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		// 呼び出し元の context が終了した場合は database/sql が既にロールバックしている
+		if rollbackErr := tx.Rollback(); rollbackErr != nil && !errors.Is(rollbackErr, sql.ErrTxDone) {
+			logging.FromContext(ctx).Error("Transaction rollback failed", logging.KeyError, rollbackErr)
+		}
+		logging.FromContext(ctx).Debug("Transaction rolled back", logging.KeyError, err)
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		logging.FromContext(ctx).Error("Transaction commit failed", logging.KeyError, err)
+		return err
+	}
+	return nil
+}
@@ -19,8 +19,11 @@ func NewUserRepository(db *sql.DB) repository.UserRepository {
 }
 
 func (r *userRepository) Create(ctx context.Context, user *model.User) error {
-	query := `INSERT INTO users (name, created_at, updated_at) VALUES (?, ?, ?)`
-	result, err := r.db.ExecContext(ctx, query, user.Name, user.CreatedAt, user.UpdatedAt)
+	if user.Status == "" {
+		user.Status = model.UserStatusActive
+	}
+	query := `INSERT INTO users (name, region, signup_ip, signup_user_agent, status, token_hash, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, user.Name, user.Region, user.SignupIP, user.SignupUserAgent, user.Status, user.TokenHash, user.CreatedAt, user.UpdatedAt)
 	if err != nil {
 		return err
 	}
@@ -35,11 +38,33 @@ func (r *userRepository) Create(ctx context.Context, user *model.User) error {
 }
 
 func (r *userRepository) FindByID(ctx context.Context, id int) (*model.User, error) {
-	query := `SELECT id, name, created_at, updated_at FROM users WHERE id = ?`
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = ?`
+	return r.findUser(ctx, query, id)
+}
+
+func (r *userRepository) FindByIDForUpdate(ctx context.Context, id int) (*model.User, error) {
+	query := `SELECT ` + userColumns + ` FROM users WHERE id = ? FOR UPDATE`
+	return r.findUser(ctx, query, id)
+}
+
+const userColumns = `id, name, region, region_verified_at, birth_date, age_verified_at, signup_ip, signup_user_agent, status, status_reason, status_until, token_hash, created_at, updated_at`
+
+func (r *userRepository) findUser(ctx context.Context, query string, id int) (*model.User, error) {
 	var user model.User
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	var regionVerifiedAt, birthDate, ageVerifiedAt, statusUntil sql.NullTime
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
 		&user.ID,
 		&user.Name,
+		&user.Region,
+		&regionVerifiedAt,
+		&birthDate,
+		&ageVerifiedAt,
+		&user.SignupIP,
+		&user.SignupUserAgent,
+		&user.Status,
+		&user.StatusReason,
+		&statusUntil,
+		&user.TokenHash,
 		&user.CreatedAt,
 		&user.UpdatedAt,
 	)
@@ -49,11 +74,49 @@ func (r *userRepository) FindByID(ctx context.Context, id int) (*model.User, err
 		}
 		return nil, err
 	}
+	if regionVerifiedAt.Valid {
+		user.RegionVerifiedAt = &regionVerifiedAt.Time
+	}
+	if birthDate.Valid {
+		user.BirthDate = &birthDate.Time
+	}
+	if ageVerifiedAt.Valid {
+		user.AgeVerifiedAt = &ageVerifiedAt.Time
+	}
+	if statusUntil.Valid {
+		user.StatusUntil = &statusUntil.Time
+	}
 	return &user, nil
 }
 
 func (r *userRepository) Update(ctx context.Context, user *model.User) error {
-	query := `UPDATE users SET name = ?, updated_at = ? WHERE id = ?`
-	_, err := r.db.ExecContext(ctx, query, user.Name, user.UpdatedAt, user.ID)
+	query := `UPDATE users SET name = ?, region = ?, region_verified_at = ?, birth_date = ?, age_verified_at = ?, updated_at = ? WHERE id = ?`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		user.Name,
+		user.Region,
+		nullTime(user.RegionVerifiedAt),
+		nullTime(user.BirthDate),
+		nullTime(user.AgeVerifiedAt),
+		user.UpdatedAt,
+		user.ID,
+	)
+	return err
+}
+
+func (r *userRepository) UpdateStatus(ctx context.Context, user *model.User) error {
+	query := `UPDATE users SET status = ?, status_reason = ?, status_until = ?, updated_at = ? WHERE id = ?`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		user.Status,
+		user.StatusReason,
+		nullTime(user.StatusUntil),
+		user.UpdatedAt,
+		user.ID,
+	)
 	return err
-}
\ No newline at end of file
+}
+
+func (r *userRepository) UpdateToken(ctx context.Context, user *model.User) error {
+	query := `UPDATE users SET token_hash = ?, updated_at = ? WHERE id = ?`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, user.TokenHash, user.UpdatedAt, user.ID)
+	return err
+}
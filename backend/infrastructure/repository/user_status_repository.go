@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+type userStatusRepository struct {
+	db *sql.DB
+}
+
+func NewUserStatusRepository(db *sql.DB) repository.UserStatusRepository {
+	return &userStatusRepository{
+		db: db,
+	}
+}
+
+func (r *userStatusRepository) SaveChange(ctx context.Context, change *model.UserStatusChange) error {
+	query := `INSERT INTO user_status_changes (user_id, from_status, to_status, reason, until, admin_id, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		change.UserID,
+		change.FromStatus,
+		change.ToStatus,
+		change.Reason,
+		nullTime(change.Until),
+		change.AdminID,
+		change.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	change.ID = int(id)
+	return nil
+}
+
+func (r *userStatusRepository) FindChangesByUserID(ctx context.Context, userID int, limit int) ([]*model.UserStatusChange, error) {
+	query := `SELECT id, user_id, from_status, to_status, reason, until, admin_id, created_at 
+		FROM user_status_changes 
+		WHERE user_id = ? 
+		ORDER BY created_at DESC, id DESC 
+		LIMIT ?`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var changes []*model.UserStatusChange
+	for rows.Next() {
+		var change model.UserStatusChange
+		var until sql.NullTime
+		err := rows.Scan(
+			&change.ID,
+			&change.UserID,
+			&change.FromStatus,
+			&change.ToStatus,
+			&change.Reason,
+			&until,
+			&change.AdminID,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		if until.Valid {
+			change.Until = &until.Time
+		}
+		changes = append(changes, &change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
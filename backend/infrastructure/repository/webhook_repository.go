@@ -0,0 +1,258 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+const webhookDeliveryColumns = `id, subscription_id, event_id, event_name, payload, status, attempts, next_attempt_at, last_status_code, last_error, created_at, delivered_at`
+
+type webhookRepository struct {
+	db         *sql.DB
+	transactor repository.Transactor
+}
+
+func NewWebhookRepository(db *sql.DB) repository.WebhookRepository {
+	return &webhookRepository{
+		db:         db,
+		transactor: NewTransactor(db),
+	}
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, subscription *model.WebhookSubscription) error {
+	query := `INSERT INTO webhook_subscriptions (url, secret, event_names, filter, active, created_by, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		subscription.URL,
+		subscription.Secret,
+		strings.Join(subscription.EventNames, ","),
+		subscription.Filter.Raw,
+		subscription.Active,
+		subscription.CreatedBy,
+		subscription.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+
+	subscription.ID = int(id)
+	return nil
+}
+
+func (r *webhookRepository) FindSubscriptionByID(ctx context.Context, id int) (*model.WebhookSubscription, error) {
+	query := `SELECT id, url, secret, event_names, filter, active, created_by, created_at 
+		FROM webhook_subscriptions 
+		WHERE id = ?`
+
+	subscriptions, err := r.findSubscriptions(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(subscriptions) == 0 {
+		return nil, nil
+	}
+	return subscriptions[0], nil
+}
+
+func (r *webhookRepository) FindSubscriptions(ctx context.Context, activeOnly bool) ([]*model.WebhookSubscription, error) {
+	query := `SELECT id, url, secret, event_names, filter, active, created_by, created_at 
+		FROM webhook_subscriptions 
+		WHERE active = TRUE OR ? = FALSE 
+		ORDER BY id`
+	return r.findSubscriptions(ctx, query, activeOnly)
+}
+
+func (r *webhookRepository) findSubscriptions(ctx context.Context, query string, args ...interface{}) ([]*model.WebhookSubscription, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subscriptions []*model.WebhookSubscription
+	for rows.Next() {
+		var subscription model.WebhookSubscription
+		var eventNames, filter string
+		err := rows.Scan(
+			&subscription.ID,
+			&subscription.URL,
+			&subscription.Secret,
+			&eventNames,
+			&filter,
+			&subscription.Active,
+			&subscription.CreatedBy,
+			&subscription.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		subscription.EventNames = strings.Split(eventNames, ",")
+		if subscription.Filter, err = model.ParseWebhookFilter(filter); err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, &subscription)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return subscriptions, nil
+}
+
+func (r *webhookRepository) EnqueueDelivery(ctx context.Context, delivery *model.WebhookDelivery) (bool, error) {
+	query := `INSERT IGNORE INTO webhook_deliveries (subscription_id, event_id, event_name, payload, status, attempts, next_attempt_at, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		delivery.SubscriptionID,
+		sql.NullInt64{Int64: delivery.EventID, Valid: delivery.EventID != 0},
+		delivery.EventName,
+		delivery.Payload,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		delivery.CreatedAt,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, nil
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return false, err
+	}
+
+	delivery.ID = id
+	return true, nil
+}
+
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.WebhookDelivery, error) {
+	var deliveries []*model.WebhookDelivery
+	err := r.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		query := `SELECT ` + webhookDeliveryColumns + ` 
+			FROM webhook_deliveries 
+			WHERE status = ? AND next_attempt_at <= ? 
+			ORDER BY id 
+			LIMIT ? 
+			FOR UPDATE SKIP LOCKED`
+
+		var err error
+		deliveries, err = r.findDeliveries(ctx, query, model.WebhookDeliveryPending, now, limit)
+		if err != nil {
+			return err
+		}
+
+		leaseQuery := `UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ?`
+		for _, delivery := range deliveries {
+			if _, err := conn(ctx, r.db).ExecContext(ctx, leaseQuery, now.Add(lease), delivery.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
+
+func (r *webhookRepository) FindDeliveryByID(ctx context.Context, id int64) (*model.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE id = ?`
+
+	deliveries, err := r.findDeliveries(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		return nil, nil
+	}
+	return deliveries[0], nil
+}
+
+func (r *webhookRepository) FindDeadDeliveries(ctx context.Context, subscriptionID int, limit int) ([]*model.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` 
+		FROM webhook_deliveries 
+		WHERE status = ? AND (subscription_id = ? OR ? = 0) 
+		ORDER BY id DESC 
+		LIMIT ?`
+	return r.findDeliveries(ctx, query, model.WebhookDeliveryDead, subscriptionID, subscriptionID, limit)
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	query := `UPDATE webhook_deliveries 
+		SET status = ?, attempts = ?, next_attempt_at = ?, last_status_code = ?, last_error = ?, delivered_at = ? 
+		WHERE id = ?`
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt,
+		sql.NullInt64{Int64: int64(delivery.LastStatusCode), Valid: delivery.LastStatusCode != 0},
+		sql.NullString{String: delivery.LastError, Valid: delivery.LastError != ""},
+		delivery.DeliveredAt,
+		delivery.ID,
+	)
+	return err
+}
+
+func (r *webhookRepository) findDeliveries(ctx context.Context, query string, args ...interface{}) ([]*model.WebhookDelivery, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*model.WebhookDelivery
+	for rows.Next() {
+		var delivery model.WebhookDelivery
+		var eventID, lastStatusCode sql.NullInt64
+		var lastError sql.NullString
+		var deliveredAt sql.NullTime
+		err := rows.Scan(
+			&delivery.ID,
+			&delivery.SubscriptionID,
+			&eventID,
+			&delivery.EventName,
+			&delivery.Payload,
+			&delivery.Status,
+			&delivery.Attempts,
+			&delivery.NextAttemptAt,
+			&lastStatusCode,
+			&lastError,
+			&delivery.CreatedAt,
+			&deliveredAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		delivery.EventID = eventID.Int64
+		delivery.LastStatusCode = int(lastStatusCode.Int64)
+		delivery.LastError = lastError.String
+		if deliveredAt.Valid {
+			delivery.DeliveredAt = &deliveredAt.Time
+		}
+		deliveries = append(deliveries, &delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
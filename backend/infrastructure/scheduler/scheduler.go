@@ -0,0 +1,31 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/logging"
+)
+
+// Every runs fn immediately and then once per interval until ctx is cancelled.
+// Each run logs with the job's name. Errors are logged and do not stop the
+// schedule.
+func Every(ctx context.Context, name string, interval time.Duration, fn func(ctx context.Context) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	logger := slog.Default().With("job", name)
+	for {
+		runCtx := logging.NewContext(ctx, logger)
+		if err := fn(runCtx); err != nil && ctx.Err() == nil {
+			logging.FromContext(runCtx).Error("Scheduled job failed", logging.KeyError, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
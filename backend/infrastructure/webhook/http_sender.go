@@ -0,0 +1,52 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	usecase "github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/webhook"
+)
+
+// maxResponseDrain bounds how much of a receiver's response is read so the
+// connection can be reused
+const maxResponseDrain = 64 << 10
+
+type httpSender struct {
+	client *http.Client
+}
+
+// NewHTTPSender returns a Sender that POSTs with the given per-request timeout
+func NewHTTPSender(timeout time.Duration) usecase.Sender {
+	return &httpSender{
+		client: &http.Client{
+			Timeout: timeout,
+			// リダイレクト先に署名付きペイロードを送らない
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (s *httpSender) Send(ctx context.Context, req usecase.Request) (int, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return 0, err
+	}
+	httpReq.Header.Set("User-Agent", "FortuneSpinner-Webhook/1.0")
+	for key, value := range req.Headers {
+		httpReq.Header.Set(key, value)
+	}
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseDrain))
+
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,294 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/audit"
+	usecase "github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/webhook"
+)
+
+const testSecret = "receiver-secret"
+
+// receivedRequest is what the test receiver saw of one delivery
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+// receiver answers with the given status codes in turn, repeating the last one
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []receivedRequest
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	rc.mu.Lock()
+	rc.requests = append(rc.requests, receivedRequest{header: r.Header.Clone(), body: body})
+	status := rc.statuses[0]
+	if len(rc.statuses) > 1 {
+		rc.statuses = rc.statuses[1:]
+	}
+	rc.mu.Unlock()
+
+	w.WriteHeader(status)
+}
+
+func (rc *receiver) received() []receivedRequest {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return append([]receivedRequest{}, rc.requests...)
+}
+
+// fakeWebhookRepository keeps one subscription and its deliveries in memory
+type fakeWebhookRepository struct {
+	subscription *model.WebhookSubscription
+	deliveries   []*model.WebhookDelivery
+}
+
+func (r *fakeWebhookRepository) CreateSubscription(ctx context.Context, subscription *model.WebhookSubscription) error {
+	r.subscription = subscription
+	return nil
+}
+
+func (r *fakeWebhookRepository) FindSubscriptionByID(ctx context.Context, id int) (*model.WebhookSubscription, error) {
+	if r.subscription == nil || r.subscription.ID != id {
+		return nil, nil
+	}
+	return r.subscription, nil
+}
+
+func (r *fakeWebhookRepository) FindSubscriptions(ctx context.Context, activeOnly bool) ([]*model.WebhookSubscription, error) {
+	return []*model.WebhookSubscription{r.subscription}, nil
+}
+
+func (r *fakeWebhookRepository) EnqueueDelivery(ctx context.Context, delivery *model.WebhookDelivery) (bool, error) {
+	delivery.ID = int64(len(r.deliveries) + 1)
+	r.deliveries = append(r.deliveries, delivery)
+	return true, nil
+}
+
+func (r *fakeWebhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.WebhookDelivery, error) {
+	var due []*model.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status == model.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) && len(due) < limit {
+			// ユースケースは返されたコピーを更新し、UpdateDelivery で保存する
+			claimed := *delivery
+			delivery.NextAttemptAt = now.Add(lease)
+			due = append(due, &claimed)
+		}
+	}
+	return due, nil
+}
+
+func (r *fakeWebhookRepository) FindDeliveryByID(ctx context.Context, id int64) (*model.WebhookDelivery, error) {
+	for _, delivery := range r.deliveries {
+		if delivery.ID == id {
+			return delivery, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeWebhookRepository) FindDeadDeliveries(ctx context.Context, subscriptionID int, limit int) ([]*model.WebhookDelivery, error) {
+	var dead []*model.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status == model.WebhookDeliveryDead {
+			dead = append(dead, delivery)
+		}
+	}
+	return dead, nil
+}
+
+func (r *fakeWebhookRepository) UpdateDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	for i := range r.deliveries {
+		if r.deliveries[i].ID == delivery.ID {
+			updated := *delivery
+			r.deliveries[i] = &updated
+		}
+	}
+	return nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type fakeRecorder struct{}
+
+func (fakeRecorder) Record(ctx context.Context, change audit.Change) error {
+	return nil
+}
+
+// newDeliveryFixture queues one delivery for a subscription pointing at the receiver
+func newDeliveryFixture(t *testing.T, rc *receiver) (*fakeWebhookRepository, usecase.WebhookUsecase) {
+	t.Helper()
+	server := httptest.NewServer(rc)
+	t.Cleanup(server.Close)
+
+	repo := &fakeWebhookRepository{
+		subscription: &model.WebhookSubscription{
+			ID:         1,
+			URL:        server.URL,
+			Secret:     testSecret,
+			EventNames: []string{"gacha.executed"},
+			Active:     true,
+		},
+	}
+	repo.EnqueueDelivery(context.Background(), model.NewWebhookDelivery(1, 42, "gacha.executed", []byte(`{"rarity":4}`)))
+
+	// フィルタのない購読なので、レアリティのティアは使われない
+	uc := usecase.NewWebhookUsecase(repo, fakeTransactor{}, fakeRecorder{}, NewHTTPSender(5*time.Second), nil)
+	return repo, uc
+}
+
+func TestDeliveryIsSigned(t *testing.T) {
+	rc := &receiver{statuses: []int{http.StatusNoContent}}
+	repo, uc := newDeliveryFixture(t, rc)
+
+	delivered, err := uc.DeliverPending(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if delivered != 1 {
+		t.Fatalf("delivered %d, want 1", delivered)
+	}
+
+	requests := rc.received()
+	if len(requests) != 1 {
+		t.Fatalf("receiver got %d requests, want 1", len(requests))
+	}
+	req := requests[0]
+
+	// 受信側と同じ手順で署名を検証する
+	mac := hmac.New(sha256.New, []byte(testSecret))
+	mac.Write([]byte(req.header.Get("X-FortuneSpinner-Timestamp") + "."))
+	mac.Write(req.body)
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if got := req.header.Get("X-FortuneSpinner-Signature"); !hmac.Equal([]byte(got), []byte(want)) {
+		t.Errorf("signature = %q, want %q", got, want)
+	}
+
+	mac = hmac.New(sha256.New, []byte("another-secret"))
+	mac.Write([]byte(req.header.Get("X-FortuneSpinner-Timestamp") + "."))
+	mac.Write(req.body)
+	if req.header.Get("X-FortuneSpinner-Signature") == "sha256="+hex.EncodeToString(mac.Sum(nil)) {
+		t.Error("signature does not depend on the secret")
+	}
+
+	for header, want := range map[string]string{
+		"Content-Type":              "application/json",
+		"X-FortuneSpinner-Event":    "gacha.executed",
+		"X-FortuneSpinner-Delivery": "1",
+	} {
+		if got := req.header.Get(header); got != want {
+			t.Errorf("%s = %q, want %q", header, got, want)
+		}
+	}
+
+	delivery := repo.deliveries[0]
+	if delivery.Status != model.WebhookDeliveryDelivered || delivery.Attempts != 1 || delivery.LastStatusCode != http.StatusNoContent {
+		t.Errorf("delivery not marked delivered: %+v", delivery)
+	}
+}
+
+func TestServerErrorsAreRetriedWithBackoff(t *testing.T) {
+	rc := &receiver{statuses: []int{http.StatusInternalServerError, http.StatusBadGateway, http.StatusOK}}
+	repo, uc := newDeliveryFixture(t, rc)
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		before := time.Now()
+		delivered, err := uc.DeliverPending(context.Background())
+		after := time.Now()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if delivered != 0 {
+			t.Fatalf("attempt %d: delivered %d, want 0", attempt, delivered)
+		}
+
+		delivery := repo.deliveries[0]
+		if delivery.Status != model.WebhookDeliveryPending || delivery.Attempts != attempt {
+			t.Fatalf("attempt %d: delivery %+v is not pending a retry", attempt, delivery)
+		}
+		backoff := model.ExponentialBackoff(attempt, 5*time.Second, 30*time.Minute)
+		if delivery.NextAttemptAt.Before(before.Add(backoff)) || delivery.NextAttemptAt.After(after.Add(backoff)) {
+			t.Errorf("attempt %d: next attempt in %s, want %s", attempt, delivery.NextAttemptAt.Sub(before), backoff)
+		}
+
+		// バックオフ中は送信されない
+		if _, err := uc.DeliverPending(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if got := len(rc.received()); got != attempt {
+			t.Fatalf("receiver got %d requests during backoff, want %d", got, attempt)
+		}
+		delivery.NextAttemptAt = time.Now().Add(-time.Second)
+	}
+
+	if delivered, err := uc.DeliverPending(context.Background()); err != nil || delivered != 1 {
+		t.Fatalf("third attempt delivered %d, err %v", delivered, err)
+	}
+	if delivery := repo.deliveries[0]; delivery.Status != model.WebhookDeliveryDelivered || delivery.Attempts != 3 {
+		t.Errorf("delivery %+v not delivered on the third attempt", delivery)
+	}
+}
+
+func TestDeliveryIsDeadLetteredAfterMaxAttempts(t *testing.T) {
+	rc := &receiver{statuses: []int{http.StatusServiceUnavailable}}
+	repo, uc := newDeliveryFixture(t, rc)
+
+	for attempt := 1; attempt <= model.MaxWebhookAttempts; attempt++ {
+		if _, err := uc.DeliverPending(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		delivery := repo.deliveries[0]
+		if delivery.Attempts != attempt {
+			t.Fatalf("attempt %d: delivery has %d attempts", attempt, delivery.Attempts)
+		}
+		if attempt < model.MaxWebhookAttempts {
+			if delivery.Status != model.WebhookDeliveryPending {
+				t.Fatalf("attempt %d: delivery is %s before reaching the limit", attempt, delivery.Status)
+			}
+			delivery.NextAttemptAt = time.Now().Add(-time.Second)
+		}
+	}
+
+	delivery := repo.deliveries[0]
+	if delivery.Status != model.WebhookDeliveryDead {
+		t.Fatalf("delivery is %s after %d attempts, want dead", delivery.Status, model.MaxWebhookAttempts)
+	}
+	if delivery.LastStatusCode != http.StatusServiceUnavailable || delivery.LastError == "" {
+		t.Errorf("dead delivery lost its last failure: %+v", delivery)
+	}
+
+	// 配信不能になった配信は再送されない
+	if _, err := uc.DeliverPending(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(rc.received()); got != model.MaxWebhookAttempts {
+		t.Errorf("receiver got %d requests, want %d", got, model.MaxWebhookAttempts)
+	}
+
+	dead, err := uc.ListDeadLetters(context.Background(), 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(dead) != 1 || dead[0].ID != delivery.ID {
+		t.Errorf("dead-letter list = %+v, want delivery %d", dead, delivery.ID)
+	}
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/logging"
+)
+
+// LogRequests starts a logging scope for the request, tagged with the ID set
+// by WithClientInfo, and writes an access log line when it finishes. Server
+// errors are logged at error level with their message and, when the use case
+// added them, the user ID and operation.
+func LogRequests(logger *slog.Logger, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		client := clientFromContext(r.Context())
+		ctx := logging.NewContext(r.Context(), logger.With(logging.KeyRequestID, client.RequestID))
+
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next(recorder, r.WithContext(ctx))
+
+		level := slog.LevelInfo
+		if recorder.status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		attrs := []interface{}{
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", recorder.status,
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+			"bytes", recorder.bytes,
+			"ip", client.IP,
+		}
+		if recorder.err != "" {
+			attrs = append(attrs, logging.KeyError, recorder.err)
+		}
+		logging.FromContext(ctx).Log(ctx, level, "Request handled", attrs...)
+	}
+}
+
+// responseRecorder captures what the handler wrote for the access log
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	bytes       int
+	err         string
+	wroteHeader bool
+}
+
+func (rec *responseRecorder) WriteHeader(status int) {
+	if !rec.wroteHeader {
+		rec.status = status
+		rec.wroteHeader = true
+	}
+	rec.ResponseWriter.WriteHeader(status)
+}
+
+func (rec *responseRecorder) Write(b []byte) (int, error) {
+	rec.wroteHeader = true
+	n, err := rec.ResponseWriter.Write(b)
+	rec.bytes += n
+	return n, err
+}
+
+// Flush keeps server-sent event streams working through the recorder
+func (rec *responseRecorder) Flush() {
+	if flusher, ok := rec.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack keeps WebSocket upgrades working through the recorder
+func (rec *responseRecorder) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := rec.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errors.New("response does not support hijacking")
+	}
+	rec.status = http.StatusSwitchingProtocols
+	rec.wroteHeader = true
+	return hijacker.Hijack()
+}
+
+func (rec *responseRecorder) Unwrap() http.ResponseWriter {
+	return rec.ResponseWriter
+}
+
+// recordError keeps the error message of a response for the access log
+func recordError(w http.ResponseWriter, message string) {
+	if recorder, ok := w.(*responseRecorder); ok {
+		recorder.err = message
+	}
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/achievement"
+)
+
+type AchievementHandler struct {
+	achievementUsecase achievement.AchievementUsecase
+}
+
+func NewAchievementHandler(achievementUsecase achievement.AchievementUsecase) *AchievementHandler {
+	return &AchievementHandler{
+		achievementUsecase: achievementUsecase,
+	}
+}
+
+type AchievementResponse struct {
+	Code         string     `json:"code"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description"`
+	RewardPoints int        `json:"reward_points"`
+	Unlocked     bool       `json:"unlocked"`
+	UnlockedAt   *time.Time `json:"unlocked_at,omitempty"`
+}
+
+// GetUserAchievements serves GET /api/users/{id}/achievements
+func (h *AchievementHandler) GetUserAchievements(w http.ResponseWriter, r *http.Request, userID int) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	statuses, err := h.achievementUsecase.GetUserAchievements(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := make([]AchievementResponse, 0, len(statuses))
+	for _, status := range statuses {
+		item := AchievementResponse{
+			Code:         status.Rule.Code,
+			Name:         status.Rule.Name,
+			Description:  status.Rule.Description,
+			RewardPoints: status.Rule.RewardPoints,
+			Unlocked:     status.Unlocked != nil,
+		}
+		if status.Unlocked != nil {
+			unlockedAt := status.Unlocked.UnlockedAt
+			item.UnlockedAt = &unlockedAt
+		}
+		response = append(response, item)
+	}
+
+	respondSuccess(w, response)
+}
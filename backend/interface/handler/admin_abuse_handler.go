@@ -0,0 +1,158 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/abuse"
+)
+
+type AdminAbuseHandler struct {
+	abuseUsecase abuse.AbuseUsecase
+}
+
+func NewAdminAbuseHandler(abuseUsecase abuse.AbuseUsecase) *AdminAbuseHandler {
+	return &AdminAbuseHandler{
+		abuseUsecase: abuseUsecase,
+	}
+}
+
+type ReviewAbuseFlagRequest struct {
+	Action string `json:"action"` // dismiss or freeze
+	Note   string `json:"note"`
+}
+
+type AbuseFlagResponse struct {
+	ID         int        `json:"id"`
+	UserID     int        `json:"user_id"`
+	Score      int        `json:"score"`
+	Reasons    []string   `json:"reasons"`
+	Throttled  bool       `json:"throttled"`
+	Status     string     `json:"status"`
+	ReviewedBy string     `json:"reviewed_by,omitempty"`
+	ReviewNote string     `json:"review_note,omitempty"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// HandleFlags routes the review queue:
+//
+//	GET  /api/admin/abuse/flags?status=open&limit=50
+//	POST /api/admin/abuse/flags/{id}/review
+func (h *AdminAbuseHandler) HandleFlags(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/abuse/flags"), "/")
+	if path == "" {
+		h.ListFlags(w, r)
+		return
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) != 2 || parts[1] != "review" {
+		respondError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	flagID, err := strconv.Atoi(parts[0])
+	if err != nil || flagID <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid flag ID")
+		return
+	}
+	h.ReviewFlag(w, r, flagID)
+}
+
+func (h *AdminAbuseHandler) ListFlags(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	status := model.AbuseFlagOpen
+	if statusStr := r.URL.Query().Get("status"); statusStr != "" {
+		status = model.AbuseFlagStatus(statusStr)
+		if status != model.AbuseFlagOpen && status != model.AbuseFlagDismissed && status != model.AbuseFlagFrozen {
+			respondError(w, http.StatusBadRequest, "status must be open, dismissed or frozen")
+			return
+		}
+	}
+
+	limitStr := r.URL.Query().Get("limit")
+	limit := 50 // デフォルト値
+	if limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 && parsedLimit <= 200 {
+			limit = parsedLimit
+		}
+	}
+
+	flags, err := h.abuseUsecase.ListFlags(r.Context(), status, limit)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := make([]AbuseFlagResponse, 0, len(flags))
+	for _, flag := range flags {
+		response = append(response, toAbuseFlagResponse(flag))
+	}
+
+	respondSuccess(w, response)
+}
+
+func (h *AdminAbuseHandler) ReviewFlag(w http.ResponseWriter, r *http.Request, flagID int) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req ReviewAbuseFlagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	action := abuse.ReviewAction(req.Action)
+	if action != abuse.ReviewDismiss && action != abuse.ReviewFreeze {
+		respondError(w, http.StatusBadRequest, "action must be dismiss or freeze")
+		return
+	}
+	if len(req.Note) > 500 {
+		respondError(w, http.StatusBadRequest, "note must be at most 500 characters long")
+		return
+	}
+
+	flag, err := h.abuseUsecase.ReviewFlag(r.Context(), flagID, action, adminFromContext(r.Context()), req.Note)
+	if err != nil {
+		switch {
+		case errors.Is(err, abuse.ErrFlagNotFound):
+			respondError(w, http.StatusNotFound, err.Error())
+		case errors.Is(err, model.ErrAbuseFlagReviewed):
+			respondError(w, http.StatusConflict, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondSuccess(w, toAbuseFlagResponse(flag))
+}
+
+func toAbuseFlagResponse(flag *model.AbuseFlag) AbuseFlagResponse {
+	return AbuseFlagResponse{
+		ID:         flag.ID,
+		UserID:     flag.UserID,
+		Score:      flag.Score,
+		Reasons:    flag.Reasons,
+		Throttled:  flag.Throttled,
+		Status:     string(flag.Status),
+		ReviewedBy: flag.ReviewedBy,
+		ReviewNote: flag.ReviewNote,
+		ReviewedAt: flag.ReviewedAt,
+		CreatedAt:  flag.CreatedAt,
+		UpdatedAt:  flag.UpdatedAt,
+	}
+}
@@ -0,0 +1,151 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/audit"
+)
+
+type AdminAuditHandler struct {
+	auditUsecase audit.AuditUsecase
+}
+
+func NewAdminAuditHandler(auditUsecase audit.AuditUsecase) *AdminAuditHandler {
+	return &AdminAuditHandler{
+		auditUsecase: auditUsecase,
+	}
+}
+
+type AuditEntryResponse struct {
+	Seq        int64           `json:"seq"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   string          `json:"target_id,omitempty"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	RequestID  string          `json:"request_id,omitempty"`
+	IP         string          `json:"ip,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	PrevHash   string          `json:"prev_hash"`
+	Hash       string          `json:"hash"`
+}
+
+type AuditVerifyResponse struct {
+	Checked  int64  `json:"checked"`
+	Valid    bool   `json:"valid"`
+	BrokenAt int64  `json:"broken_at,omitempty"`
+	HeadSeq  int64  `json:"head_seq"`
+	HeadHash string `json:"head_hash"`
+}
+
+// HandleAudit lists audit entries newest first:
+//
+//	GET /api/admin/audit?actor=admin:ops&action=points.adjust&target_type=user&target_id=1
+//	    &request_id=...&since=RFC3339&until=RFC3339&before_seq=100&limit=100
+func (h *AdminAuditHandler) HandleAudit(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+	filter := model.AuditFilter{
+		Actor:      query.Get("actor"),
+		Action:     query.Get("action"),
+		TargetType: query.Get("target_type"),
+		TargetID:   query.Get("target_id"),
+		RequestID:  query.Get("request_id"),
+	}
+
+	for _, bound := range []struct {
+		name   string
+		target **time.Time
+	}{
+		{"since", &filter.Since},
+		{"until", &filter.Until},
+	} {
+		value := query.Get(bound.name)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid "+bound.name+", expected an RFC 3339 time")
+			return
+		}
+		*bound.target = &parsed
+	}
+
+	if beforeStr := query.Get("before_seq"); beforeStr != "" {
+		beforeSeq, err := strconv.ParseInt(beforeStr, 10, 64)
+		if err != nil || beforeSeq <= 0 {
+			respondError(w, http.StatusBadRequest, "Invalid before_seq")
+			return
+		}
+		filter.BeforeSeq = beforeSeq
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			filter.Limit = parsedLimit
+		}
+	}
+
+	entries, err := h.auditUsecase.Search(r.Context(), filter)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := make([]AuditEntryResponse, 0, len(entries))
+	for _, entry := range entries {
+		response = append(response, toAuditEntryResponse(entry))
+	}
+
+	respondSuccess(w, response)
+}
+
+// VerifyChain handles GET /api/admin/audit/verify
+func (h *AdminAuditHandler) VerifyChain(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	result, err := h.auditUsecase.Verify(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, AuditVerifyResponse{
+		Checked:  result.Checked,
+		Valid:    result.Valid,
+		BrokenAt: result.BrokenAt,
+		HeadSeq:  result.Head.Seq,
+		HeadHash: result.Head.Hash,
+	})
+}
+
+func toAuditEntryResponse(entry *model.AuditEntry) AuditEntryResponse {
+	// nil の変更前後は null として出力される
+	return AuditEntryResponse{
+		Seq:        entry.Seq,
+		Actor:      entry.Actor,
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetID:   entry.TargetID,
+		Before:     entry.Before,
+		After:      entry.After,
+		RequestID:  entry.RequestID,
+		IP:         entry.IP,
+		CreatedAt:  entry.CreatedAt,
+		PrevHash:   entry.PrevHash,
+		Hash:       entry.Hash,
+	}
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/audit"
+)
+
+type adminKey struct{}
+
+// RequireAdmin only lets requests through that present one of the admin
+// tokens as a bearer token. The acting admin is the one the token belongs
+// to, so every admin action is attributed to the credential that made it.
+// No tokens disables the admin API entirely.
+func RequireAdmin(tokens map[string]string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(tokens) == 0 {
+			respondError(w, http.StatusServiceUnavailable, "Admin API is disabled")
+			return
+		}
+
+		adminID, ok := matchAdminToken(tokens, bearerToken(r))
+		if !ok {
+			respondError(w, http.StatusUnauthorized, "Unauthorized")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), adminKey{}, adminID)
+		next(w, r.WithContext(audit.WithAdmin(ctx, adminID)))
+	}
+}
+
+// matchAdminToken returns the admin whose token was presented. Every token
+// is compared in constant time, without stopping at a match, so the
+// response time does not reveal how much of a token was guessed.
+func matchAdminToken(tokens map[string]string, presented string) (string, bool) {
+	matched := ""
+	for token, adminID := range tokens {
+		if subtle.ConstantTimeCompare([]byte(presented), []byte(token)) == 1 {
+			matched = adminID
+		}
+	}
+	return matched, matched != ""
+}
+
+// bearerToken returns the token of an "Authorization: Bearer" header
+func bearerToken(r *http.Request) string {
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+// adminFromContext returns the acting admin set by RequireAdmin
+func adminFromContext(ctx context.Context) string {
+	adminID, _ := ctx.Value(adminKey{}).(string)
+	return adminID
+}
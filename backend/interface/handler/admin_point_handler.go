@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/point"
+)
+
+type AdminPointHandler struct {
+	adjustmentUsecase point.AdjustmentUsecase
+}
+
+func NewAdminPointHandler(adjustmentUsecase point.AdjustmentUsecase) *AdminPointHandler {
+	return &AdminPointHandler{
+		adjustmentUsecase: adjustmentUsecase,
+	}
+}
+
+type AdjustPointsRequest struct {
+	UserID     int    `json:"user_id"`
+	Amount     int    `json:"amount"`
+	ReasonCode string `json:"reason_code"`
+	Reason     string `json:"reason"`
+	Override   bool   `json:"override"`
+}
+
+type AdjustmentResponse struct {
+	ID            int       `json:"id"`
+	TransactionID int       `json:"transaction_id"`
+	UserID        int       `json:"user_id"`
+	Amount        int       `json:"amount"`
+	ReasonCode    string    `json:"reason_code"`
+	Reason        string    `json:"reason"`
+	AdminID       string    `json:"admin_id"`
+	Override      bool      `json:"override"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+func (h *AdminPointHandler) AdjustPoints(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req AdjustPointsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.UserID <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if req.Amount == 0 {
+		respondError(w, http.StatusBadRequest, "Invalid amount")
+		return
+	}
+
+	if req.ReasonCode == "" || req.Reason == "" {
+		respondError(w, http.StatusBadRequest, "Reason code and reason are required")
+		return
+	}
+
+	adjustment, err := h.adjustmentUsecase.AdjustPoints(r.Context(), point.AdjustPointsInput{
+		UserID:        req.UserID,
+		Amount:        req.Amount,
+		ReasonCode:    model.AdjustmentReasonCode(req.ReasonCode),
+		Reason:        req.Reason,
+		AdminID:       adminFromContext(r.Context()),
+		OverrideLimit: req.Override,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidAdjustment):
+			respondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, model.ErrAdjustmentOverLimit), errors.Is(err, model.ErrInsufficientPoints), errors.Is(err, model.ErrMaxBalanceExceeded):
+			respondError(w, http.StatusConflict, err.Error())
+		case errors.Is(err, model.ErrUserNotFound):
+			respondError(w, http.StatusNotFound, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondSuccess(w, newAdjustmentResponse(adjustment))
+}
+
+func (h *AdminPointHandler) ListAdjustments(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userID := 0
+	if userIDStr := r.URL.Query().Get("user_id"); userIDStr != "" {
+		parsedUserID, err := strconv.Atoi(userIDStr)
+		if err != nil || parsedUserID <= 0 {
+			respondError(w, http.StatusBadRequest, "Invalid user ID")
+			return
+		}
+		userID = parsedUserID
+	}
+
+	limitStr := r.URL.Query().Get("limit")
+	limit := 50 // デフォルト値
+	if limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	adjustments, err := h.adjustmentUsecase.ListAdjustments(r.Context(), userID, limit)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := make([]AdjustmentResponse, 0)
+	for _, adjustment := range adjustments {
+		response = append(response, newAdjustmentResponse(adjustment))
+	}
+
+	respondSuccess(w, response)
+}
+
+func newAdjustmentResponse(adjustment *model.PointAdjustment) AdjustmentResponse {
+	return AdjustmentResponse{
+		ID:            adjustment.ID,
+		TransactionID: adjustment.TransactionID,
+		UserID:        adjustment.UserID,
+		Amount:        adjustment.Amount,
+		ReasonCode:    string(adjustment.ReasonCode),
+		Reason:        adjustment.Reason,
+		AdminID:       adjustment.AdminID,
+		Override:      adjustment.LimitOverride,
+		CreatedAt:     adjustment.CreatedAt,
+	}
+}
@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/abuse"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/user"
+)
+
+type AdminUserHandler struct {
+	userUsecase  user.UserUsecase
+	abuseUsecase abuse.AbuseUsecase
+}
+
+func NewAdminUserHandler(userUsecase user.UserUsecase, abuseUsecase abuse.AbuseUsecase) *AdminUserHandler {
+	return &AdminUserHandler{
+		userUsecase:  userUsecase,
+		abuseUsecase: abuseUsecase,
+	}
+}
+
+// UpdateVerificationRequest changes only the fields that are present.
+// BirthDate is a YYYY-MM-DD date confirmed by an identity check.
+type UpdateVerificationRequest struct {
+	Region    *string `json:"region"`
+	BirthDate *string `json:"birth_date"`
+}
+
+// ChangeStatusRequest sets the account status. Until is an optional RFC3339
+// expiry for freezes and bans.
+type ChangeStatusRequest struct {
+	Status string     `json:"status"`
+	Reason string     `json:"reason"`
+	Until  *time.Time `json:"until"`
+}
+
+type UserStatusResponse struct {
+	UserID  int                        `json:"user_id"`
+	Status  string                     `json:"status"`
+	Reason  string                     `json:"reason,omitempty"`
+	Until   *time.Time                 `json:"until,omitempty"`
+	History []UserStatusChangeResponse `json:"history"`
+}
+
+type UserStatusChangeResponse struct {
+	ID         int        `json:"id"`
+	FromStatus string     `json:"from_status"`
+	ToStatus   string     `json:"to_status"`
+	Reason     string     `json:"reason"`
+	Until      *time.Time `json:"until,omitempty"`
+	AdminID    string     `json:"admin_id"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+type UserTokenResponse struct {
+	UserID int    `json:"user_id"`
+	Token  string `json:"token"`
+}
+
+type FreezePointsRequest struct {
+	Reason string `json:"reason"`
+}
+
+type PointFreezeResponse struct {
+	UserID       int        `json:"user_id"`
+	Balance      int        `json:"balance"`
+	Frozen       bool       `json:"frozen"`
+	FrozenAt     *time.Time `json:"frozen_at,omitempty"`
+	FrozenReason string     `json:"frozen_reason,omitempty"`
+}
+
+// HandleUsers routes /api/admin/users/{id}/{action}:
+//
+//	PUT  /api/admin/users/{id}/verification
+//	GET  /api/admin/users/{id}/status
+//	PUT  /api/admin/users/{id}/status
+//	POST /api/admin/users/{id}/freeze
+//	POST /api/admin/users/{id}/unfreeze
+//	POST /api/admin/users/{id}/token
+func (h *AdminUserHandler) HandleUsers(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/users"), "/"), "/")
+	if len(parts) != 2 {
+		respondError(w, http.StatusNotFound, "Not found")
+		return
+	}
+
+	userID, err := strconv.Atoi(parts[0])
+	if err != nil || userID <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	switch parts[1] {
+	case "verification":
+		h.UpdateVerification(w, r, userID)
+	case "status":
+		h.HandleStatus(w, r, userID)
+	case "freeze":
+		h.FreezePoints(w, r, userID)
+	case "unfreeze":
+		h.UnfreezePoints(w, r, userID)
+	case "token":
+		h.IssueToken(w, r, userID)
+	default:
+		respondError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func (h *AdminUserHandler) UpdateVerification(w http.ResponseWriter, r *http.Request, userID int) {
+	if r.Method != http.MethodPut {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req UpdateVerificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.Region != nil {
+		region := strings.ToUpper(strings.TrimSpace(*req.Region))
+		if region != "" && !model.IsRegionCode(region) {
+			respondError(w, http.StatusBadRequest, "region must be an ISO 3166-1 alpha-2 country code")
+			return
+		}
+	}
+
+	var birthDate *time.Time
+	if req.BirthDate != nil {
+		parsed, err := time.Parse("2006-01-02", *req.BirthDate)
+		if err != nil || parsed.After(time.Now()) {
+			respondError(w, http.StatusBadRequest, "Invalid birth_date, expected a past YYYY-MM-DD date")
+			return
+		}
+		birthDate = &parsed
+	}
+
+	updated, err := h.userUsecase.UpdateVerification(r.Context(), userID, req.Region, birthDate)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, toUserResponse(updated))
+}
+
+// statusHistoryLimit bounds the status changes returned with the status
+const statusHistoryLimit = 50
+
+func (h *AdminUserHandler) HandleStatus(w http.ResponseWriter, r *http.Request, userID int) {
+	var target *model.User
+	var err error
+	switch r.Method {
+	case http.MethodGet:
+		target, err = h.userUsecase.GetUser(r.Context(), userID)
+	case http.MethodPut:
+		var req ChangeStatusRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		target, err = h.userUsecase.ChangeStatus(r.Context(), userID, model.UserStatus(req.Status), req.Reason, req.Until, adminFromContext(r.Context()))
+	default:
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidStatusChange) {
+			respondError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	changes, err := h.userUsecase.GetStatusHistory(r.Context(), userID, statusHistoryLimit)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := UserStatusResponse{
+		UserID:  target.ID,
+		Status:  string(target.EffectiveStatus(time.Now())),
+		History: make([]UserStatusChangeResponse, 0, len(changes)),
+	}
+	if response.Status != string(model.UserStatusActive) {
+		response.Reason = target.StatusReason
+		response.Until = target.StatusUntil
+	}
+	for _, change := range changes {
+		response.History = append(response.History, UserStatusChangeResponse{
+			ID:         change.ID,
+			FromStatus: string(change.FromStatus),
+			ToStatus:   string(change.ToStatus),
+			Reason:     change.Reason,
+			Until:      change.Until,
+			AdminID:    change.AdminID,
+			CreatedAt:  change.CreatedAt,
+		})
+	}
+
+	respondSuccess(w, response)
+}
+
+func (h *AdminUserHandler) FreezePoints(w http.ResponseWriter, r *http.Request, userID int) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req FreezePointsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if strings.TrimSpace(req.Reason) == "" || len(req.Reason) > 500 {
+		respondError(w, http.StatusBadRequest, "reason is required and must be at most 500 characters long")
+		return
+	}
+
+	userPoint, err := h.abuseUsecase.FreezePoints(r.Context(), userID, req.Reason)
+	if err != nil {
+		if errors.Is(err, model.ErrAlreadyFrozen) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, toPointFreezeResponse(userPoint))
+}
+
+func (h *AdminUserHandler) UnfreezePoints(w http.ResponseWriter, r *http.Request, userID int) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userPoint, err := h.abuseUsecase.UnfreezePoints(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, toPointFreezeResponse(userPoint))
+}
+
+// IssueToken replaces the user's secret token and returns the new one
+func (h *AdminUserHandler) IssueToken(w http.ResponseWriter, r *http.Request, userID int) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	token, err := h.userUsecase.IssueToken(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, model.ErrUserNotFound) {
+			respondError(w, http.StatusNotFound, err.Error())
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, UserTokenResponse{UserID: userID, Token: token})
+}
+
+func toPointFreezeResponse(userPoint *model.UserPoint) PointFreezeResponse {
+	return PointFreezeResponse{
+		UserID:       userPoint.UserID,
+		Balance:      userPoint.Balance,
+		Frozen:       userPoint.IsFrozen(),
+		FrozenAt:     userPoint.FrozenAt,
+		FrozenReason: userPoint.FrozenReason,
+	}
+}
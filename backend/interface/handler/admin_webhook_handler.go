@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/webhook"
+)
+
+type AdminWebhookHandler struct {
+	webhookUsecase webhook.WebhookUsecase
+}
+
+func NewAdminWebhookHandler(webhookUsecase webhook.WebhookUsecase) *AdminWebhookHandler {
+	return &AdminWebhookHandler{
+		webhookUsecase: webhookUsecase,
+	}
+}
+
+type RegisterWebhookRequest struct {
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+	Filter string   `json:"filter"`
+}
+
+type WebhookResponse struct {
+	ID        int       `json:"id"`
+	URL       string    `json:"url"`
+	Secret    string    `json:"secret,omitempty"`
+	Events    []string  `json:"events"`
+	Filter    string    `json:"filter"`
+	Active    bool      `json:"active"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookDeliveryResponse struct {
+	ID             int64           `json:"id"`
+	SubscriptionID int             `json:"subscription_id"`
+	EventID        int64           `json:"event_id"`
+	EventName      string          `json:"event_name"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	NextAttemptAt  time.Time       `json:"next_attempt_at"`
+	LastStatusCode int             `json:"last_status_code"`
+	LastError      string          `json:"last_error"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+type WebhookTestResponse struct {
+	StatusCode int    `json:"status_code"`
+	Error      string `json:"error,omitempty"`
+}
+
+// HandleWebhooks routes /api/admin/webhooks and its subpaths:
+//
+//	GET  /api/admin/webhooks
+//	POST /api/admin/webhooks
+//	GET  /api/admin/webhooks/dead-letters
+//	POST /api/admin/webhooks/{id}/test
+//	POST /api/admin/webhooks/deliveries/{id}/replay
+func (h *AdminWebhookHandler) HandleWebhooks(w http.ResponseWriter, r *http.Request) {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/admin/webhooks"), "/")
+	parts := strings.Split(rest, "/")
+
+	switch {
+	case rest == "":
+		switch r.Method {
+		case http.MethodGet:
+			h.ListWebhooks(w, r)
+		case http.MethodPost:
+			h.RegisterWebhook(w, r)
+		default:
+			respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		}
+	case rest == "dead-letters":
+		h.ListDeadLetters(w, r)
+	case len(parts) == 2 && parts[1] == "test":
+		h.TestWebhook(w, r, parts[0])
+	case len(parts) == 3 && parts[0] == "deliveries" && parts[2] == "replay":
+		h.ReplayDelivery(w, r, parts[1])
+	default:
+		respondError(w, http.StatusNotFound, "Not found")
+	}
+}
+
+func (h *AdminWebhookHandler) RegisterWebhook(w http.ResponseWriter, r *http.Request) {
+	var req RegisterWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.URL == "" || len(req.Events) == 0 {
+		respondError(w, http.StatusBadRequest, "URL and events are required")
+		return
+	}
+
+	subscription, err := h.webhookUsecase.Register(r.Context(), webhook.RegisterWebhookInput{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventNames: req.Events,
+		Filter:     req.Filter,
+		AdminID:    adminFromContext(r.Context()),
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	// シークレットは登録時のみ返す
+	response := newWebhookResponse(subscription)
+	response.Secret = subscription.Secret
+	respondSuccess(w, response)
+}
+
+func (h *AdminWebhookHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	subscriptions, err := h.webhookUsecase.ListSubscriptions(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := make([]WebhookResponse, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		response = append(response, newWebhookResponse(subscription))
+	}
+
+	respondSuccess(w, response)
+}
+
+func (h *AdminWebhookHandler) TestWebhook(w http.ResponseWriter, r *http.Request, idStr string) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	subscriptionID, err := strconv.Atoi(idStr)
+	if err != nil || subscriptionID <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid webhook ID")
+		return
+	}
+
+	result, err := h.webhookUsecase.SendTest(r.Context(), subscriptionID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, WebhookTestResponse{
+		StatusCode: result.StatusCode,
+		Error:      result.Error,
+	})
+}
+
+func (h *AdminWebhookHandler) ListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	subscriptionID := 0
+	if idStr := r.URL.Query().Get("subscription_id"); idStr != "" {
+		parsedID, err := strconv.Atoi(idStr)
+		if err != nil || parsedID <= 0 {
+			respondError(w, http.StatusBadRequest, "Invalid webhook ID")
+			return
+		}
+		subscriptionID = parsedID
+	}
+
+	limitStr := r.URL.Query().Get("limit")
+	limit := 50 // デフォルト値
+	if limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	deliveries, err := h.webhookUsecase.ListDeadLetters(r.Context(), subscriptionID, limit)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := make([]WebhookDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		response = append(response, newWebhookDeliveryResponse(delivery))
+	}
+
+	respondSuccess(w, response)
+}
+
+func (h *AdminWebhookHandler) ReplayDelivery(w http.ResponseWriter, r *http.Request, idStr string) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	deliveryID, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || deliveryID <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid delivery ID")
+		return
+	}
+
+	delivery, err := h.webhookUsecase.Replay(r.Context(), deliveryID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, newWebhookDeliveryResponse(delivery))
+}
+
+func newWebhookResponse(subscription *model.WebhookSubscription) WebhookResponse {
+	return WebhookResponse{
+		ID:        subscription.ID,
+		URL:       subscription.URL,
+		Events:    subscription.EventNames,
+		Filter:    subscription.Filter.Raw,
+		Active:    subscription.Active,
+		CreatedBy: subscription.CreatedBy,
+		CreatedAt: subscription.CreatedAt,
+	}
+}
+
+func newWebhookDeliveryResponse(delivery *model.WebhookDelivery) WebhookDeliveryResponse {
+	return WebhookDeliveryResponse{
+		ID:             delivery.ID,
+		SubscriptionID: delivery.SubscriptionID,
+		EventID:        delivery.EventID,
+		EventName:      delivery.EventName,
+		Payload:        delivery.Payload,
+		Status:         string(delivery.Status),
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastStatusCode: delivery.LastStatusCode,
+		LastError:      delivery.LastError,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
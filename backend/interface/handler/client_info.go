@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/audit"
+)
+
+type clientInfoKey struct{}
+
+type clientInfo struct {
+	RequestID string
+	IP        string
+	UserAgent string
+}
+
+// WithClientInfo records the caller's IP and user agent for handlers and
+// assigns the request an ID, returned in the X-Request-ID header. The
+// X-Real-IP and X-Request-ID headers set by the frontend proxy are only
+// trusted when trustProxy is set, since clients can send them themselves.
+func WithClientInfo(trustProxy bool, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		info := clientInfo{
+			RequestID: newRequestID(),
+			IP:        remoteIP(r),
+			UserAgent: r.UserAgent(),
+		}
+		if trustProxy {
+			if realIP := strings.TrimSpace(r.Header.Get("X-Real-IP")); net.ParseIP(realIP) != nil {
+				info.IP = realIP
+			}
+			if requestID := r.Header.Get("X-Request-ID"); isRequestID(requestID) {
+				info.RequestID = requestID
+			}
+		}
+		w.Header().Set("X-Request-ID", info.RequestID)
+
+		ctx := context.WithValue(r.Context(), clientInfoKey{}, info)
+		ctx = audit.WithRequest(ctx, info.RequestID, info.IP)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return ""
+	}
+	return hex.EncodeToString(b)
+}
+
+// isRequestID accepts IDs of up to 64 letters, digits, '-' and '_'
+func isRequestID(id string) bool {
+	if id == "" || len(id) > 64 {
+		return false
+	}
+	for _, c := range id {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// clientFromContext returns the client set by WithClientInfo
+func clientFromContext(ctx context.Context) clientInfo {
+	info, _ := ctx.Value(clientInfoKey{}).(clientInfo)
+	return info
+}
+
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
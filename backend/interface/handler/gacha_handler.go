@@ -2,20 +2,25 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
 	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/gacha"
 )
 
 type GachaHandler struct {
 	gachaUsecase gacha.GachaUsecase
+	// tiers name and colour rarities in responses
+	tiers *model.RarityRegistry
 }
 
-func NewGachaHandler(gachaUsecase gacha.GachaUsecase) *GachaHandler {
+func NewGachaHandler(gachaUsecase gacha.GachaUsecase, tiers *model.RarityRegistry) *GachaHandler {
 	return &GachaHandler{
 		gachaUsecase: gachaUsecase,
+		tiers:        tiers,
 	}
 }
 
@@ -27,10 +32,28 @@ type GachaResultResponse struct {
 	ID           int       `json:"id"`
 	ItemName     string    `json:"item_name"`
 	Rarity       string    `json:"rarity"`
+	RarityColor  string    `json:"rarity_color"`
 	PointsEarned int       `json:"points_earned"`
 	CreatedAt    time.Time `json:"created_at"`
 }
 
+type GachaPolicyResponse struct {
+	Region                string `json:"region"`
+	Allowed               bool   `json:"allowed"`
+	Reason                string `json:"reason,omitempty"`
+	RequireRateDisclosure bool   `json:"require_rate_disclosure"`
+	MinAge                int    `json:"min_age"`
+}
+
+type RarityTierResponse struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Rank      int    `json:"rank"`
+	Color     string `json:"color"`
+	MinPoints int    `json:"min_points"`
+	MaxPoints int    `json:"max_points"`
+}
+
 func (h *GachaHandler) ExecuteGacha(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
@@ -50,14 +73,25 @@ func (h *GachaHandler) ExecuteGacha(w http.ResponseWriter, r *http.Request) {
 
 	result, err := h.gachaUsecase.ExecuteGacha(r.Context(), req.UserID)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, err.Error())
+		switch {
+		case errors.Is(err, model.ErrSpendLimitReached), errors.Is(err, model.ErrGachaRestricted), errors.Is(err, model.ErrPointsFrozen),
+			errors.Is(err, model.ErrAccountSuspended):
+			respondError(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, model.ErrSpinThrottled):
+			respondError(w, http.StatusTooManyRequests, err.Error())
+		case errors.Is(err, gacha.ErrRatesNotDisclosed):
+			respondError(w, http.StatusServiceUnavailable, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
 		return
 	}
 
 	response := GachaResultResponse{
 		ID:           result.ID,
 		ItemName:     result.ItemName,
-		Rarity:       result.Rarity.String(),
+		Rarity:       h.tiers.Name(result.Rarity),
+		RarityColor:  h.tiers.Color(result.Rarity),
 		PointsEarned: result.PointsEarned,
 		CreatedAt:    result.CreatedAt,
 	}
@@ -102,11 +136,323 @@ func (h *GachaHandler) GetGachaHistory(w http.ResponseWriter, r *http.Request) {
 		response = append(response, GachaResultResponse{
 			ID:           result.ID,
 			ItemName:     result.ItemName,
-			Rarity:       result.Rarity.String(),
+			Rarity:       h.tiers.Name(result.Rarity),
+			RarityColor:  h.tiers.Color(result.Rarity),
 			PointsEarned: result.PointsEarned,
 			CreatedAt:    result.CreatedAt,
 		})
 	}
 
 	respondSuccess(w, response)
-}
\ No newline at end of file
+}
+
+// GetRarities lists the rarity tiers, lowest rank first
+func (h *GachaHandler) GetRarities(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	tiers := h.tiers.Tiers()
+	response := make([]RarityTierResponse, 0, len(tiers))
+	for _, tier := range tiers {
+		response = append(response, RarityTierResponse{
+			ID:        int(tier.Rarity),
+			Name:      tier.Name,
+			Rank:      tier.Rank,
+			Color:     tier.Color,
+			MinPoints: tier.MinPoints,
+			MaxPoints: tier.MaxPoints,
+		})
+	}
+
+	respondSuccess(w, response)
+}
+
+type RateStatResponse struct {
+	Configured float64 `json:"configured"`
+	Observed   float64 `json:"observed"`
+	Count      int     `json:"count"`
+	SampleSize int     `json:"sample_size"`
+	Lower      float64 `json:"ci_lower"`
+	Upper      float64 `json:"ci_upper"`
+	Consistent bool    `json:"consistent"`
+}
+
+type ItemRateStatResponse struct {
+	ItemID   int    `json:"item_id"`
+	ItemName string `json:"item_name"`
+	Rarity   string `json:"rarity"`
+	RateStatResponse
+}
+
+type RarityRateStatResponse struct {
+	Rarity string `json:"rarity"`
+	RateStatResponse
+}
+
+type GachaStatsResponse struct {
+	SampleSize int                      `json:"sample_size"`
+	Items      []ItemRateStatResponse   `json:"items"`
+	Rarities   []RarityRateStatResponse `json:"rarities"`
+}
+
+type GachaStatsReportResponse struct {
+	ConfidenceLevel float64             `json:"confidence_level"`
+	Global          GachaStatsResponse  `json:"global"`
+	User            *GachaStatsResponse `json:"user"`
+}
+
+func (h *GachaHandler) GetGachaStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	userID := 0
+	if userIDStr := r.URL.Query().Get("user_id"); userIDStr != "" {
+		parsedUserID, err := strconv.Atoi(userIDStr)
+		if err != nil || parsedUserID <= 0 {
+			respondError(w, http.StatusBadRequest, "Invalid user ID")
+			return
+		}
+		userID = parsedUserID
+	}
+
+	global, user, err := h.gachaUsecase.GetStats(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := GachaStatsReportResponse{
+		ConfidenceLevel: model.StatsConfidenceLevel,
+		Global:          h.newGachaStatsResponse(global),
+	}
+	if user != nil {
+		userResponse := h.newGachaStatsResponse(user)
+		response.User = &userResponse
+	}
+
+	respondSuccess(w, response)
+}
+
+func (h *GachaHandler) newGachaStatsResponse(stats *model.GachaStats) GachaStatsResponse {
+	response := GachaStatsResponse{
+		SampleSize: stats.SampleSize,
+		Items:      make([]ItemRateStatResponse, 0, len(stats.Items)),
+		Rarities:   make([]RarityRateStatResponse, 0, len(stats.Rarities)),
+	}
+	for _, stat := range stats.Items {
+		response.Items = append(response.Items, ItemRateStatResponse{
+			ItemID:           stat.Item.ID,
+			ItemName:         stat.Item.Name,
+			Rarity:           h.tiers.Name(stat.Item.Rarity),
+			RateStatResponse: newRateStatResponse(stat.RateStat),
+		})
+	}
+	for _, stat := range stats.Rarities {
+		response.Rarities = append(response.Rarities, RarityRateStatResponse{
+			Rarity:           h.tiers.Name(stat.Rarity),
+			RateStatResponse: newRateStatResponse(stat.RateStat),
+		})
+	}
+	return response
+}
+
+func newRateStatResponse(stat model.RateStat) RateStatResponse {
+	return RateStatResponse{
+		Configured: stat.Configured,
+		Observed:   stat.Observed,
+		Count:      stat.Count,
+		SampleSize: stat.SampleSize,
+		Lower:      stat.Lower,
+		Upper:      stat.Upper,
+		Consistent: stat.Consistent(),
+	}
+}
+
+type ItemRateResponse struct {
+	ItemID      int     `json:"item_id"`
+	ItemName    string  `json:"item_name"`
+	Rarity      string  `json:"rarity"`
+	Points      int     `json:"points"`
+	Featured    bool    `json:"featured"`
+	Weight      int     `json:"weight"`
+	Probability float64 `json:"probability"`
+	Percent     string  `json:"percent"`
+}
+
+type RarityRateResponse struct {
+	Rarity        string  `json:"rarity"`
+	Weight        int     `json:"weight"`
+	Probability   float64 `json:"probability"`
+	Percent       string  `json:"percent"`
+	FeaturedShare int     `json:"featured_share"` // of the rarity's draws, out of weight_scale
+}
+
+type PityRuleResponse struct {
+	Rarity     string `json:"rarity"`
+	AfterSpins int    `json:"after_spins"`
+}
+
+type RateTableResponse struct {
+	Version               int                  `json:"version"`
+	EffectiveFrom         time.Time            `json:"effective_from"`
+	Checksum              string               `json:"checksum"`
+	WeightScale           int                  `json:"weight_scale"`
+	Items                 []ItemRateResponse   `json:"items"`
+	Rarities              []RarityRateResponse `json:"rarities"`
+	ExpectedPointsPerSpin float64              `json:"expected_points_per_spin"`
+	PityRules             []PityRuleResponse   `json:"pity_rules"`
+}
+
+type RateTableVersionResponse struct {
+	Version        int        `json:"version"`
+	EffectiveFrom  time.Time  `json:"effective_from"`
+	EffectiveUntil *time.Time `json:"effective_until"`
+	Checksum       string     `json:"checksum"`
+}
+
+// GetGachaRates discloses the drop rates in effect now, at ?at=RFC3339, or of ?version=N
+func (h *GachaHandler) GetGachaRates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	version := 0
+	if versionStr := r.URL.Query().Get("version"); versionStr != "" {
+		parsedVersion, err := strconv.Atoi(versionStr)
+		if err != nil || parsedVersion <= 0 {
+			respondError(w, http.StatusBadRequest, "Invalid version")
+			return
+		}
+		version = parsedVersion
+	}
+
+	var at time.Time
+	if atStr := r.URL.Query().Get("at"); atStr != "" {
+		parsedAt, err := time.Parse(time.RFC3339, atStr)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid time; use RFC 3339")
+			return
+		}
+		at = parsedAt
+	}
+
+	table, err := h.gachaUsecase.GetRates(r.Context(), version, at)
+	if err != nil {
+		if errors.Is(err, gacha.ErrRateTableNotFound) {
+			respondError(w, http.StatusNotFound, "Rate table not found")
+			return
+		}
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, h.newRateTableResponse(table))
+}
+
+// GetGachaRateHistory lists every published rate table version, newest first
+func (h *GachaHandler) GetGachaRateHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	tables, err := h.gachaUsecase.ListRateTables(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := make([]RateTableVersionResponse, 0, len(tables))
+	for i, table := range tables {
+		version := RateTableVersionResponse{
+			Version:       table.Version,
+			EffectiveFrom: table.EffectiveFrom,
+			Checksum:      table.Checksum,
+		}
+		// 新しい順のため、一つ前の要素が次の版になる
+		if i > 0 {
+			version.EffectiveUntil = &tables[i-1].EffectiveFrom
+		}
+		response = append(response, version)
+	}
+
+	respondSuccess(w, response)
+}
+
+func (h *GachaHandler) newRateTableResponse(table *model.RateTable) RateTableResponse {
+	response := RateTableResponse{
+		Version:               table.Version,
+		EffectiveFrom:         table.EffectiveFrom,
+		Checksum:              table.Checksum,
+		WeightScale:           model.WeightScale,
+		Items:                 make([]ItemRateResponse, 0, len(table.Items)),
+		Rarities:              make([]RarityRateResponse, 0),
+		ExpectedPointsPerSpin: table.ExpectedPointsPerSpin(),
+		PityRules:             make([]PityRuleResponse, 0, len(table.PityRules)),
+	}
+	for _, item := range table.Items {
+		response.Items = append(response.Items, ItemRateResponse{
+			ItemID:      item.ID,
+			ItemName:    item.Name,
+			Rarity:      h.tiers.Name(item.Rarity),
+			Points:      item.Points,
+			Featured:    table.IsFeatured(item),
+			Weight:      item.Weight,
+			Probability: item.Probability(),
+			Percent:     formatPercent(item.Weight),
+		})
+	}
+	for _, rarity := range table.RarityWeights(h.tiers) {
+		response.Rarities = append(response.Rarities, RarityRateResponse{
+			Rarity:        h.tiers.Name(rarity.Rarity),
+			Weight:        rarity.Weight,
+			Probability:   rarity.Probability(),
+			Percent:       formatPercent(rarity.Weight),
+			FeaturedShare: rarity.FeaturedShare,
+		})
+	}
+	for _, rule := range table.PityRules {
+		response.PityRules = append(response.PityRules, PityRuleResponse{
+			Rarity:     h.tiers.Name(rule.Rarity),
+			AfterSpins: rule.AfterSpins,
+		})
+	}
+	return response
+}
+
+// formatPercent renders a weight in parts per million as an exact percentage, e.g. 80000 as "8%"
+func formatPercent(weight int) string {
+	return strconv.FormatFloat(float64(weight)/(model.WeightScale/100), 'f', -1, 64) + "%"
+}
+
+
+// GetUserPolicy serves GET /api/users/{id}/gacha-policy
+func (h *GachaHandler) GetUserPolicy(w http.ResponseWriter, r *http.Request, userID int) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	decision, err := h.gachaUsecase.GetPolicy(r.Context(), userID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := GachaPolicyResponse{
+		Region:                decision.Policy.Region,
+		Allowed:               decision.Restriction == nil,
+		RequireRateDisclosure: decision.Policy.RequireRateDisclosure,
+		MinAge:                decision.Policy.MinAge,
+	}
+	if decision.Restriction != nil {
+		response.Reason = decision.Restriction.Error()
+	}
+
+	respondSuccess(w, response)
+}
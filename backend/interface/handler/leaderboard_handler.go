@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/leaderboard"
+)
+
+type LeaderboardHandler struct {
+	leaderboardUsecase leaderboard.LeaderboardUsecase
+}
+
+func NewLeaderboardHandler(leaderboardUsecase leaderboard.LeaderboardUsecase) *LeaderboardHandler {
+	return &LeaderboardHandler{
+		leaderboardUsecase: leaderboardUsecase,
+	}
+}
+
+type LeaderboardEntryResponse struct {
+	Rank     int    `json:"rank"`
+	UserID   int    `json:"user_id"`
+	UserName string `json:"user_name"`
+	Score    int    `json:"score"`
+}
+
+type LeaderboardResponse struct {
+	Metric      string                     `json:"metric"`
+	Period      string                     `json:"period"`
+	PeriodStart string                     `json:"period_start"`
+	Entries     []LeaderboardEntryResponse `json:"entries"`
+	Me          *LeaderboardEntryResponse  `json:"me"`
+}
+
+func (h *LeaderboardHandler) GetLeaderboard(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	query := r.URL.Query()
+
+	metric := model.LeaderboardMetric(query.Get("metric"))
+	if metric == "" {
+		metric = model.LeaderboardMetricBalance
+	}
+
+	period := model.LeaderboardPeriod(query.Get("period"))
+	if period == "" {
+		period = model.LeaderboardPeriodAllTime
+	}
+
+	if err := model.ValidateLeaderboard(metric, period); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	limit := 10 // デフォルト値
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if parsedLimit, err := strconv.Atoi(limitStr); err == nil && parsedLimit > 0 {
+			limit = parsedLimit
+		}
+	}
+
+	callerID := 0
+	if userIDStr := query.Get("user_id"); userIDStr != "" {
+		userID, err := strconv.Atoi(userIDStr)
+		if err != nil || userID <= 0 {
+			respondError(w, http.StatusBadRequest, "Invalid user ID")
+			return
+		}
+		callerID = userID
+	}
+
+	board, err := h.leaderboardUsecase.GetLeaderboard(r.Context(), metric, period, limit, callerID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := LeaderboardResponse{
+		Metric:      string(board.Metric),
+		Period:      string(board.Period),
+		PeriodStart: board.PeriodStart.Format("2006-01-02"),
+		Entries:     make([]LeaderboardEntryResponse, 0),
+	}
+	for _, entry := range board.Entries {
+		response.Entries = append(response.Entries, newLeaderboardEntryResponse(entry))
+	}
+	if board.Caller != nil {
+		me := newLeaderboardEntryResponse(*board.Caller)
+		response.Me = &me
+	}
+
+	respondSuccess(w, response)
+}
+
+func (h *LeaderboardHandler) RebuildLeaderboards(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	if err := h.leaderboardUsecase.Rebuild(r.Context()); err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	respondSuccess(w, map[string]bool{"rebuilt": true})
+}
+
+func newLeaderboardEntryResponse(entry model.LeaderboardEntry) LeaderboardEntryResponse {
+	return LeaderboardEntryResponse{
+		Rank:     entry.Rank,
+		UserID:   entry.UserID,
+		UserName: entry.UserName,
+		Score:    entry.Score,
+	}
+}
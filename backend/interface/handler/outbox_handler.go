@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/outbox"
+)
+
+type OutboxHandler struct {
+	outboxUsecase outbox.OutboxUsecase
+}
+
+func NewOutboxHandler(outboxUsecase outbox.OutboxUsecase) *OutboxHandler {
+	return &OutboxHandler{
+		outboxUsecase: outboxUsecase,
+	}
+}
+
+type OutboxFailureResponse struct {
+	ID        int64     `json:"id"`
+	EventName string    `json:"event_name"`
+	Attempts  int       `json:"attempts"`
+	LastError string    `json:"last_error"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type OutboxStatusResponse struct {
+	Pending          int                     `json:"pending"`
+	Delivered        int                     `json:"delivered"`
+	Failed           int                     `json:"failed"`
+	OldestPendingAge *float64                `json:"oldest_pending_age_seconds"`
+	RecentFailures   []OutboxFailureResponse `json:"recent_failures"`
+}
+
+func (h *OutboxHandler) GetStatus(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	stats, err := h.outboxUsecase.GetStats(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	response := OutboxStatusResponse{
+		Pending:        stats.Pending,
+		Delivered:      stats.Delivered,
+		Failed:         stats.Failed,
+		RecentFailures: make([]OutboxFailureResponse, 0, len(stats.RecentFailures)),
+	}
+	if stats.OldestPending != nil {
+		age := time.Since(*stats.OldestPending).Seconds()
+		response.OldestPendingAge = &age
+	}
+	for _, failure := range stats.RecentFailures {
+		response.RecentFailures = append(response.RecentFailures, OutboxFailureResponse{
+			ID:        failure.ID,
+			EventName: failure.Name,
+			Attempts:  failure.Attempts,
+			LastError: failure.LastError,
+			CreatedAt: failure.CreatedAt,
+		})
+	}
+
+	respondSuccess(w, response)
+}
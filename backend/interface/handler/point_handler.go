@@ -1,10 +1,13 @@
 package handler
 
 import (
+	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
 	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/point"
 )
 
@@ -19,8 +22,14 @@ func NewPointHandler(pointUsecase point.PointUsecase) *PointHandler {
 }
 
 type BalanceResponse struct {
-	UserID  int `json:"user_id"`
-	Balance int `json:"balance"`
+	UserID              int                  `json:"user_id"`
+	Balance             int                  `json:"balance"`
+	UpcomingExpirations []ExpirationResponse `json:"upcoming_expirations"`
+}
+
+type ExpirationResponse struct {
+	Amount    int       `json:"amount"`
+	ExpiresAt time.Time `json:"expires_at"`
 }
 
 type TransactionResponse struct {
@@ -28,6 +37,23 @@ type TransactionResponse struct {
 	Amount      int       `json:"amount"`
 	Type        string    `json:"type"`
 	Description string    `json:"description"`
+	TransferID  string    `json:"transfer_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type TransferRequest struct {
+	FromUserID  int    `json:"from_user_id"`
+	ToUserID    int    `json:"to_user_id"`
+	Amount      int    `json:"amount"`
+	Description string `json:"description"`
+}
+
+type TransferResponse struct {
+	TransferID  string    `json:"transfer_id"`
+	FromUserID  int       `json:"from_user_id"`
+	ToUserID    int       `json:"to_user_id"`
+	Amount      int       `json:"amount"`
+	Description string    `json:"description"`
 	CreatedAt   time.Time `json:"created_at"`
 }
 
@@ -49,15 +75,35 @@ func (h *PointHandler) GetBalance(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	withinDays := 30 // デフォルト値
+	if withinStr := r.URL.Query().Get("expiring_within_days"); withinStr != "" {
+		if parsedWithin, err := strconv.Atoi(withinStr); err == nil && parsedWithin > 0 {
+			withinDays = parsedWithin
+		}
+	}
+
 	balance, err := h.pointUsecase.GetBalance(r.Context(), userID)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
+	expirations, err := h.pointUsecase.GetUpcomingExpirations(r.Context(), userID, time.Duration(withinDays)*24*time.Hour)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
 	response := BalanceResponse{
-		UserID:  userID,
-		Balance: balance,
+		UserID:              userID,
+		Balance:             balance,
+		UpcomingExpirations: make([]ExpirationResponse, 0),
+	}
+	for _, expiration := range expirations {
+		response.UpcomingExpirations = append(response.UpcomingExpirations, ExpirationResponse{
+			Amount:    expiration.Amount,
+			ExpiresAt: expiration.ExpiresAt,
+		})
 	}
 
 	respondSuccess(w, response)
@@ -102,9 +148,62 @@ func (h *PointHandler) GetTransactionHistory(w http.ResponseWriter, r *http.Requ
 			Amount:      tx.Amount,
 			Type:        string(tx.Type),
 			Description: tx.Description,
+			TransferID:  tx.TransferID,
 			CreatedAt:   tx.CreatedAt,
 		})
 	}
 
+	respondSuccess(w, response)
+}
+
+func (h *PointHandler) TransferPoints(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var req TransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	if req.FromUserID <= 0 || req.ToUserID <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	if req.Amount <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid amount")
+		return
+	}
+
+	transfer, err := h.pointUsecase.TransferPoints(r.Context(), req.FromUserID, bearerToken(r), req.ToUserID, req.Amount, req.Description)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidTransfer), errors.Is(err, model.ErrInsufficientPoints), errors.Is(err, model.ErrMaxBalanceExceeded):
+			respondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, model.ErrInvalidUserToken):
+			respondError(w, http.StatusUnauthorized, err.Error())
+		case errors.Is(err, model.ErrSpendLimitReached), errors.Is(err, model.ErrDailyTransferLimit),
+			errors.Is(err, model.ErrPointsFrozen), errors.Is(err, model.ErrAccountSuspended):
+			respondError(w, http.StatusForbidden, err.Error())
+		case errors.Is(err, model.ErrUserNotFound):
+			respondError(w, http.StatusNotFound, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	response := TransferResponse{
+		TransferID:  transfer.ID,
+		FromUserID:  transfer.FromUserID,
+		ToUserID:    transfer.ToUserID,
+		Amount:      transfer.Amount,
+		Description: transfer.Description,
+		CreatedAt:   transfer.CreatedAt,
+	}
+
 	respondSuccess(w, response)
 }
\ No newline at end of file
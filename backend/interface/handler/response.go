@@ -24,6 +24,7 @@ func respondJSON(w http.ResponseWriter, status int, payload interface{}) {
 }
 
 func respondError(w http.ResponseWriter, status int, message string) {
+	recordError(w, message)
 	respondJSON(w, status, Response{
 		Success: false,
 		Error:   message,
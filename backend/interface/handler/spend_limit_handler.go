@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/spendlimit"
+)
+
+type SpendLimitHandler struct {
+	spendLimitUsecase spendlimit.SpendLimitUsecase
+}
+
+func NewSpendLimitHandler(spendLimitUsecase spendlimit.SpendLimitUsecase) *SpendLimitHandler {
+	return &SpendLimitHandler{
+		spendLimitUsecase: spendLimitUsecase,
+	}
+}
+
+// UpdateSpendLimitsRequest changes only the fields that are present. A limit of 0 removes it.
+type UpdateSpendLimitsRequest struct {
+	DailySpinLimit    *int       `json:"daily_spin_limit"`
+	WeeklySpendLimit  *int       `json:"weekly_spend_limit"`
+	CoolOffUntil      *time.Time `json:"cool_off_until"`
+	SelfExcludedUntil *time.Time `json:"self_excluded_until"`
+}
+
+type PendingLimitResponse struct {
+	Value       int       `json:"value"`
+	EffectiveAt time.Time `json:"effective_at"`
+}
+
+type SpendLimitsResponse struct {
+	UserID                  int                   `json:"user_id"`
+	DailySpinLimit          int                   `json:"daily_spin_limit"`
+	PendingDailySpinLimit   *PendingLimitResponse `json:"pending_daily_spin_limit,omitempty"`
+	WeeklySpendLimit        int                   `json:"weekly_spend_limit"`
+	PendingWeeklySpendLimit *PendingLimitResponse `json:"pending_weekly_spend_limit,omitempty"`
+	CoolOffUntil            *time.Time            `json:"cool_off_until,omitempty"`
+	SelfExcludedUntil       *time.Time            `json:"self_excluded_until,omitempty"`
+	SpinsToday              int                   `json:"spins_today"`
+	SpentThisWeek           int                   `json:"spent_this_week"`
+}
+
+// HandleLimits serves GET and PUT /api/users/{id}/limits
+func (h *SpendLimitHandler) HandleLimits(w http.ResponseWriter, r *http.Request, userID int) {
+	var status *spendlimit.Status
+	var err error
+	switch r.Method {
+	case http.MethodGet:
+		status, err = h.spendLimitUsecase.GetLimits(r.Context(), userID)
+	case http.MethodPut:
+		var req UpdateSpendLimitsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body")
+			return
+		}
+		status, err = h.spendLimitUsecase.UpdateLimits(r.Context(), userID, bearerToken(r), model.SpendLimitChange{
+			DailySpins:        req.DailySpinLimit,
+			WeeklySpend:       req.WeeklySpendLimit,
+			CoolOffUntil:      req.CoolOffUntil,
+			SelfExcludedUntil: req.SelfExcludedUntil,
+		})
+	default:
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidSpendLimitChange):
+			respondError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, model.ErrInvalidUserToken):
+			respondError(w, http.StatusUnauthorized, err.Error())
+		case errors.Is(err, model.ErrUserNotFound):
+			respondError(w, http.StatusNotFound, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	limits := status.Limits
+	response := SpendLimitsResponse{
+		UserID:                  limits.UserID,
+		DailySpinLimit:          limits.DailySpins,
+		PendingDailySpinLimit:   toPendingLimitResponse(limits.PendingDailySpins),
+		WeeklySpendLimit:        limits.WeeklySpend,
+		PendingWeeklySpendLimit: toPendingLimitResponse(limits.PendingWeekly),
+		CoolOffUntil:            limits.CoolOffUntil,
+		SelfExcludedUntil:       limits.SelfExcludedUntil,
+		SpinsToday:              status.SpinsToday,
+		SpentThisWeek:           status.SpentThisWeek,
+	}
+
+	respondSuccess(w, response)
+}
+
+func toPendingLimitResponse(pending *model.PendingLimit) *PendingLimitResponse {
+	if pending == nil {
+		return nil
+	}
+	return &PendingLimitResponse{
+		Value:       pending.Value,
+		EffectiveAt: pending.EffectiveAt,
+	}
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/stream"
+)
+
+// heartbeatInterval keeps idle connections open through proxies
+const heartbeatInterval = 15 * time.Second
+
+type StreamHandler struct {
+	broker *stream.Broker
+}
+
+func NewStreamHandler(broker *stream.Broker) *StreamHandler {
+	return &StreamHandler{
+		broker: broker,
+	}
+}
+
+// Stream serves Server-Sent Events: balance_changed and gacha_result for
+// ?user_id=, and rare_pull when ?rare_pulls=true. A reset event tells a
+// resuming client that some messages were lost and it should refetch.
+func (h *StreamHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
+		return
+	}
+
+	var filter stream.Filter
+	if userIDStr := r.URL.Query().Get("user_id"); userIDStr != "" {
+		userID, err := strconv.Atoi(userIDStr)
+		if err != nil || userID <= 0 {
+			respondError(w, http.StatusBadRequest, "Invalid user ID")
+			return
+		}
+		filter.UserID = userID
+	}
+	filter.RarePulls = r.URL.Query().Get("rare_pulls") == "true"
+	if filter.UserID == 0 && !filter.RarePulls {
+		respondError(w, http.StatusBadRequest, "User ID or rare_pulls=true is required")
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		respondError(w, http.StatusInternalServerError, "Streaming is not supported")
+		return
+	}
+
+	// EventSource は再接続時に Last-Event-ID ヘッダーを送る
+	var lastEventID int64
+	if lastEventIDStr := r.Header.Get("Last-Event-ID"); lastEventIDStr != "" {
+		lastEventID, _ = strconv.ParseInt(lastEventIDStr, 10, 64)
+	}
+
+	subscription, err := h.broker.Subscribe(filter, lastEventID)
+	if err != nil {
+		respondError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	defer h.broker.Unsubscribe(subscription)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if subscription.Reset {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, msg := range subscription.Backlog {
+		writeStreamMessage(w, msg)
+	}
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case msg, ok := <-subscription.Messages:
+			if !ok {
+				return
+			}
+			writeStreamMessage(w, msg)
+			flusher.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func writeStreamMessage(w http.ResponseWriter, msg stream.Message) {
+	if msg.ID != 0 {
+		fmt.Fprintf(w, "id: %d\n", msg.ID)
+	}
+	fmt.Fprintf(w, "event: %s\ndata: %s\n\n", msg.Event, msg.Data)
+}
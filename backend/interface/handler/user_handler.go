@@ -2,33 +2,71 @@ package handler
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
-	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/user"
 )
 
 type UserHandler struct {
-	userRepo repository.UserRepository
+	userUsecase  user.UserUsecase
+	subresources map[string]UserSubresourceHandler
 }
 
-func NewUserHandler(userRepo repository.UserRepository) *UserHandler {
+// UserSubresourceHandler serves /api/users/{id}/{subresource}
+type UserSubresourceHandler func(w http.ResponseWriter, r *http.Request, userID int)
+
+func NewUserHandler(userUsecase user.UserUsecase) *UserHandler {
 	return &UserHandler{
-		userRepo: userRepo,
+		userUsecase:  userUsecase,
+		subresources: make(map[string]UserSubresourceHandler),
 	}
 }
 
+// RegisterSubresource routes /api/users/{id}/{name} to fn
+func (h *UserHandler) RegisterSubresource(name string, fn UserSubresourceHandler) {
+	h.subresources[name] = fn
+}
+
 type CreateUserRequest struct {
 	Name string `json:"name"`
 }
 
-type UserResponse struct {
-	ID   int    `json:"id"`
+type RenameUserRequest struct {
 	Name string `json:"name"`
 }
 
+type UserResponse struct {
+	ID             int    `json:"id"`
+	Name           string `json:"name"`
+	Region         string `json:"region"`
+	RegionVerified bool   `json:"region_verified"`
+	AgeVerified    bool   `json:"age_verified"`
+	Status         string `json:"status"`
+}
+
+// CreateUserResponse includes the user's secret token, which is only ever
+// returned here
+type CreateUserResponse struct {
+	UserResponse
+	Token string `json:"token"`
+}
+
+func toUserResponse(user *model.User) UserResponse {
+	return UserResponse{
+		ID:             user.ID,
+		Name:           user.Name,
+		Region:         user.Region,
+		RegionVerified: user.RegionVerifiedAt != nil,
+		AgeVerified:    user.AgeVerifiedAt != nil,
+		Status:         string(user.EffectiveStatus(time.Now())),
+	}
+}
+
 func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
@@ -46,26 +84,45 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusBadRequest, err.Error())
 		return
 	}
+	client := clientFromContext(r.Context())
+	user.RecordSignup(client.IP, client.UserAgent)
 
-	if err := h.userRepo.Create(r.Context(), user); err != nil {
+	token, err := h.userUsecase.CreateUser(r.Context(), user)
+	if err != nil {
 		respondError(w, http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	response := UserResponse{
-		ID:   user.ID,
-		Name: user.Name,
-	}
-
-	respondSuccess(w, response)
+	respondSuccess(w, CreateUserResponse{UserResponse: toUserResponse(user), Token: token})
 }
 
 func (h *UserHandler) HandleUsers(w http.ResponseWriter, r *http.Request) {
+	// /api/users/{id}/{subresource}
+	if rest := strings.TrimPrefix(r.URL.Path, "/api/users/"); rest != r.URL.Path && strings.Contains(rest, "/") {
+		parts := strings.SplitN(rest, "/", 2)
+		fn, ok := h.subresources[parts[1]]
+		if !ok {
+			respondError(w, http.StatusNotFound, "Not found")
+			return
+		}
+
+		userID, err := strconv.Atoi(parts[0])
+		if err != nil || userID <= 0 {
+			respondError(w, http.StatusBadRequest, "Invalid user ID")
+			return
+		}
+
+		fn(w, r, userID)
+		return
+	}
+
 	switch r.Method {
 	case http.MethodPost:
 		h.CreateUser(w, r)
 	case http.MethodGet:
 		h.GetUser(w, r)
+	case http.MethodPut:
+		h.RenameUser(w, r)
 	default:
 		respondError(w, http.StatusMethodNotAllowed, "Method not allowed")
 	}
@@ -94,16 +151,46 @@ func (h *UserHandler) GetUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.userRepo.FindByID(r.Context(), userID)
+	user, err := h.userUsecase.GetUser(r.Context(), userID)
 	if err != nil {
 		respondError(w, http.StatusNotFound, "User not found")
 		return
 	}
 
-	response := UserResponse{
-		ID:   user.ID,
-		Name: user.Name,
+	respondSuccess(w, toUserResponse(user))
+}
+
+// RenameUser handles PUT /api/users/{id}
+func (h *UserHandler) RenameUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/users/"))
+	if err != nil || userID <= 0 {
+		respondError(w, http.StatusBadRequest, "Invalid user ID")
+		return
 	}
 
-	respondSuccess(w, response)
-}
\ No newline at end of file
+	var req RenameUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	// 名前の検証は作成時と同じ規則で行う
+	if _, err := model.NewUser(req.Name); err != nil {
+		respondError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	user, err := h.userUsecase.RenameUser(r.Context(), userID, bearerToken(r), req.Name)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrInvalidUserToken):
+			respondError(w, http.StatusUnauthorized, err.Error())
+		case errors.Is(err, model.ErrUserNotFound):
+			respondError(w, http.StatusNotFound, err.Error())
+		default:
+			respondError(w, http.StatusInternalServerError, err.Error())
+		}
+		return
+	}
+
+	respondSuccess(w, toUserResponse(user))
+}
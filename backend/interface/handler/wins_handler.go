@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/interface/websocket"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/logging"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/wins"
+)
+
+const (
+	winsPingInterval = 30 * time.Second
+	// winsReadTimeout must exceed the ping interval so a pong can arrive
+	winsReadTimeout  = 2 * winsPingInterval
+	winsWriteTimeout = 10 * time.Second
+)
+
+type WinsHandler struct {
+	hub *wins.Hub
+}
+
+func NewWinsHandler(hub *wins.Hub) *WinsHandler {
+	return &WinsHandler{
+		hub: hub,
+	}
+}
+
+// Stream upgrades to a WebSocket and pushes each big win as a JSON text message
+func (h *WinsHandler) Stream(w http.ResponseWriter, r *http.Request) {
+	// 接続数の上限はハンドシェイク前に確認する
+	client, err := h.hub.Register()
+	if err != nil {
+		if errors.Is(err, wins.ErrTooManyConnections) {
+			w.Header().Set("Retry-After", "30")
+		}
+		respondError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	defer h.hub.Unregister(client)
+
+	conn, err := websocket.Upgrade(w, r)
+	if err != nil {
+		return
+	}
+
+	readDone := make(chan struct{})
+	go func() {
+		defer close(readDone)
+		conn.ReadLoop(winsReadTimeout)
+	}()
+
+	ping := time.NewTicker(winsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case <-readDone:
+			conn.Close(websocket.CloseNormal, "")
+			return
+		case data, ok := <-client.Messages:
+			if !ok {
+				conn.Close(websocket.CloseGoingAway, "server shutting down")
+				<-readDone
+				return
+			}
+			if err := conn.WriteText(data, time.Now().Add(winsWriteTimeout)); err != nil {
+				logging.FromContext(r.Context()).Info("Closing wins ticker connection", logging.KeyError, err)
+				conn.Close(websocket.CloseGoingAway, "")
+				<-readDone
+				return
+			}
+		case <-ping.C:
+			if err := conn.WritePing(time.Now().Add(winsWriteTimeout)); err != nil {
+				conn.Close(websocket.CloseGoingAway, "")
+				<-readDone
+				return
+			}
+		}
+	}
+}
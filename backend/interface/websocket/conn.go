@@ -0,0 +1,288 @@
+// Package websocket implements the server side of RFC 6455 with the standard
+// library: the opening handshake, unfragmented text messages from the
+// server, and ping/pong/close handling. Messages sent by clients are read and
+// discarded since the application only broadcasts.
+package websocket
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Opcodes
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xA
+)
+
+// Close status codes
+const (
+	CloseNormal          = 1000
+	CloseGoingAway       = 1001
+	CloseProtocolError   = 1002
+	CloseMessageTooBig   = 1009
+	maxControlPayload    = 125
+	defaultMaxReadLength = 4096
+)
+
+var (
+	ErrClosed          = errors.New("websocket: connection closed")
+	errProtocol        = errors.New("websocket: protocol error")
+	errMessageTooLarge = errors.New("websocket: message too large")
+)
+
+// Conn is a server-side WebSocket connection. Writes are safe for concurrent
+// use; reads must happen on a single goroutine.
+type Conn struct {
+	netConn net.Conn
+	reader  *bufio.Reader
+
+	writeMu sync.Mutex
+	closed  bool
+
+	// MaxReadLength bounds the payload of a single frame sent by the client
+	MaxReadLength int64
+	// PongHandler is called with the payload of each pong
+	PongHandler func(payload []byte)
+}
+
+// Upgrade performs the opening handshake and takes over the connection.
+// On failure an HTTP error has already been written.
+func Upgrade(w http.ResponseWriter, r *http.Request) (*Conn, error) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return nil, errors.New("websocket: handshake requires GET")
+	}
+	if !headerContainsToken(r.Header, "Connection", "upgrade") || !headerContainsToken(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "Upgrade required", http.StatusUpgradeRequired)
+		return nil, errors.New("websocket: missing upgrade headers")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "Unsupported WebSocket version", http.StatusUpgradeRequired)
+		return nil, errors.New("websocket: unsupported version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if decoded, err := base64.StdEncoding.DecodeString(key); err != nil || len(decoded) != 16 {
+		http.Error(w, "Invalid Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, errors.New("websocket: invalid key")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "WebSocket is not supported", http.StatusInternalServerError)
+		return nil, errors.New("websocket: response does not support hijacking")
+	}
+	netConn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + acceptKey(key) + "\r\n\r\n"
+	if _, err := netConn.Write([]byte(response)); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	return &Conn{
+		netConn:       netConn,
+		reader:        rw.Reader,
+		MaxReadLength: defaultMaxReadLength,
+	}, nil
+}
+
+func acceptKey(key string) string {
+	sum := sha1.Sum([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func headerContainsToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// WriteText sends a text message, failing if it cannot be written before deadline
+func (c *Conn) WriteText(data []byte, deadline time.Time) error {
+	return c.writeFrame(opText, data, deadline)
+}
+
+// WritePing sends a ping; the client answers with a pong
+func (c *Conn) WritePing(deadline time.Time) error {
+	return c.writeFrame(opPing, nil, deadline)
+}
+
+// Close sends a close frame with the status code and closes the connection
+func (c *Conn) Close(code int, reason string) error {
+	payload := make([]byte, 2, 2+len(reason))
+	binary.BigEndian.PutUint16(payload, uint16(code))
+	payload = append(payload, reason...)
+	if len(payload) > maxControlPayload {
+		payload = payload[:maxControlPayload]
+	}
+
+	err := c.writeFrame(opClose, payload, time.Now().Add(time.Second))
+
+	c.writeMu.Lock()
+	c.closed = true
+	c.writeMu.Unlock()
+
+	if closeErr := c.netConn.Close(); err == nil && !errors.Is(closeErr, net.ErrClosed) {
+		err = closeErr
+	}
+	return err
+}
+
+func (c *Conn) writeFrame(opcode byte, payload []byte, deadline time.Time) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+
+	// サーバーからのフレームはマスクしない (RFC 6455 5.1)
+	header := make([]byte, 2, 10)
+	header[0] = 0x80 | opcode
+	switch length := len(payload); {
+	case length <= 125:
+		header[1] = byte(length)
+	case length <= 0xFFFF:
+		header[1] = 126
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header[1] = 127
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	if err := c.netConn.SetWriteDeadline(deadline); err != nil {
+		return err
+	}
+	if _, err := c.netConn.Write(append(header, payload...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ReadLoop reads frames until the connection closes, answering pings and
+// close frames and discarding data messages. readTimeout is extended on
+// every frame, so clients must keep answering pings.
+func (c *Conn) ReadLoop(readTimeout time.Duration) error {
+	for {
+		if err := c.netConn.SetReadDeadline(time.Now().Add(readTimeout)); err != nil {
+			return err
+		}
+
+		opcode, payload, err := c.readFrame()
+		if err != nil {
+			if errors.Is(err, errMessageTooLarge) {
+				c.Close(CloseMessageTooBig, "")
+			} else if errors.Is(err, errProtocol) {
+				c.Close(CloseProtocolError, "")
+			}
+			return err
+		}
+
+		switch opcode {
+		case opPing:
+			if err := c.writeFrame(opPong, payload, time.Now().Add(time.Second)); err != nil {
+				return err
+			}
+		case opPong:
+			if c.PongHandler != nil {
+				c.PongHandler(payload)
+			}
+		case opClose:
+			code := CloseNormal
+			if len(payload) >= 2 {
+				code = int(binary.BigEndian.Uint16(payload))
+			}
+			c.Close(code, "")
+			return ErrClosed
+		}
+	}
+}
+
+func (c *Conn) readFrame() (byte, []byte, error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return 0, nil, err
+	}
+
+	fin := header[0]&0x80 != 0
+	if header[0]&0x70 != 0 {
+		// 拡張はネゴシエートしていないため RSV ビットは常に 0
+		return 0, nil, errProtocol
+	}
+	opcode := header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	if !masked {
+		// クライアントからのフレームは必ずマスクされる (RFC 6455 5.1)
+		return 0, nil, errProtocol
+	}
+
+	length := int64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var extended [2]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint16(extended[:]))
+	case 127:
+		var extended [8]byte
+		if _, err := io.ReadFull(c.reader, extended[:]); err != nil {
+			return 0, nil, err
+		}
+		length = int64(binary.BigEndian.Uint64(extended[:]))
+	}
+
+	isControl := opcode >= opClose
+	switch opcode {
+	case opContinuation, opText, opBinary, opClose, opPing, opPong:
+	default:
+		return 0, nil, errProtocol
+	}
+	if isControl && (!fin || length > maxControlPayload) {
+		return 0, nil, errProtocol
+	}
+	if length < 0 || length > c.MaxReadLength {
+		return 0, nil, errMessageTooLarge
+	}
+
+	var mask [4]byte
+	if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+		return 0, nil, err
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return 0, nil, err
+	}
+	for i := range payload {
+		payload[i] ^= mask[i%4]
+	}
+
+	return opcode, payload, nil
+}
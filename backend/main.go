@@ -1,48 +1,70 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/infrastructure"
-	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/infrastructure/mysql"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/infrastructure/scheduler"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/interface/handler"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/logging"
 )
 
 func main() {
-	// Database configuration
-	dbConfig := mysql.Config{
-		Host:     getEnv("DB_HOST", "localhost"),
-		Port:     getEnv("DB_PORT", "3306"),
-		User:     getEnv("DB_USER", "root"),
-		Password: getEnv("DB_PASSWORD", "rootpassword"),
-		Database: getEnv("DB_NAME", "fortunespinner"),
-	}
+	config := infrastructure.LoadConfig()
+
+	// Everything, including the standard log package, logs through slog
+	logger := infrastructure.NewLogger(os.Stdout, config.LogFormat, config.LogLevel)
+	slog.SetDefault(logger)
+
+	// Cancelled on SIGINT/SIGTERM to stop background jobs and the server
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// Initialize DI container with all dependencies
-	container, err := infrastructure.NewContainer(dbConfig)
+	container, err := infrastructure.NewContainer(config)
 	if err != nil {
-		log.Fatalf("Failed to initialize container: %v", err)
+		slog.Error("Failed to initialize container", "error", err)
+		os.Exit(1)
 	}
 	defer container.Close()
 
+	// Publish the configured drop rates as a new version if they changed
+	if table, err := container.GachaUsecase.PublishRates(ctx); err != nil {
+		slog.Error("Failed to publish gacha rate table", "error", err)
+	} else {
+		slog.Info("Gacha rate table in effect", "version", table.Version)
+	}
+
 	// Setup routes
 	mux := http.NewServeMux()
 
+	// Request ID and access log middleware
+	logged := func(next http.HandlerFunc) http.HandlerFunc {
+		return handler.WithClientInfo(config.TrustProxyHeaders, handler.LogRequests(logger, next))
+	}
+
 	// CORS middleware
 	corsHandler := func(next http.HandlerFunc) http.HandlerFunc {
 		return func(w http.ResponseWriter, r *http.Request) {
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
 			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
 
 			if r.Method == "OPTIONS" {
 				w.WriteHeader(http.StatusOK)
 				return
 			}
 
-			next(w, r)
+			logged(next)(w, r)
 		}
 	}
 
@@ -53,10 +75,38 @@ func main() {
 	// Gacha routes
 	mux.HandleFunc("/api/gacha/execute", corsHandler(container.GachaHandler.ExecuteGacha))
 	mux.HandleFunc("/api/gacha/history", corsHandler(container.GachaHandler.GetGachaHistory))
+	mux.HandleFunc("/api/gacha/stats", corsHandler(container.GachaHandler.GetGachaStats))
+	mux.HandleFunc("/api/gacha/rarities", corsHandler(container.GachaHandler.GetRarities))
+	mux.HandleFunc("/api/gacha/rates", corsHandler(container.GachaHandler.GetGachaRates))
+	mux.HandleFunc("/api/gacha/rates/history", corsHandler(container.GachaHandler.GetGachaRateHistory))
 
 	// Point routes
 	mux.HandleFunc("/api/points/balance", corsHandler(container.PointHandler.GetBalance))
 	mux.HandleFunc("/api/points/transactions", corsHandler(container.PointHandler.GetTransactionHistory))
+	mux.HandleFunc("/api/points/transfer", corsHandler(container.PointHandler.TransferPoints))
+
+	// Leaderboard routes
+	mux.HandleFunc("/api/leaderboards", corsHandler(container.LeaderboardHandler.GetLeaderboard))
+
+	// Real-time routes
+	mux.HandleFunc("/api/stream", corsHandler(container.StreamHandler.Stream))
+	mux.HandleFunc("/api/ws/wins", logged(container.WinsHandler.Stream))
+
+	// Admin routes
+	adminHandler := func(next http.HandlerFunc) http.HandlerFunc {
+		return corsHandler(handler.RequireAdmin(config.AdminTokens, next))
+	}
+	mux.HandleFunc("/api/admin/points/adjust", adminHandler(container.AdminPointHandler.AdjustPoints))
+	mux.HandleFunc("/api/admin/points/adjustments", adminHandler(container.AdminPointHandler.ListAdjustments))
+	mux.HandleFunc("/api/admin/users/", adminHandler(container.AdminUserHandler.HandleUsers))
+	mux.HandleFunc("/api/admin/abuse/flags", adminHandler(container.AdminAbuseHandler.HandleFlags))
+	mux.HandleFunc("/api/admin/abuse/flags/", adminHandler(container.AdminAbuseHandler.HandleFlags))
+	mux.HandleFunc("/api/admin/leaderboards/rebuild", adminHandler(container.LeaderboardHandler.RebuildLeaderboards))
+	mux.HandleFunc("/api/admin/audit", adminHandler(container.AdminAuditHandler.HandleAudit))
+	mux.HandleFunc("/api/admin/audit/verify", adminHandler(container.AdminAuditHandler.VerifyChain))
+	mux.HandleFunc("/api/admin/outbox", adminHandler(container.OutboxHandler.GetStatus))
+	mux.HandleFunc("/api/admin/webhooks", adminHandler(container.AdminWebhookHandler.HandleWebhooks))
+	mux.HandleFunc("/api/admin/webhooks/", adminHandler(container.AdminWebhookHandler.HandleWebhooks))
 
 	// Health check
 	mux.HandleFunc("/health", corsHandler(func(w http.ResponseWriter, r *http.Request) {
@@ -64,17 +114,51 @@ func main() {
 		w.Write([]byte(`{"status":"ok"}`))
 	}))
 
+	// Background jobs
+	go scheduler.Every(ctx, "point-expiry", config.PointExpiryInterval, func(ctx context.Context) error {
+		affected, err := container.PointUsecase.ExpirePoints(ctx, time.Now())
+		if affected > 0 {
+			logging.FromContext(ctx).Info("Expired points", "users", affected)
+		}
+		return err
+	})
+	go scheduler.Every(ctx, "outbox-relay", config.OutboxRelayInterval, func(ctx context.Context) error {
+		_, err := container.OutboxUsecase.RelayPending(ctx)
+		return err
+	})
+	go scheduler.Every(ctx, "audit-chain", config.AuditChainInterval, func(ctx context.Context) error {
+		_, err := container.AuditUsecase.ChainPending(ctx)
+		return err
+	})
+	go scheduler.Every(ctx, "webhook-delivery", config.WebhookDeliveryInterval, func(ctx context.Context) error {
+		_, err := container.WebhookUsecase.DeliverPending(ctx)
+		return err
+	})
+
 	// Start server
-	port := getEnv("PORT", "8080")
-	log.Printf("Server starting on port %s", port)
-	if err := http.ListenAndServe(fmt.Sprintf(":%s", port), mux); err != nil {
-		log.Fatalf("Server failed to start: %v", err)
+	server := &http.Server{
+		Addr:    fmt.Sprintf(":%s", config.Port),
+		Handler: mux,
 	}
-}
+	// Shutdown waits for open requests, so end the long-lived streams first.
+	// WebSocket connections are hijacked and not tracked by Shutdown; the hub
+	// sends them a close frame.
+	server.RegisterOnShutdown(container.StreamBroker.Close)
+	server.RegisterOnShutdown(container.WinsHub.Close)
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			slog.Error("Server shutdown failed", "error", err)
+		}
+	}()
 
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
+	slog.Info("Server starting", "port", config.Port)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		slog.Error("Server failed to start", "error", err)
+		os.Exit(1)
 	}
-	return defaultValue
-}
\ No newline at end of file
+	slog.Info("Server stopped")
+}
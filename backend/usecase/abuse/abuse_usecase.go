@@ -0,0 +1,389 @@
+package abuse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/event"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/audit"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/logging"
+)
+
+// spinSampleSize bounds how many recent spins the cadence rules look at
+const spinSampleSize = 50
+
+// ReviewAction is an admin's decision on an open flag
+type ReviewAction string
+
+const (
+	ReviewDismiss ReviewAction = "dismiss"
+	ReviewFreeze  ReviewAction = "freeze"
+)
+
+var ErrFlagNotFound = errors.New("abuse flag not found")
+
+// Throttle slows down users whose open flag scored above the throttle score.
+// Call it inside the spin transaction after the spend limit guard, which
+// serializes spins per user.
+type Throttle interface {
+	CheckSpin(ctx context.Context, userID int, now time.Time) error
+}
+
+type AbuseUsecase interface {
+	Throttle
+	// Evaluate scores the user's recent activity and opens or raises their
+	// flag. It returns the open flag, or nil when the user is not flagged.
+	Evaluate(ctx context.Context, userID int) (*model.AbuseFlag, error)
+	// HandleUserEvent evaluates the user a signup, spin or transfer concerns
+	HandleUserEvent(ctx context.Context, e event.Event) error
+	ListFlags(ctx context.Context, status model.AbuseFlagStatus, limit int) ([]*model.AbuseFlag, error)
+	// ReviewFlag dismisses a flag or freezes the user's points
+	ReviewFlag(ctx context.Context, flagID int, action ReviewAction, adminID, note string) (*model.AbuseFlag, error)
+	FreezePoints(ctx context.Context, userID int, reason string) (*model.UserPoint, error)
+	UnfreezePoints(ctx context.Context, userID int) (*model.UserPoint, error)
+}
+
+type abuseUsecase struct {
+	abuseRepo  repository.AbuseRepository
+	userRepo   repository.UserRepository
+	gachaRepo  repository.GachaRepository
+	pointRepo  repository.PointRepository
+	transactor repository.Transactor
+	recorder   audit.Recorder
+	rules      model.AbuseRules
+}
+
+func NewAbuseUsecase(
+	abuseRepo repository.AbuseRepository,
+	userRepo repository.UserRepository,
+	gachaRepo repository.GachaRepository,
+	pointRepo repository.PointRepository,
+	transactor repository.Transactor,
+	recorder audit.Recorder,
+	rules model.AbuseRules,
+) AbuseUsecase {
+	return &abuseUsecase{
+		abuseRepo:  abuseRepo,
+		userRepo:   userRepo,
+		gachaRepo:  gachaRepo,
+		pointRepo:  pointRepo,
+		transactor: transactor,
+		recorder:   recorder,
+		rules:      rules,
+	}
+}
+
+func (uc *abuseUsecase) Evaluate(ctx context.Context, userID int) (*model.AbuseFlag, error) {
+	now := time.Now()
+	signals, err := uc.collectSignals(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	assessment := uc.rules.Assess(*signals)
+
+	// イベントはリレーから順に届くため、同じユーザーの評価が並行することはない
+	var open *model.AbuseFlag
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		latest, err := uc.abuseRepo.FindLatestFlagByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if latest != nil && latest.Status == model.AbuseFlagOpen {
+			open = latest
+			if !latest.Reassess(assessment, uc.rules, now) {
+				return nil
+			}
+			return uc.abuseRepo.UpdateFlag(ctx, latest)
+		}
+
+		if assessment.Score < uc.rules.FlagScore {
+			return nil
+		}
+		// 審査済みのフラグと同じ根拠では再度フラグを立てない
+		if latest != nil && assessment.Score <= latest.Score {
+			return nil
+		}
+
+		open = model.NewAbuseFlag(userID, assessment, uc.rules, now)
+		return uc.abuseRepo.SaveFlag(ctx, open)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return open, nil
+}
+
+func (uc *abuseUsecase) collectSignals(ctx context.Context, userID int, now time.Time) (*model.AbuseSignals, error) {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, model.ErrUserNotFound
+	}
+
+	since := now.Add(-uc.rules.Window)
+	signals := &model.AbuseSignals{
+		UserID:         userID,
+		AccountAge:     now.Sub(user.CreatedAt),
+		ClientRecorded: user.SignupIP != "",
+		UserAgent:      user.SignupUserAgent,
+	}
+
+	// 履歴は新しい順に返る
+	results, err := uc.gachaRepo.FindResultsByUserID(ctx, userID, spinSampleSize)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		if result.CreatedAt.Before(since) {
+			break
+		}
+		signals.SpinTimes = append(signals.SpinTimes, result.CreatedAt)
+	}
+
+	if signals.ClientRecorded {
+		if signals.AccountsFromIP, err = uc.abuseRepo.CountSignupsFromIP(ctx, user.SignupIP, since); err != nil {
+			return nil, err
+		}
+		if user.SignupUserAgent != "" {
+			if signals.AccountsWithUserAgent, err = uc.abuseRepo.CountSignupsWithUserAgent(ctx, user.SignupUserAgent, since); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if signals.NewAccountSenders, err = uc.abuseRepo.CountNewAccountSenders(ctx, userID, since, since); err != nil {
+		return nil, err
+	}
+	if signals.PointsEarned, err = uc.pointRepo.SumTransactionsSince(ctx, userID, model.TransactionTypeGacha, since); err != nil {
+		return nil, err
+	}
+	sent, err := uc.pointRepo.SumTransactionsSince(ctx, userID, model.TransactionTypeTransferOut, since)
+	if err != nil {
+		return nil, err
+	}
+	// 送金は負数で記録されている
+	signals.PointsSent = -sent
+
+	return signals, nil
+}
+
+func (uc *abuseUsecase) HandleUserEvent(ctx context.Context, e event.Event) error {
+	userEvent, ok := e.(event.UserEvent)
+	if !ok {
+		return fmt.Errorf("abuse detection cannot handle %s", e.EventName())
+	}
+
+	// ガチャの付与ポイントは gacha.executed で評価済みのため、送金のみ対象とする
+	var changed event.PointsChanged
+	switch pointsEvent := e.(type) {
+	case event.PointsCredited:
+		changed = pointsEvent.PointsChanged
+	case event.PointsDebited:
+		changed = pointsEvent.PointsChanged
+	}
+	if changed.Type != "" && changed.Type != model.TransactionTypeTransferIn && changed.Type != model.TransactionTypeTransferOut {
+		return nil
+	}
+
+	if _, err := uc.Evaluate(ctx, userEvent.EventUserID()); err != nil {
+		return fmt.Errorf("evaluate abuse signals for user %d: %w", userEvent.EventUserID(), err)
+	}
+	return nil
+}
+
+func (uc *abuseUsecase) CheckSpin(ctx context.Context, userID int, now time.Time) error {
+	flag, err := uc.abuseRepo.FindLatestFlagByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if flag == nil || flag.Status != model.AbuseFlagOpen || !flag.Throttled {
+		return nil
+	}
+
+	recent, err := uc.gachaRepo.CountResultsSince(ctx, userID, now.Add(-uc.rules.ThrottleInterval))
+	if err != nil {
+		return err
+	}
+	if recent > 0 {
+		return fmt.Errorf("%w: this account may spin once every %s while under review", model.ErrSpinThrottled, uc.rules.ThrottleInterval)
+	}
+	return nil
+}
+
+func (uc *abuseUsecase) ListFlags(ctx context.Context, status model.AbuseFlagStatus, limit int) ([]*model.AbuseFlag, error) {
+	return uc.abuseRepo.FindFlags(ctx, status, limit)
+}
+
+func (uc *abuseUsecase) ReviewFlag(ctx context.Context, flagID int, action ReviewAction, adminID, note string) (*model.AbuseFlag, error) {
+	logging.AddAttrs(ctx, logging.KeyOperation, audit.ActionAbuseFlagReview, "flag_id", flagID)
+	var status model.AbuseFlagStatus
+	switch action {
+	case ReviewDismiss:
+		status = model.AbuseFlagDismissed
+	case ReviewFreeze:
+		status = model.AbuseFlagFrozen
+	default:
+		return nil, fmt.Errorf("unknown review action %q", action)
+	}
+
+	var flag *model.AbuseFlag
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		flag, err = uc.abuseRepo.FindFlagByIDForUpdate(ctx, flagID)
+		if err != nil {
+			return err
+		}
+		if flag == nil {
+			return ErrFlagNotFound
+		}
+
+		now := time.Now()
+		before := *flag
+		if err := flag.Resolve(status, adminID, note, now); err != nil {
+			return err
+		}
+		if status == model.AbuseFlagFrozen {
+			reason := fmt.Sprintf("abuse flag #%d", flag.ID)
+			if flag.ReviewNote != "" {
+				reason += ": " + flag.ReviewNote
+			}
+			// 既に凍結済みの場合はそのまま審査結果のみ記録する
+			if _, err := uc.freeze(ctx, flag.UserID, reason, now); err != nil && !errors.Is(err, model.ErrAlreadyFrozen) {
+				return err
+			}
+		}
+		if err := uc.abuseRepo.UpdateFlag(ctx, flag); err != nil {
+			return err
+		}
+		return uc.recorder.Record(ctx, audit.Change{
+			Actor:      audit.AdminActor(adminID),
+			Action:     audit.ActionAbuseFlagReview,
+			TargetType: "abuse_flag",
+			TargetID:   strconv.Itoa(flag.ID),
+			Before:     &before,
+			After:      flag,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return flag, nil
+}
+
+func (uc *abuseUsecase) FreezePoints(ctx context.Context, userID int, reason string) (*model.UserPoint, error) {
+	logging.Operation(ctx, audit.ActionPointsFreeze, userID)
+	if err := uc.ensureUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	var userPoint *model.UserPoint
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		userPoint, err = uc.freeze(ctx, userID, reason, time.Now())
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return userPoint, nil
+}
+
+// freeze locks the user's point row and freezes it, creating the row for
+// users who have never held points so the freeze also blocks their first credit
+func (uc *abuseUsecase) freeze(ctx context.Context, userID int, reason string, now time.Time) (*model.UserPoint, error) {
+	userPoint, err := uc.pointRepo.GetUserPointForUpdate(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var before *model.UserPoint
+	isNew := userPoint == nil
+	if isNew {
+		if userPoint, err = model.NewUserPoint(userID); err != nil {
+			return nil, err
+		}
+	} else {
+		previous := *userPoint
+		before = &previous
+	}
+	if err := userPoint.Freeze(reason, now); err != nil {
+		return nil, err
+	}
+
+	if isNew {
+		err = uc.pointRepo.CreateUserPoint(ctx, userPoint)
+	} else {
+		err = uc.pointRepo.UpdateUserPoint(ctx, userPoint)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	change := audit.Change{
+		Action:     audit.ActionPointsFreeze,
+		TargetType: "user_points",
+		TargetID:   strconv.Itoa(userID),
+		After:      userPoint,
+	}
+	if before != nil {
+		change.Before = before
+	}
+	if err := uc.recorder.Record(ctx, change); err != nil {
+		return nil, err
+	}
+	return userPoint, nil
+}
+
+func (uc *abuseUsecase) UnfreezePoints(ctx context.Context, userID int) (*model.UserPoint, error) {
+	logging.Operation(ctx, audit.ActionPointsUnfreeze, userID)
+	var userPoint *model.UserPoint
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		userPoint, err = uc.pointRepo.GetUserPointForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if userPoint == nil {
+			return errors.New("point account is not frozen")
+		}
+		before := *userPoint
+		if err := userPoint.Unfreeze(time.Now()); err != nil {
+			return err
+		}
+		if err := uc.pointRepo.UpdateUserPoint(ctx, userPoint); err != nil {
+			return err
+		}
+		return uc.recorder.Record(ctx, audit.Change{
+			Action:     audit.ActionPointsUnfreeze,
+			TargetType: "user_points",
+			TargetID:   strconv.Itoa(userID),
+			Before:     &before,
+			After:      userPoint,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return userPoint, nil
+}
+
+func (uc *abuseUsecase) ensureUser(ctx context.Context, userID int) error {
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return model.ErrUserNotFound
+	}
+	return nil
+}
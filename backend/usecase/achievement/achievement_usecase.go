@@ -0,0 +1,187 @@
+package achievement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/event"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/logging"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/point"
+)
+
+// streakWindowDays bounds how far back spin days are loaded for streak rules
+const streakWindowDays = 60
+
+type AchievementUsecase interface {
+	// Evaluate unlocks every achievement the user newly qualifies for and
+	// pays out its reward through the ledger
+	Evaluate(ctx context.Context, userID int) ([]*model.UserAchievement, error)
+	GetUserAchievements(ctx context.Context, userID int) ([]model.AchievementStatus, error)
+	// HandleUserEvent evaluates achievements for the user a spin or point event concerns
+	HandleUserEvent(ctx context.Context, e event.Event) error
+}
+
+type achievementUsecase struct {
+	achievementRepo repository.AchievementRepository
+	userRepo        repository.UserRepository
+	transactor      repository.Transactor
+	wallet          point.Wallet
+	publisher       event.Publisher
+	rules           []model.AchievementRule
+}
+
+func NewAchievementUsecase(
+	achievementRepo repository.AchievementRepository,
+	userRepo repository.UserRepository,
+	transactor repository.Transactor,
+	wallet point.Wallet,
+	publisher event.Publisher,
+	rules []model.AchievementRule,
+) (AchievementUsecase, error) {
+	codes := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if err := rule.Validate(); err != nil {
+			return nil, fmt.Errorf("achievement %q: %w", rule.Code, err)
+		}
+		if codes[rule.Code] {
+			return nil, fmt.Errorf("duplicate achievement code %q", rule.Code)
+		}
+		codes[rule.Code] = true
+	}
+
+	return &achievementUsecase{
+		achievementRepo: achievementRepo,
+		userRepo:        userRepo,
+		transactor:      transactor,
+		wallet:          wallet,
+		publisher:       publisher,
+		rules:           rules,
+	}, nil
+}
+
+func (uc *achievementUsecase) Evaluate(ctx context.Context, userID int) ([]*model.UserAchievement, error) {
+	var unlocked []*model.UserAchievement
+	var events []event.Event
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := uc.achievementRepo.FindByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		have := make(map[string]bool, len(existing))
+		for _, achievement := range existing {
+			have[achievement.Code] = true
+		}
+
+		// 報酬で残高が増えると別の実績を満たす場合があるため、新規解除がなくなるまで繰り返す
+		for {
+			now := time.Now()
+			progress, spinDays, err := uc.achievementRepo.GetProgress(ctx, userID, now.AddDate(0, 0, -streakWindowDays))
+			if err != nil {
+				return err
+			}
+			progress.StreakDays = model.SpinStreakDays(spinDays, now)
+
+			newlyUnlocked := 0
+			for _, rule := range uc.rules {
+				if have[rule.Code] || !rule.IsSatisfied(progress) {
+					continue
+				}
+
+				achievement := model.NewUserAchievement(userID, rule)
+				inserted, err := uc.achievementRepo.Unlock(ctx, achievement)
+				if err != nil {
+					return err
+				}
+				have[rule.Code] = true
+				if !inserted {
+					continue
+				}
+
+				if rule.RewardPoints > 0 {
+					reward, err := model.NewPointTransaction(userID, rule.RewardPoints, model.TransactionTypeAchievement, "Achievement reward: "+rule.Name)
+					if err != nil {
+						return err
+					}
+					if err := uc.wallet.Credit(ctx, reward); err != nil {
+						return err
+					}
+					achievement.RewardTransactionID = reward.ID
+					if err := uc.achievementRepo.SetRewardTransaction(ctx, achievement); err != nil {
+						return err
+					}
+					events = append(events, event.NewPointsChanged(reward))
+				}
+
+				unlocked = append(unlocked, achievement)
+				events = append(events, event.AchievementUnlocked{
+					UserID:       userID,
+					Code:         rule.Code,
+					RewardPoints: rule.RewardPoints,
+					OccurredAt:   achievement.UnlockedAt,
+				})
+				newlyUnlocked++
+			}
+
+			if newlyUnlocked == 0 {
+				if len(events) == 0 {
+					return nil
+				}
+				return uc.publisher.Publish(ctx, events...)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return unlocked, nil
+}
+
+func (uc *achievementUsecase) GetUserAchievements(ctx context.Context, userID int) ([]model.AchievementStatus, error) {
+	logging.Operation(ctx, "achievements.get", userID)
+	// ユーザーの存在確認
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, model.ErrUserNotFound
+	}
+
+	unlocked, err := uc.achievementRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	byCode := make(map[string]*model.UserAchievement, len(unlocked))
+	for _, achievement := range unlocked {
+		byCode[achievement.Code] = achievement
+	}
+
+	statuses := make([]model.AchievementStatus, 0, len(uc.rules))
+	for _, rule := range uc.rules {
+		statuses = append(statuses, model.AchievementStatus{
+			Rule:     rule,
+			Unlocked: byCode[rule.Code],
+		})
+	}
+	return statuses, nil
+}
+
+func (uc *achievementUsecase) HandleUserEvent(ctx context.Context, e event.Event) error {
+	userEvent, ok := e.(event.UserEvent)
+	if !ok {
+		return fmt.Errorf("achievements cannot handle %s", e.EventName())
+	}
+	if _, err := uc.Evaluate(ctx, userEvent.EventUserID()); err != nil {
+		// 凍結中は報酬を付与できないため、解除後の次のイベントで改めて評価する
+		if errors.Is(err, model.ErrPointsFrozen) {
+			return nil
+		}
+		return fmt.Errorf("evaluate achievements for user %d: %w", userEvent.EventUserID(), err)
+	}
+	return nil
+}
@@ -0,0 +1,229 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+// Actions recorded in the audit log
+const (
+	ActionUserCreate          = "user.create"
+	ActionUserRename          = "user.rename"
+	ActionUserVerification    = "user.verification"
+	ActionUserStatus          = "user.status"
+	ActionUserToken           = "user.token"
+	ActionGachaExecute        = "gacha.execute"
+	ActionPointsTransfer      = "points.transfer"
+	ActionPointsAdjust        = "points.adjust"
+	ActionPointsFreeze        = "points.freeze"
+	ActionPointsUnfreeze      = "points.unfreeze"
+	ActionSpendLimitsUpdate   = "spend_limits.update"
+	ActionAbuseFlagReview     = "abuse_flag.review"
+	ActionWebhookRegister     = "webhook.register"
+	ActionWebhookReplay       = "webhook_delivery.replay"
+	ActionLeaderboardsRebuild = "leaderboards.rebuild"
+)
+
+const (
+	defaultSearchLimit = 100
+	maxSearchLimit     = 500
+	verifyBatchSize    = 1000
+	chainBatchSize     = 500
+)
+
+// Change describes a state change to record. Actor is who made it when no
+// admin is acting; an admin set by WithAdmin always takes precedence.
+type Change struct {
+	Actor      string
+	Action     string
+	TargetType string
+	TargetID   string
+	Before     interface{}
+	After      interface{}
+}
+
+// Recorder appends changes to the audit log. Call Record inside the
+// transaction that makes the change so the entry commits with it. Entries
+// are queued and chained by ChainPending, so recording takes no lock shared
+// with other transactions.
+type Recorder interface {
+	Record(ctx context.Context, change Change) error
+}
+
+// VerifyResult is the outcome of checking the whole hash chain
+type VerifyResult struct {
+	Checked int64
+	Valid   bool
+	// BrokenAt is the first entry that does not fit the chain when not valid
+	BrokenAt int64
+	Head     model.AuditChainHead
+}
+
+type AuditUsecase interface {
+	Recorder
+	// ChainPending moves queued entries into the hash chain in the order they
+	// are found and returns how many were chained
+	ChainPending(ctx context.Context) (int, error)
+	// Search finds chained entries; entries still queued are not included
+	Search(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error)
+	// Verify recomputes every hash and checks the chain ends at the stored head
+	Verify(ctx context.Context) (*VerifyResult, error)
+}
+
+type auditUsecase struct {
+	auditRepo  repository.AuditRepository
+	transactor repository.Transactor
+}
+
+func NewAuditUsecase(auditRepo repository.AuditRepository, transactor repository.Transactor) AuditUsecase {
+	return &auditUsecase{
+		auditRepo:  auditRepo,
+		transactor: transactor,
+	}
+}
+
+func (uc *auditUsecase) Record(ctx context.Context, change Change) error {
+	before, err := snapshot(change.Before)
+	if err != nil {
+		return err
+	}
+	after, err := snapshot(change.After)
+	if err != nil {
+		return err
+	}
+
+	request := RequestFromContext(ctx)
+	actor := change.Actor
+	if request.AdminID != "" {
+		actor = AdminActor(request.AdminID)
+	}
+	if actor == "" {
+		actor = model.AuditActorSystem
+	}
+
+	entry, err := model.NewAuditEntry(actor, change.Action, change.TargetType, change.TargetID, before, after, request.RequestID, request.IP, time.Now())
+	if err != nil {
+		return err
+	}
+
+	// 連鎖はバックグラウンドで行い、ここでは先頭の行ロックを取らない
+	return uc.auditRepo.Enqueue(ctx, entry)
+}
+
+func (uc *auditUsecase) ChainPending(ctx context.Context) (int, error) {
+	chained := 0
+	for {
+		batch := 0
+		err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			head, err := uc.auditRepo.LockHead(ctx)
+			if err != nil {
+				return err
+			}
+			entries, err := uc.auditRepo.ClaimQueued(ctx, chainBatchSize)
+			if err != nil {
+				return err
+			}
+			for _, entry := range entries {
+				entry.Chain(head)
+				if err := uc.auditRepo.Append(ctx, entry); err != nil {
+					return err
+				}
+				head = model.AuditChainHead{Seq: entry.Seq, Hash: entry.Hash}
+			}
+			batch = len(entries)
+			return nil
+		})
+		if err != nil {
+			return chained, err
+		}
+		chained += batch
+
+		if batch < chainBatchSize {
+			return chained, nil
+		}
+	}
+}
+
+func snapshot(state interface{}) (json.RawMessage, error) {
+	if state == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("audit snapshot: %w", err)
+	}
+	return data, nil
+}
+
+func (uc *auditUsecase) Search(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultSearchLimit
+	}
+	if filter.Limit > maxSearchLimit {
+		filter.Limit = maxSearchLimit
+	}
+	return uc.auditRepo.FindEntries(ctx, filter)
+}
+
+func (uc *auditUsecase) Verify(ctx context.Context) (*VerifyResult, error) {
+	// 検証中に追記されても、読み始めた時点の先頭までを検証する
+	stored, err := uc.auditRepo.GetHead(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VerifyResult{Valid: true}
+	for result.Head.Seq < stored.Seq {
+		entries, err := uc.auditRepo.FindEntriesAfter(ctx, result.Head.Seq, verifyBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		entries = trimAfter(entries, stored.Seq)
+		if len(entries) == 0 {
+			// 先頭より前のエントリが欠けている
+			result.Valid = false
+			result.BrokenAt = result.Head.Seq + 1
+			return result, nil
+		}
+
+		head, brokenAt, ok := model.VerifyAuditChain(result.Head, entries)
+		result.Checked += head.Seq - result.Head.Seq
+		result.Head = head
+		if !ok {
+			result.Valid = false
+			result.BrokenAt = brokenAt
+			return result, nil
+		}
+	}
+
+	if result.Head != stored {
+		result.Valid = false
+		result.BrokenAt = stored.Seq
+	}
+	return result, nil
+}
+
+func trimAfter(entries []*model.AuditEntry, seq int64) []*model.AuditEntry {
+	for i, entry := range entries {
+		if entry.Seq > seq {
+			return entries[:i]
+		}
+	}
+	return entries
+}
+
+// UserActor names a user acting on their own account
+func UserActor(userID int) string {
+	return "user:" + strconv.Itoa(userID)
+}
+
+// AdminActor names an admin acting through the admin API
+func AdminActor(adminID string) string {
+	return "admin:" + adminID
+}
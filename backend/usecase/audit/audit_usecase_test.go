@@ -0,0 +1,179 @@
+package audit
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+// fakeAuditRepository keeps the queue, chain and head in memory
+type fakeAuditRepository struct {
+	queue  []*model.AuditEntry
+	nextID int64
+	log    []*model.AuditEntry
+	head   model.AuditChainHead
+}
+
+func (r *fakeAuditRepository) Enqueue(ctx context.Context, entry *model.AuditEntry) error {
+	r.nextID++
+	entry.QueueID = r.nextID
+	r.queue = append(r.queue, entry)
+	return nil
+}
+
+func (r *fakeAuditRepository) ClaimQueued(ctx context.Context, limit int) ([]*model.AuditEntry, error) {
+	if len(r.queue) < limit {
+		limit = len(r.queue)
+	}
+	return append([]*model.AuditEntry{}, r.queue[:limit]...), nil
+}
+
+func (r *fakeAuditRepository) LockHead(ctx context.Context) (model.AuditChainHead, error) {
+	return r.head, nil
+}
+
+func (r *fakeAuditRepository) Append(ctx context.Context, entry *model.AuditEntry) error {
+	r.log = append(r.log, entry)
+	r.head = model.AuditChainHead{Seq: entry.Seq, Hash: entry.Hash}
+	for i, queued := range r.queue {
+		if queued.QueueID == entry.QueueID {
+			r.queue = append(r.queue[:i], r.queue[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (r *fakeAuditRepository) GetHead(ctx context.Context) (model.AuditChainHead, error) {
+	return r.head, nil
+}
+
+func (r *fakeAuditRepository) FindEntries(ctx context.Context, filter model.AuditFilter) ([]*model.AuditEntry, error) {
+	return nil, nil
+}
+
+func (r *fakeAuditRepository) FindEntriesAfter(ctx context.Context, afterSeq int64, limit int) ([]*model.AuditEntry, error) {
+	var entries []*model.AuditEntry
+	for _, entry := range r.log {
+		if entry.Seq > afterSeq && len(entries) < limit {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func recordChanges(t *testing.T, uc AuditUsecase, n int) {
+	t.Helper()
+	for i := 0; i < n; i++ {
+		err := uc.Record(context.Background(), Change{
+			Actor:      UserActor(i + 1),
+			Action:     ActionGachaExecute,
+			TargetType: "gacha_result",
+			After:      map[string]int{"points": i},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestRecordedChangesAreChainedInOrder(t *testing.T) {
+	repo := &fakeAuditRepository{}
+	uc := NewAuditUsecase(repo, fakeTransactor{})
+
+	recordChanges(t, uc, chainBatchSize+3)
+	if len(repo.log) != 0 {
+		t.Fatalf("Record chained %d entries, want them queued", len(repo.log))
+	}
+
+	chained, err := uc.ChainPending(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if chained != chainBatchSize+3 || len(repo.queue) != 0 {
+		t.Fatalf("chained %d, %d left in the queue", chained, len(repo.queue))
+	}
+	for i, entry := range repo.log {
+		if entry.Seq != int64(i+1) || entry.Actor != UserActor(i+1) {
+			t.Fatalf("entry %d is %+v, want seq %d by %s", i, entry, i+1, UserActor(i+1))
+		}
+	}
+
+	result, err := uc.Verify(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Valid || result.Checked != int64(chainBatchSize+3) {
+		t.Errorf("verify = %+v, want a valid chain of %d entries", result, chainBatchSize+3)
+	}
+
+	// 後から記録された分は既存の連鎖の続きに追加される
+	recordChanges(t, uc, 2)
+	if _, err := uc.ChainPending(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := uc.Verify(context.Background()); err != nil || !result.Valid || result.Head.Seq != int64(chainBatchSize+5) {
+		t.Errorf("verify after a second batch = %+v, %v", result, err)
+	}
+}
+
+func TestVerifyDetectsTamperingAndGaps(t *testing.T) {
+	tests := []struct {
+		name   string
+		tamper func(repo *fakeAuditRepository)
+		want   int64
+	}{
+		{
+			name: "edited entry",
+			tamper: func(repo *fakeAuditRepository) {
+				repo.log[2].Action = ActionPointsAdjust
+			},
+			want: 3,
+		},
+		{
+			name: "deleted entry",
+			tamper: func(repo *fakeAuditRepository) {
+				repo.log = append(repo.log[:1], repo.log[2:]...)
+			},
+			// 欠けた直後のエントリで検出される
+			want: 3,
+		},
+		{
+			name: "deleted last entry",
+			tamper: func(repo *fakeAuditRepository) {
+				repo.log = repo.log[:len(repo.log)-1]
+			},
+			want: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeAuditRepository{}
+			uc := NewAuditUsecase(repo, fakeTransactor{})
+			recordChanges(t, uc, 5)
+			if _, err := uc.ChainPending(context.Background()); err != nil {
+				t.Fatal(err)
+			}
+
+			tt.tamper(repo)
+			result, err := uc.Verify(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Valid {
+				t.Fatal("tampered log verified")
+			}
+			if result.BrokenAt != tt.want {
+				t.Errorf("broken at %d, want %d", result.BrokenAt, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package audit
+
+import "context"
+
+// Request is the HTTP request behind a change
+type Request struct {
+	RequestID string
+	IP        string
+	// AdminID is the acting admin on admin API requests
+	AdminID string
+}
+
+type requestKey struct{}
+
+// WithRequest attaches the request ID and client IP recorded with changes
+func WithRequest(ctx context.Context, requestID, ip string) context.Context {
+	request := RequestFromContext(ctx)
+	request.RequestID = requestID
+	request.IP = ip
+	return context.WithValue(ctx, requestKey{}, request)
+}
+
+// WithAdmin records the acting admin as the actor of changes
+func WithAdmin(ctx context.Context, adminID string) context.Context {
+	request := RequestFromContext(ctx)
+	request.AdminID = adminID
+	return context.WithValue(ctx, requestKey{}, request)
+}
+
+// RequestFromContext returns the request set by WithRequest and WithAdmin
+func RequestFromContext(ctx context.Context) Request {
+	request, _ := ctx.Value(requestKey{}).(Request)
+	return request
+}
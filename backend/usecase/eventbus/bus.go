@@ -0,0 +1,117 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/event"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/logging"
+)
+
+// AllEvents subscribes a handler to every event name
+const AllEvents = "*"
+
+const asyncQueueSize = 256
+
+type asyncSubscriber struct {
+	name    string
+	handler event.Handler
+	queue   chan event.Event
+}
+
+// Bus is an in-process event.Publisher. Synchronous subscribers run in the
+// publishing goroutine and their errors are returned from Publish; async
+// subscribers each get a goroutine and a bounded queue.
+type Bus struct {
+	mu      sync.RWMutex
+	sync    map[string][]event.Handler
+	async   map[string][]*asyncSubscriber
+	closed  bool
+	workers sync.WaitGroup
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		sync:  make(map[string][]event.Handler),
+		async: make(map[string][]*asyncSubscriber),
+	}
+}
+
+// Subscribe registers a handler run synchronously for events named name
+func (b *Bus) Subscribe(name string, handler event.Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sync[name] = append(b.sync[name], handler)
+}
+
+// SubscribeAsync registers a handler run on its own goroutine. It receives
+// a background context since the publisher's context may end first.
+func (b *Bus) SubscribeAsync(name string, handler event.Handler) {
+	subscriber := &asyncSubscriber{
+		name:    name,
+		handler: handler,
+		queue:   make(chan event.Event, asyncQueueSize),
+	}
+
+	b.mu.Lock()
+	b.async[name] = append(b.async[name], subscriber)
+	b.mu.Unlock()
+
+	b.workers.Add(1)
+	go func() {
+		defer b.workers.Done()
+		for e := range subscriber.queue {
+			if err := subscriber.handler(context.Background(), e); err != nil {
+				slog.Error("Async subscriber failed", "subscriber", subscriber.name, "event", e.EventName(), logging.KeyError, err)
+			}
+		}
+	}()
+}
+
+func (b *Bus) Publish(ctx context.Context, events ...event.Event) error {
+	var errs []error
+	for _, e := range events {
+		b.mu.RLock()
+		if b.closed {
+			b.mu.RUnlock()
+			return errors.New("event bus is closed")
+		}
+		handlers := append(append([]event.Handler{}, b.sync[e.EventName()]...), b.sync[AllEvents]...)
+		for _, subscriber := range append(append([]*asyncSubscriber{}, b.async[e.EventName()]...), b.async[AllEvents]...) {
+			select {
+			case subscriber.queue <- e:
+			default:
+				logging.FromContext(ctx).Warn("Async subscriber is full, dropping event", "subscriber", subscriber.name, "event", e.EventName())
+			}
+		}
+		b.mu.RUnlock()
+
+		// ロックを解放してから実行し、ハンドラ内からの再発行を許可する
+		for _, handler := range handlers {
+			if err := handler(ctx, e); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close stops accepting events and waits for async subscribers to drain their queues
+func (b *Bus) Close() {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	for _, subscribers := range b.async {
+		for _, subscriber := range subscribers {
+			close(subscriber.queue)
+		}
+	}
+	b.mu.Unlock()
+
+	b.workers.Wait()
+}
@@ -0,0 +1,107 @@
+package gacha
+
+import (
+	"errors"
+	"math/rand"
+)
+
+// Source supplies the randomness for a draw. *rand.Rand satisfies it but is
+// not safe for concurrent use; GlobalSource is. Tests and simulations can
+// inject a seeded or scripted Source to make draws deterministic.
+type Source interface {
+	Int63n(n int64) int64
+}
+
+type globalSource struct{}
+
+func (globalSource) Int63n(n int64) int64 {
+	return rand.Int63n(n)
+}
+
+// GlobalSource draws from the math/rand top-level generator
+var GlobalSource Source = globalSource{}
+
+// aliasTable picks an index weighted by integer weights in constant time
+// using Vose's alias method. Building it is linear in the number of weights.
+// The table is kept in integers, so each index is picked with exactly its
+// weight over the total.
+type aliasTable struct {
+	total int64   // sum of the weights
+	prob  []int64 // chance out of total of keeping the column's own index
+	alias []int
+}
+
+func newAliasTable(weights []int) (*aliasTable, error) {
+	n := len(weights)
+	if n == 0 {
+		return nil, errors.New("no weights to sample")
+	}
+
+	total := int64(0)
+	for _, weight := range weights {
+		if weight < 0 {
+			return nil, errors.New("weight must not be negative")
+		}
+		total += int64(weight)
+	}
+	if total == 0 {
+		return nil, errors.New("weights must not all be zero")
+	}
+
+	table := &aliasTable{
+		total: total,
+		prob:  make([]int64, n),
+		alias: make([]int, n),
+	}
+
+	// 平均が total になるよう重みを n 倍し、total 未満と以上に振り分ける
+	scaled := make([]int64, n)
+	var small, large []int
+	for i, weight := range weights {
+		scaled[i] = int64(weight) * int64(n)
+		if scaled[i] < total {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+
+	// total 未満の列を total 以上の列の余りで埋める
+	for len(small) > 0 && len(large) > 0 {
+		less := small[len(small)-1]
+		small = small[:len(small)-1]
+		more := large[len(large)-1]
+		large = large[:len(large)-1]
+
+		table.prob[less] = scaled[less]
+		table.alias[less] = more
+
+		scaled[more] -= total - scaled[less]
+		if scaled[more] < total {
+			small = append(small, more)
+		} else {
+			large = append(large, more)
+		}
+	}
+
+	// 整数演算のため残りはちょうど total
+	for _, i := range append(large, small...) {
+		table.prob[i] = total
+		table.alias[i] = i
+	}
+
+	return table, nil
+}
+
+// sample picks an index with one uniform integer below n*total: the quotient
+// selects a column and the remainder decides between the column's index and
+// its alias
+func (t *aliasTable) sample(source Source) int {
+	x := source.Int63n(int64(len(t.prob)) * t.total)
+	column := int(x / t.total)
+
+	if x%t.total < t.prob[column] {
+		return column
+	}
+	return t.alias[column]
+}
@@ -0,0 +1,128 @@
+package gacha
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+// benchmarkPoolSize is the number of items in the benchmark pool, several
+// times a large seasonal pool
+const benchmarkPoolSize = 500
+
+// benchmarkWeights returns uneven weights in parts per million that sum to
+// exactly WeightScale
+func benchmarkWeights() []int {
+	rng := rand.New(rand.NewSource(1))
+	weights := make([]int, benchmarkPoolSize)
+	mean := model.WeightScale / benchmarkPoolSize
+	// 隣り合う 2 つに同じ差を逆向きに付け、合計を WeightScale に保つ
+	for i := 0; i < len(weights); i += 2 {
+		d := rng.Intn(mean)
+		weights[i] = mean + d
+		weights[i+1] = mean - d
+	}
+	return weights
+}
+
+// linearDraw is the cumulative scan the alias table replaced
+func linearDraw(source Source, weights []int, total int64) int {
+	x := source.Int63n(total)
+	for i, weight := range weights {
+		if x < int64(weight) {
+			return i
+		}
+		x -= int64(weight)
+	}
+	return len(weights) - 1
+}
+
+func BenchmarkAliasDraw(b *testing.B) {
+	table, err := newAliasTable(benchmarkWeights())
+	if err != nil {
+		b.Fatal(err)
+	}
+	source := rand.New(rand.NewSource(1))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		table.sample(source)
+	}
+}
+
+func BenchmarkLinearDraw(b *testing.B) {
+	weights := benchmarkWeights()
+	source := rand.New(rand.NewSource(1))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		linearDraw(source, weights, model.WeightScale)
+	}
+}
+
+// chiSquared is Pearson's statistic for counts drawn with the given weights
+func chiSquared(counts []int, weights []int, draws int) float64 {
+	statistic := 0.0
+	for i, weight := range weights {
+		expected := float64(draws) * float64(weight) / model.WeightScale
+		diff := float64(counts[i]) - expected
+		statistic += diff * diff / expected
+	}
+	return statistic
+}
+
+func TestAliasTableFitsWeights(t *testing.T) {
+	weights := []int{600000, 150000, 150000, 50000, 30000, 10000, 5000, 3000, 1500, 500}
+	table, err := newAliasTable(weights)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const draws = 1000000
+	source := rand.New(rand.NewSource(42))
+	counts := make([]int, len(weights))
+	for i := 0; i < draws; i++ {
+		counts[table.sample(source)]++
+	}
+
+	// 自由度 9、有意水準 0.1% の棄却限界値
+	const critical = 27.877
+	if statistic := chiSquared(counts, weights, draws); statistic > critical {
+		t.Errorf("chi-squared = %.3f exceeds %.3f; counts %v for weights %v", statistic, critical, counts, weights)
+	}
+}
+
+func TestPoolSamplerFitsItemWeights(t *testing.T) {
+	tiers := testRarityTiers(t)
+	pool, err := LoadGachaPool(context.Background(), fakeGachaPoolRepository{}, tiers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sampler, err := NewPoolSampler(pool, tiers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const draws = 1000000
+	source := rand.New(rand.NewSource(42))
+	counts := make(map[int]int)
+	for i := 0; i < draws; i++ {
+		counts[sampler.Sample(source).ID]++
+	}
+
+	// 2 段階の抽選全体が、NewGachaPool が導出した ppm の重みに従う
+	observed := make([]int, 0, len(pool.Items))
+	weights := make([]int, 0, len(pool.Items))
+	for _, item := range pool.Items {
+		observed = append(observed, counts[item.ID])
+		weights = append(weights, item.Weight)
+	}
+
+	// 自由度 5、有意水準 0.1% の棄却限界値
+	const critical = 20.515
+	if statistic := chiSquared(observed, weights, draws); statistic > critical {
+		t.Errorf("chi-squared = %.3f exceeds %.3f; counts %v for weights %v", statistic, critical, observed, weights)
+	}
+}
@@ -0,0 +1,47 @@
+package gacha
+
+import (
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+// PityCounter tracks, per pity rule, how many spins in a row missed the
+// rule's rarity. It is not safe for concurrent use.
+type PityCounter struct {
+	tiers  *model.RarityRegistry
+	rules  []model.PityRule
+	misses []int
+}
+
+func NewPityCounter(tiers *model.RarityRegistry, rules []model.PityRule) *PityCounter {
+	return &PityCounter{
+		tiers:  tiers,
+		rules:  rules,
+		misses: make([]int, len(rules)),
+	}
+}
+
+// Guaranteed returns the highest ranked rarity whose rule triggers on the
+// next spin, or 0 when the next spin draws from the whole pool
+func (c *PityCounter) Guaranteed() model.Rarity {
+	guaranteed := model.Rarity(0)
+	for i, rule := range c.rules {
+		if c.misses[i]+1 < rule.AfterSpins {
+			continue
+		}
+		if guaranteed == 0 || c.tiers.Rank(rule.Rarity) > c.tiers.Rank(guaranteed) {
+			guaranteed = rule.Rarity
+		}
+	}
+	return guaranteed
+}
+
+// Record updates the counters with the item a spin produced
+func (c *PityCounter) Record(item model.GachaItem) {
+	for i, rule := range c.rules {
+		if c.tiers.AtLeast(item.Rarity, rule.Rarity) {
+			c.misses[i] = 0
+		} else {
+			c.misses[i]++
+		}
+	}
+}
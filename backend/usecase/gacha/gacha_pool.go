@@ -0,0 +1,22 @@
+package gacha
+
+import (
+	"context"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+// LoadGachaPool builds the pool from the stored rarity rates and items,
+// validating it against the tiers and deriving each item's weight
+func LoadGachaPool(ctx context.Context, repo repository.GachaPoolRepository, tiers *model.RarityRegistry) (*model.GachaPool, error) {
+	rates, err := repo.FindRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	items, err := repo.FindItems(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return model.NewGachaPool(tiers, rates, items)
+}
@@ -3,44 +3,128 @@ package gacha
 import (
 	"context"
 	"errors"
-	"math/rand"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/event"
 	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
 	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/abuse"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/audit"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/logging"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/point"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/spendlimit"
 )
 
 type GachaUsecase interface {
 	ExecuteGacha(ctx context.Context, userID int) (*model.GachaResult, error)
 	GetGachaHistory(ctx context.Context, userID int, limit int) ([]*model.GachaResult, error)
+	// GetStats compares configured rates with observed frequencies globally
+	// and, when userID is set, for that user
+	GetStats(ctx context.Context, userID int) (global *model.GachaStats, user *model.GachaStats, err error)
+	// PublishRates stores the loaded pool as a new rate table version if it
+	// changed and returns the version in effect
+	PublishRates(ctx context.Context) (*model.RateTable, error)
+	// GetRates returns a rate table by version, the one in effect at a time,
+	// or the current one when both are zero
+	GetRates(ctx context.Context, version int, at time.Time) (*model.RateTable, error)
+	ListRateTables(ctx context.Context) ([]*model.RateTable, error)
+	// GetPolicy returns the regional policy that applies to the user
+	GetPolicy(ctx context.Context, userID int) (*PolicyDecision, error)
 }
 
+// PolicyDecision is the policy for a user's region and, when the user may not
+// spin, why
+type PolicyDecision struct {
+	Policy      model.GachaPolicy
+	Restriction error
+}
+
+var (
+	ErrRateTableNotFound = errors.New("rate table not found")
+	// ErrRatesNotDisclosed blocks spins in regions that require published odds
+	// while the rate table cannot be published
+	ErrRatesNotDisclosed = errors.New("drop rates are not published")
+)
+
 type gachaUsecase struct {
-	gachaRepo repository.GachaRepository
-	pointRepo repository.PointRepository
-	userRepo  repository.UserRepository
+	gachaRepo     repository.GachaRepository
+	rateTableRepo repository.RateTableRepository
+	userRepo      repository.UserRepository
+	transactor    repository.Transactor
+	wallet        point.Wallet
+	guard         spendlimit.Guard
+	throttle      abuse.Throttle
+	policies      *model.GachaPolicies
+	tiers         *model.RarityRegistry
+	pool          *model.GachaPool
+	publisher     event.Publisher
+	recorder      audit.Recorder
+	source        Source
+
+	// 公開済みの確率表がある場合のみ true になる
+	ratesDisclosed atomic.Bool
+
+	// プールは起動時に読み込まれて変わらないため、抽選表は一度だけ構築する
+	samplerMu sync.Mutex
+	sampler   *PoolSampler
 }
 
 func NewGachaUsecase(
 	gachaRepo repository.GachaRepository,
-	pointRepo repository.PointRepository,
+	rateTableRepo repository.RateTableRepository,
 	userRepo repository.UserRepository,
+	transactor repository.Transactor,
+	wallet point.Wallet,
+	guard spendlimit.Guard,
+	throttle abuse.Throttle,
+	policies *model.GachaPolicies,
+	tiers *model.RarityRegistry,
+	pool *model.GachaPool,
+	publisher event.Publisher,
+	recorder audit.Recorder,
+	source Source,
 ) GachaUsecase {
 	return &gachaUsecase{
-		gachaRepo: gachaRepo,
-		pointRepo: pointRepo,
-		userRepo:  userRepo,
+		gachaRepo:     gachaRepo,
+		rateTableRepo: rateTableRepo,
+		userRepo:      userRepo,
+		transactor:    transactor,
+		wallet:        wallet,
+		guard:         guard,
+		throttle:      throttle,
+		policies:      policies,
+		tiers:         tiers,
+		pool:          pool,
+		publisher:     publisher,
+		recorder:      recorder,
+		source:        source,
 	}
 }
 
 func (uc *gachaUsecase) ExecuteGacha(ctx context.Context, userID int) (*model.GachaResult, error) {
+	logging.Operation(ctx, audit.ActionGachaExecute, userID)
 	// ユーザーの存在確認
 	user, err := uc.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, model.ErrUserNotFound
+	}
+
+	// 地域ごとのポリシーを確認
+	policy := uc.policies.For(user.VerifiedRegion())
+	if err := policy.CheckAccess(user, time.Now()); err != nil {
+		return nil, err
+	}
+	if policy.RequireRateDisclosure {
+		if err := uc.ensureRatesDisclosed(ctx); err != nil {
+			return nil, err
+		}
 	}
 
 	// ガチャアイテムの抽選
@@ -49,78 +133,179 @@ func (uc *gachaUsecase) ExecuteGacha(ctx context.Context, userID int) (*model.Ga
 		return nil, err
 	}
 
-	// ガチャ結果を保存
 	result := model.NewGachaResult(userID, item)
-	if err := uc.gachaRepo.SaveResult(ctx, result); err != nil {
-		return nil, err
-	}
-
-	// ポイント付与
-	userPoint, err := uc.pointRepo.GetUserPoint(ctx, userID)
+	transaction, err := model.NewPointTransaction(userID, item.Points, model.TransactionTypeGacha, "Gacha reward: "+item.Name)
 	if err != nil {
 		return nil, err
 	}
 
-	if userPoint == nil {
-		// 初回の場合は新規作成
-		userPoint, err = model.NewUserPoint(userID)
-		if err != nil {
-			return nil, err
+	// ガチャ結果の保存・ポイント付与・イベントの記録を同一トランザクションで行う
+	// ランキングや実績はコミット後にイベント経由で更新される
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// ユーザー自身が設定した上限を確認
+		if err := uc.guard.CheckSpin(ctx, userID, result.CreatedAt); err != nil {
+			return err
 		}
-		if err := userPoint.AddPoints(item.Points); err != nil {
-			return nil, err
+		// 不正の疑いで審査待ちのユーザーは連続して回せない
+		if err := uc.throttle.CheckSpin(ctx, userID, result.CreatedAt); err != nil {
+			return err
 		}
-		if err := uc.pointRepo.CreateUserPoint(ctx, userPoint); err != nil {
-			return nil, err
+		if err := uc.gachaRepo.SaveResult(ctx, result); err != nil {
+			return err
 		}
-	} else {
-		// 既存の場合は更新
-		if err := userPoint.AddPoints(item.Points); err != nil {
-			return nil, err
+		// 凍結・停止中のアカウントはポイントの付与時に弾かれ、結果の保存も取り消される
+		if err := uc.wallet.Credit(ctx, transaction); err != nil {
+			return err
 		}
-		if err := uc.pointRepo.UpdateUserPoint(ctx, userPoint); err != nil {
-			return nil, err
+		if err := uc.publisher.Publish(ctx, event.NewGachaExecuted(result), event.NewPointsChanged(transaction)); err != nil {
+			return err
 		}
+		return uc.recorder.Record(ctx, audit.Change{
+			Actor:      audit.UserActor(userID),
+			Action:     audit.ActionGachaExecute,
+			TargetType: "gacha_result",
+			TargetID:   strconv.Itoa(result.ID),
+			After:      result,
+		})
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// ポイント取引履歴を保存
-	transaction, err := model.NewPointTransaction(userID, item.Points, model.TransactionTypeGacha, "Gacha reward: "+item.Name)
+	return result, nil
+}
+
+func (uc *gachaUsecase) GetGachaHistory(ctx context.Context, userID int, limit int) ([]*model.GachaResult, error) {
+	logging.Operation(ctx, "gacha.history", userID)
+	return uc.gachaRepo.FindResultsByUserID(ctx, userID, limit)
+}
+
+func (uc *gachaUsecase) GetStats(ctx context.Context, userID int) (*model.GachaStats, *model.GachaStats, error) {
+	logging.Operation(ctx, "gacha.stats", userID)
+	items := uc.pool.Items
+
+	globalCounts, err := uc.gachaRepo.CountResultsByItem(ctx, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	global := model.BuildGachaStats(uc.tiers, items, globalCounts)
+
+	if userID == 0 {
+		return &global, nil, nil
+	}
+
+	// ユーザーの存在確認
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, model.ErrUserNotFound
+	}
+
+	userCounts, err := uc.gachaRepo.CountResultsByItem(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	userStats := model.BuildGachaStats(uc.tiers, items, userCounts)
+
+	return &global, &userStats, nil
+}
+
+func (uc *gachaUsecase) PublishRates(ctx context.Context) (*model.RateTable, error) {
+	// 現在は天井を設けていない
+	table, err := model.NewRateTable(uc.tiers, uc.pool, []model.PityRule{}, time.Now())
 	if err != nil {
 		return nil, err
 	}
-	if err := uc.pointRepo.SaveTransaction(ctx, transaction); err != nil {
+
+	published, err := uc.rateTableRepo.PublishIfChanged(ctx, table)
+	if err != nil {
 		return nil, err
 	}
 
-	return result, nil
+	uc.ratesDisclosed.Store(true)
+	return published, nil
 }
 
-func (uc *gachaUsecase) GetGachaHistory(ctx context.Context, userID int, limit int) ([]*model.GachaResult, error) {
-	return uc.gachaRepo.FindResultsByUserID(ctx, userID, limit)
+func (uc *gachaUsecase) GetRates(ctx context.Context, version int, at time.Time) (*model.RateTable, error) {
+	var table *model.RateTable
+	var err error
+	switch {
+	case version > 0:
+		table, err = uc.rateTableRepo.FindByVersion(ctx, version)
+	case !at.IsZero():
+		table, err = uc.rateTableRepo.FindEffectiveAt(ctx, at)
+	default:
+		// 設定変更後の最初の参照でも新しい版が公開されるようにする
+		return uc.PublishRates(ctx)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if table == nil {
+		return nil, ErrRateTableNotFound
+	}
+
+	return table, nil
+}
+
+func (uc *gachaUsecase) ListRateTables(ctx context.Context) ([]*model.RateTable, error) {
+	return uc.rateTableRepo.FindAll(ctx)
+}
+
+func (uc *gachaUsecase) GetPolicy(ctx context.Context, userID int) (*PolicyDecision, error) {
+	logging.Operation(ctx, "gacha.policy", userID)
+	// ユーザーの存在確認
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, model.ErrUserNotFound
+	}
+
+	policy := uc.policies.For(user.VerifiedRegion())
+	return &PolicyDecision{
+		Policy:      policy,
+		Restriction: policy.CheckAccess(user, time.Now()),
+	}, nil
+}
+
+// ensureRatesDisclosed publishes the loaded pool if that has not happened
+// yet. The pool is loaded once at startup, so one successful publish is enough.
+func (uc *gachaUsecase) ensureRatesDisclosed(ctx context.Context) error {
+	if uc.ratesDisclosed.Load() {
+		return nil
+	}
+	if _, err := uc.PublishRates(ctx); err != nil {
+		return fmt.Errorf("%w: %v", ErrRatesNotDisclosed, err)
+	}
+	return nil
 }
 
 func (uc *gachaUsecase) drawGachaItem() (model.GachaItem, error) {
-	items, err := model.GetGachaItems()
+	sampler, err := uc.currentSampler()
 	if err != nil {
 		return model.GachaItem{}, err
 	}
-	
-	// 確率に基づいてアイテムを抽選
-	r := rand.New(rand.NewSource(time.Now().UnixNano()))
-	roll := r.Float64()
-	
-	cumulative := 0.0
-	for _, item := range items {
-		cumulative += item.Probability
-		if roll < cumulative {
-			return item, nil
-		}
+
+	return sampler.Sample(uc.source), nil
+}
+
+// currentSampler returns the sampler for the loaded pool, building it on first use
+func (uc *gachaUsecase) currentSampler() (*PoolSampler, error) {
+	uc.samplerMu.Lock()
+	defer uc.samplerMu.Unlock()
+	if uc.sampler != nil {
+		return uc.sampler, nil
 	}
-	
-	// フォールバック（通常は到達しない）
-	if len(items) > 0 {
-		return items[0], nil
+
+	sampler, err := NewPoolSampler(uc.pool, uc.tiers)
+	if err != nil {
+		return nil, err
 	}
-	
-	return model.GachaItem{}, errors.New("no gacha items available")
-}
\ No newline at end of file
+
+	uc.sampler = sampler
+	return sampler, nil
+}
@@ -0,0 +1,115 @@
+package gacha
+
+import (
+	"errors"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+// PoolSampler draws from a pool in two stages: a rarity by its rate, then an
+// item of that rarity, where featured items share the rarity's featured
+// share equally and the other items share the rest equally. It is built
+// once per pool and is safe for concurrent use.
+type PoolSampler struct {
+	tiers []poolTier // lowest rank first
+	whole rarityTable
+	// fromRarity picks among the tiers ranked at or above a rarity
+	fromRarity map[model.Rarity]rarityTable
+}
+
+type poolTier struct {
+	rarity        model.Rarity
+	featuredShare int64
+	featured      []model.GachaItem
+	others        []model.GachaItem
+}
+
+// rarityTable picks one of the tiers from offset on
+type rarityTable struct {
+	table  *aliasTable
+	offset int
+}
+
+// NewPoolSampler builds the alias tables for a pool, ordering its rarities by
+// the tiers' ranks
+func NewPoolSampler(pool *model.GachaPool, rarities *model.RarityRegistry) (*PoolSampler, error) {
+	sampler := &PoolSampler{fromRarity: make(map[model.Rarity]rarityTable)}
+
+	// ティアはランクの昇順に並べ、上位レアリティだけの抽選表を作れるようにする
+	var weights []int
+	for _, rarityTier := range rarities.Tiers() {
+		for _, rate := range pool.Rates {
+			if rate.Rarity != rarityTier.Rarity || rate.Weight == 0 {
+				continue
+			}
+			tier := poolTier{rarity: rate.Rarity, featuredShare: int64(rate.FeaturedShare)}
+			for _, item := range model.ItemsOfRarity(pool.Items, rate.Rarity) {
+				if pool.IsFeatured(item) {
+					tier.featured = append(tier.featured, item)
+				} else {
+					tier.others = append(tier.others, item)
+				}
+			}
+			sampler.tiers = append(sampler.tiers, tier)
+			weights = append(weights, rate.Weight)
+		}
+	}
+	if len(sampler.tiers) == 0 {
+		return nil, errors.New("no gacha items available")
+	}
+
+	table, err := newAliasTable(weights)
+	if err != nil {
+		return nil, err
+	}
+	sampler.whole = rarityTable{table: table}
+
+	first := 0
+	for _, rarityTier := range rarities.Tiers() {
+		for first < len(sampler.tiers) && !rarities.AtLeast(sampler.tiers[first].rarity, rarityTier.Rarity) {
+			first++
+		}
+		if first == len(sampler.tiers) {
+			break
+		}
+		table, err := newAliasTable(weights[first:])
+		if err != nil {
+			return nil, err
+		}
+		sampler.fromRarity[rarityTier.Rarity] = rarityTable{table: table, offset: first}
+	}
+
+	return sampler, nil
+}
+
+// Sample draws an item from the whole pool
+func (s *PoolSampler) Sample(source Source) model.GachaItem {
+	return s.draw(source, s.whole)
+}
+
+// SampleFrom draws an item with only the rarities ranked at or above
+// minRarity, in proportion to their rates. This is how pity guarantees a
+// rarity. When the pool has no such rarity the whole pool is drawn from.
+func (s *PoolSampler) SampleFrom(source Source, minRarity model.Rarity) model.GachaItem {
+	table, ok := s.fromRarity[minRarity]
+	if !ok {
+		table = s.whole
+	}
+	return s.draw(source, table)
+}
+
+// HasRarityFrom reports whether the pool can draw a rarity ranked at or above minRarity
+func (s *PoolSampler) HasRarityFrom(minRarity model.Rarity) bool {
+	_, ok := s.fromRarity[minRarity]
+	return ok
+}
+
+func (s *PoolSampler) draw(source Source, rarities rarityTable) model.GachaItem {
+	tier := s.tiers[rarities.offset+rarities.table.sample(source)]
+
+	group := tier.others
+	if len(tier.featured) > 0 && source.Int63n(model.WeightScale) < tier.featuredShare {
+		group = tier.featured
+	}
+	return group[source.Int63n(int64(len(group)))]
+}
@@ -0,0 +1,200 @@
+package gacha
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+// testRarityTiers are the tiers seeded by the migrations
+func testRarityTiers(t *testing.T) *model.RarityRegistry {
+	t.Helper()
+	tiers, err := model.NewRarityRegistry([]model.RarityTier{
+		{Rarity: model.RarityCommon, Name: "Common", Rank: 1, Color: "#808080", MinPoints: 1, MaxPoints: 50},
+		{Rarity: model.RarityRare, Name: "Rare", Rank: 2, Color: "#0066ff", MinPoints: 10, MaxPoints: 200},
+		{Rarity: model.RarityEpic, Name: "Epic", Rank: 3, Color: "#8b00ff", MinPoints: 100, MaxPoints: 1000},
+		{Rarity: model.RarityLegendary, Name: "Legendary", Rank: 4, Color: "#ff6600", MinPoints: 500, MaxPoints: 5000},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tiers
+}
+
+// fakeGachaPoolRepository returns a stored pool with a rate-up Legendary
+type fakeGachaPoolRepository struct{}
+
+func (fakeGachaPoolRepository) FindRates(ctx context.Context) ([]model.RarityRate, error) {
+	return []model.RarityRate{
+		{Rarity: model.RarityCommon, Weight: 600000},
+		{Rarity: model.RarityRare, Weight: 300000},
+		{Rarity: model.RarityEpic, Weight: 80000},
+		{Rarity: model.RarityLegendary, Weight: 20000, FeaturedIDs: []int{5}, FeaturedShare: 500000},
+	}, nil
+}
+
+func (fakeGachaPoolRepository) FindItems(ctx context.Context) ([]model.GachaItem, error) {
+	return []model.GachaItem{
+		{ID: 1, Name: "Bronze Coin", Rarity: model.RarityCommon, Points: 10},
+		{ID: 2, Name: "Silver Coin", Rarity: model.RarityRare, Points: 50},
+		{ID: 3, Name: "Gold Coin", Rarity: model.RarityEpic, Points: 200},
+		{ID: 4, Name: "Diamond", Rarity: model.RarityLegendary, Points: 1000},
+		{ID: 5, Name: "Crown", Rarity: model.RarityLegendary, Points: 2000},
+		{ID: 6, Name: "Sceptre", Rarity: model.RarityLegendary, Points: 2000},
+	}, nil
+}
+
+// scriptedDraw is one expected Int63n call and the value it returns
+type scriptedDraw struct {
+	n     int64
+	value int64
+}
+
+// scriptedSource returns scripted values and fails the test when a draw asks
+// for a different range than the script expects
+type scriptedSource struct {
+	t     *testing.T
+	draws []scriptedDraw
+}
+
+func (s *scriptedSource) Int63n(n int64) int64 {
+	s.t.Helper()
+	if len(s.draws) == 0 {
+		s.t.Fatalf("unscripted Int63n(%d)", n)
+	}
+	draw := s.draws[0]
+	s.draws = s.draws[1:]
+	if n != draw.n {
+		s.t.Fatalf("Int63n(%d), script expects Int63n(%d)", n, draw.n)
+	}
+	return draw.value
+}
+
+func TestLoadGachaPoolDerivesWeights(t *testing.T) {
+	pool, err := LoadGachaPool(context.Background(), fakeGachaPoolRepository{}, testRarityTiers(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 注目アイテムがレジェンダリー 2% の半分、残りの 2 つが 0.5% ずつ
+	want := map[int]int{1: 600000, 2: 300000, 3: 80000, 4: 5000, 5: 10000, 6: 5000}
+	for _, item := range pool.Items {
+		if item.Weight != want[item.ID] {
+			t.Errorf("item %d weight = %d, want %d", item.ID, item.Weight, want[item.ID])
+		}
+	}
+}
+
+func TestPoolSamplerDrawsRarityThenItem(t *testing.T) {
+	tiers := testRarityTiers(t)
+	pool, err := LoadGachaPool(context.Background(), fakeGachaPoolRepository{}, tiers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sampler, err := NewPoolSampler(pool, tiers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 1 段目は 4 列 x 合計 1000000 の一様乱数で、商が列 (ランクの昇順)、余りが 0 なら列自身を選ぶ
+	// 2 段目は注目アイテムの割合の判定と、グループ内の一様な選択
+	const rarityRange = 4 * model.WeightScale
+	tests := []struct {
+		name  string
+		draws []scriptedDraw
+		want  int
+	}{
+		{
+			name:  "common",
+			draws: []scriptedDraw{{rarityRange, 0}, {1, 0}},
+			want:  1,
+		},
+		{
+			name:  "epic has no featured split",
+			draws: []scriptedDraw{{rarityRange, 2 * model.WeightScale}, {1, 0}},
+			want:  3,
+		},
+		{
+			name:  "legendary just inside the featured share",
+			draws: []scriptedDraw{{rarityRange, 3 * model.WeightScale}, {model.WeightScale, 499999}, {1, 0}},
+			want:  5,
+		},
+		{
+			name:  "legendary just outside the featured share",
+			draws: []scriptedDraw{{rarityRange, 3 * model.WeightScale}, {model.WeightScale, 500000}, {2, 0}},
+			want:  4,
+		},
+		{
+			name:  "second legendary outside the featured share",
+			draws: []scriptedDraw{{rarityRange, 3 * model.WeightScale}, {model.WeightScale, 999999}, {2, 1}},
+			want:  6,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := &scriptedSource{t: t, draws: tt.draws}
+			item := sampler.Sample(source)
+			if item.ID != tt.want {
+				t.Errorf("drew item %d, want %d", item.ID, tt.want)
+			}
+			if len(source.draws) != 0 {
+				t.Errorf("%d scripted draws unused", len(source.draws))
+			}
+		})
+	}
+}
+
+func TestPoolSamplerSampleFromDrawsOnlyEligibleRarities(t *testing.T) {
+	tiers := testRarityTiers(t)
+	pool, err := LoadGachaPool(context.Background(), fakeGachaPoolRepository{}, tiers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sampler, err := NewPoolSampler(pool, tiers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// エピック以上の 2 列だけの表で、合計は 80000 + 20000
+	const total = 100000
+	source := &scriptedSource{t: t, draws: []scriptedDraw{{2 * total, total}, {model.WeightScale, 0}, {1, 0}}}
+	if item := sampler.SampleFrom(source, model.RarityEpic); item.ID != 5 {
+		t.Errorf("drew item %d, want the featured Legendary", item.ID)
+	}
+}
+
+func TestPoolSamplerRanksTiersAddedAsData(t *testing.T) {
+	// Mythic はデータだけで追加され、ランクで Legendary の上に並ぶ
+	const mythic = model.Rarity(5)
+	tiers, err := model.NewRarityRegistry(append(testRarityTiers(t).Tiers(),
+		model.RarityTier{Rarity: mythic, Name: "Mythic", Rank: 5, Color: "#ff0033", MinPoints: 2000, MaxPoints: 10000}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool, err := model.NewGachaPool(tiers,
+		[]model.RarityRate{
+			{Rarity: model.RarityCommon, Weight: 990000},
+			{Rarity: mythic, Weight: 10000},
+		},
+		[]model.GachaItem{
+			{ID: 1, Name: "Bronze Coin", Rarity: model.RarityCommon, Points: 10},
+			{ID: 7, Name: "Phoenix Feather", Rarity: mythic, Points: 5000},
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sampler, err := NewPoolSampler(pool, tiers)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !sampler.HasRarityFrom(model.RarityLegendary) {
+		t.Fatal("Mythic is not drawn by a Legendary guarantee")
+	}
+	source := &scriptedSource{t: t, draws: []scriptedDraw{{10000, 0}, {1, 0}}}
+	if item := sampler.SampleFrom(source, model.RarityLegendary); item.ID != 7 {
+		t.Errorf("drew item %d with a Legendary guarantee, want the Mythic item", item.ID)
+	}
+}
@@ -0,0 +1,18 @@
+package gacha
+
+import (
+	"context"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+// LoadRarityTiers builds the rarity registry from the stored tiers. There are
+// no built-in defaults: without the stored built-in tiers loading fails.
+func LoadRarityTiers(ctx context.Context, repo repository.RarityTierRepository) (*model.RarityRegistry, error) {
+	tiers, err := repo.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return model.NewRarityRegistry(tiers)
+}
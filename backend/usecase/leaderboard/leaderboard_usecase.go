@@ -0,0 +1,100 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/event"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/audit"
+)
+
+const maxLeaderboardSize = 100
+
+type LeaderboardUsecase interface {
+	// GetLeaderboard returns the top users for the current window. When
+	// callerID is set, the caller's own rank is included even outside the top.
+	GetLeaderboard(ctx context.Context, metric model.LeaderboardMetric, period model.LeaderboardPeriod, limit int, callerID int) (*model.Leaderboard, error)
+	// Rebuild recomputes the materialized scores of the current windows from gacha_results
+	Rebuild(ctx context.Context) error
+	// RecordGachaResult applies a GachaExecuted event to the materialized scores
+	RecordGachaResult(ctx context.Context, e event.Event) error
+}
+
+type leaderboardUsecase struct {
+	leaderboardRepo repository.LeaderboardRepository
+	recorder        audit.Recorder
+}
+
+func NewLeaderboardUsecase(leaderboardRepo repository.LeaderboardRepository, recorder audit.Recorder) LeaderboardUsecase {
+	return &leaderboardUsecase{
+		leaderboardRepo: leaderboardRepo,
+		recorder:        recorder,
+	}
+}
+
+func (uc *leaderboardUsecase) GetLeaderboard(ctx context.Context, metric model.LeaderboardMetric, period model.LeaderboardPeriod, limit int, callerID int) (*model.Leaderboard, error) {
+	if err := model.ValidateLeaderboard(metric, period); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 || limit > maxLeaderboardSize {
+		limit = maxLeaderboardSize
+	}
+
+	start := period.Start(time.Now())
+	entries, err := uc.leaderboardRepo.FindTop(ctx, metric, period, start, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// 同点は同順位（1, 2, 2, 4 ...）
+	for i := range entries {
+		if i > 0 && entries[i].Score == entries[i-1].Score {
+			entries[i].Rank = entries[i-1].Rank
+		} else {
+			entries[i].Rank = i + 1
+		}
+	}
+
+	board := &model.Leaderboard{
+		Metric:      metric,
+		Period:      period,
+		PeriodStart: start,
+		Entries:     entries,
+	}
+
+	if callerID > 0 {
+		board.Caller, err = uc.leaderboardRepo.FindUserRank(ctx, metric, period, start, callerID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return board, nil
+}
+
+func (uc *leaderboardUsecase) Rebuild(ctx context.Context) error {
+	now := time.Now()
+	for _, metric := range []model.LeaderboardMetric{model.LeaderboardMetricGachaPoints, model.LeaderboardMetricLegendaryPulls} {
+		for _, period := range model.LeaderboardPeriods() {
+			if err := uc.leaderboardRepo.RebuildScores(ctx, metric, period, period.Start(now)); err != nil {
+				return err
+			}
+		}
+	}
+	return uc.recorder.Record(ctx, audit.Change{
+		Action:     audit.ActionLeaderboardsRebuild,
+		TargetType: "leaderboards",
+	})
+}
+
+func (uc *leaderboardUsecase) RecordGachaResult(ctx context.Context, e event.Event) error {
+	executed, ok := e.(event.GachaExecuted)
+	if !ok {
+		return fmt.Errorf("leaderboard cannot handle %s", e.EventName())
+	}
+	return uc.leaderboardRepo.ApplyDeltas(ctx, model.LeaderboardDeltas(executed.Result()))
+}
@@ -0,0 +1,92 @@
+// Package logging carries a structured logger through the context so that
+// every layer logs with the request ID and the attributes added while the
+// request is handled.
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Common attribute keys
+const (
+	KeyRequestID = "request_id"
+	KeyUserID    = "user_id"
+	KeyOperation = "operation"
+	KeyError     = "error"
+)
+
+type scopeKey struct{}
+
+// scope is shared by everything handling one request or job, so attributes
+// added deep in a use case also reach the access log written by the caller.
+type scope struct {
+	logger *slog.Logger
+	mu     sync.Mutex
+	attrs  []slog.Attr
+}
+
+// NewContext starts a scope logging to logger. The scope replaces any
+// scope already in ctx.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, scopeKey{}, &scope{logger: logger})
+}
+
+// AddAttrs adds key-value pairs to every later log line of the scope,
+// replacing values already set for the same keys. Without a scope it does
+// nothing.
+func AddAttrs(ctx context.Context, args ...interface{}) {
+	addAttrs(ctx, true, args)
+}
+
+// Operation names what the request is doing and for which user. The first
+// call wins, so use cases called by other use cases keep the caller's name.
+func Operation(ctx context.Context, operation string, userID int) {
+	addAttrs(ctx, false, []interface{}{KeyOperation, operation, KeyUserID, userID})
+}
+
+func addAttrs(ctx context.Context, replace bool, args []interface{}) {
+	s, ok := ctx.Value(scopeKey{}).(*scope)
+	if !ok {
+		return
+	}
+
+	record := slog.Record{}
+	record.Add(args...)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record.Attrs(func(attr slog.Attr) bool {
+		for i := range s.attrs {
+			if s.attrs[i].Key == attr.Key {
+				if replace {
+					s.attrs[i] = attr
+				}
+				return true
+			}
+		}
+		s.attrs = append(s.attrs, attr)
+		return true
+	})
+}
+
+// FromContext returns the scope's logger with its attributes, or the
+// default logger outside a scope.
+func FromContext(ctx context.Context) *slog.Logger {
+	s, ok := ctx.Value(scopeKey{}).(*scope)
+	if !ok {
+		return slog.Default()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.attrs) == 0 {
+		return s.logger
+	}
+	args := make([]interface{}, len(s.attrs))
+	for i, attr := range s.attrs {
+		args[i] = attr
+	}
+	return s.logger.With(args...)
+}
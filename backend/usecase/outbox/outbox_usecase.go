@@ -0,0 +1,105 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/event"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/logging"
+)
+
+const (
+	relayBatchSize = 100
+	// relayLease keeps a claimed event from other relays while it is delivered
+	relayLease        = time.Minute
+	recentFailureSize = 20
+)
+
+type OutboxUsecase interface {
+	// RelayPending delivers due events to the dispatcher and returns how many were delivered.
+	// Delivery is at-least-once: a failed event is retried with backoff.
+	RelayPending(ctx context.Context) (int, error)
+	GetStats(ctx context.Context) (*model.OutboxStats, error)
+	// Once wraps a subscriber that is not idempotent so it handles each
+	// stored event only once, even when the event is redelivered
+	Once(consumer string, handler event.Handler) event.Handler
+}
+
+type outboxUsecase struct {
+	outboxRepo repository.OutboxRepository
+	transactor repository.Transactor
+	dispatcher event.Publisher
+}
+
+func NewOutboxUsecase(
+	outboxRepo repository.OutboxRepository,
+	transactor repository.Transactor,
+	dispatcher event.Publisher,
+) OutboxUsecase {
+	return &outboxUsecase{
+		outboxRepo: outboxRepo,
+		transactor: transactor,
+		dispatcher: dispatcher,
+	}
+}
+
+func (uc *outboxUsecase) RelayPending(ctx context.Context) (int, error) {
+	delivered := 0
+	for {
+		events, err := uc.outboxRepo.ClaimDue(ctx, time.Now(), relayLease, relayBatchSize)
+		if err != nil {
+			return delivered, err
+		}
+
+		for _, stored := range events {
+			// 購読者が追加する属性がイベント間で混ざらないよう、イベントごとにスコープを分ける
+			eventCtx := logging.NewContext(ctx, logging.FromContext(ctx).With("event", stored.Name, "event_id", stored.ID))
+			if err := uc.deliver(eventCtx, stored); err != nil {
+				stored.MarkAttemptFailed(time.Now(), err)
+				logging.FromContext(eventCtx).Warn("Event delivery failed", "attempts", stored.Attempts, "status", stored.Status, logging.KeyError, err)
+			} else {
+				stored.MarkDelivered(time.Now())
+				delivered++
+			}
+			if err := uc.outboxRepo.UpdateDelivery(ctx, stored); err != nil {
+				return delivered, err
+			}
+		}
+
+		if len(events) < relayBatchSize {
+			return delivered, nil
+		}
+	}
+}
+
+func (uc *outboxUsecase) deliver(ctx context.Context, stored *model.OutboxEvent) error {
+	e, err := event.Decode(stored.Name, stored.Payload)
+	if err != nil {
+		return err
+	}
+	return uc.dispatcher.Publish(event.WithID(ctx, stored.ID), e)
+}
+
+func (uc *outboxUsecase) GetStats(ctx context.Context) (*model.OutboxStats, error) {
+	return uc.outboxRepo.GetStats(ctx, recentFailureSize)
+}
+
+func (uc *outboxUsecase) Once(consumer string, handler event.Handler) event.Handler {
+	return func(ctx context.Context, e event.Event) error {
+		id, ok := event.IDFromContext(ctx)
+		if !ok {
+			return handler(ctx, e)
+		}
+
+		// 処理済みの記録とハンドラの更新を同一トランザクションで行う
+		return uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			first, err := uc.outboxRepo.MarkConsumed(ctx, id, consumer)
+			if err != nil || !first {
+				return err
+			}
+			return handler(ctx, e)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/event"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+type publisher struct {
+	outboxRepo repository.OutboxRepository
+}
+
+// NewPublisher returns an event.Publisher that stores events in the outbox.
+// Called inside a transaction, the events commit or roll back with it; the
+// relay delivers them to subscribers afterwards.
+func NewPublisher(outboxRepo repository.OutboxRepository) event.Publisher {
+	return &publisher{
+		outboxRepo: outboxRepo,
+	}
+}
+
+func (p *publisher) Publish(ctx context.Context, events ...event.Event) error {
+	stored := make([]*model.OutboxEvent, 0, len(events))
+	for _, e := range events {
+		payload, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		outboxEvent, err := model.NewOutboxEvent(e.EventName(), payload)
+		if err != nil {
+			return err
+		}
+		stored = append(stored, outboxEvent)
+	}
+	return p.outboxRepo.Append(ctx, stored...)
+}
@@ -0,0 +1,102 @@
+package point
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/event"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/audit"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/logging"
+)
+
+// AdjustPointsInput describes a manual balance change requested by an admin
+type AdjustPointsInput struct {
+	UserID        int
+	Amount        int // signed
+	ReasonCode    model.AdjustmentReasonCode
+	Reason        string
+	AdminID       string
+	OverrideLimit bool
+}
+
+type AdjustmentUsecase interface {
+	AdjustPoints(ctx context.Context, input AdjustPointsInput) (*model.PointAdjustment, error)
+	ListAdjustments(ctx context.Context, userID int, limit int) ([]*model.PointAdjustment, error)
+}
+
+type adjustmentUsecase struct {
+	adjustmentRepo repository.PointAdjustmentRepository
+	userRepo       repository.UserRepository
+	transactor     repository.Transactor
+	wallet         Wallet
+	publisher      event.Publisher
+	recorder       audit.Recorder
+}
+
+func NewAdjustmentUsecase(
+	adjustmentRepo repository.PointAdjustmentRepository,
+	userRepo repository.UserRepository,
+	transactor repository.Transactor,
+	wallet Wallet,
+	publisher event.Publisher,
+	recorder audit.Recorder,
+) AdjustmentUsecase {
+	return &adjustmentUsecase{
+		adjustmentRepo: adjustmentRepo,
+		userRepo:       userRepo,
+		transactor:     transactor,
+		wallet:         wallet,
+		publisher:      publisher,
+		recorder:       recorder,
+	}
+}
+
+func (uc *adjustmentUsecase) AdjustPoints(ctx context.Context, input AdjustPointsInput) (*model.PointAdjustment, error) {
+	logging.Operation(ctx, audit.ActionPointsAdjust, input.UserID)
+	adjustment, err := model.NewPointAdjustment(input.UserID, input.Amount, input.ReasonCode, input.Reason, input.AdminID, input.OverrideLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	// ユーザーの存在確認
+	user, err := uc.userRepo.FindByID(ctx, input.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, model.ErrUserNotFound
+	}
+
+	// 取引と調整記録を同一トランザクションで保存
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		transaction := adjustment.Transaction()
+		if err := uc.wallet.Adjust(ctx, transaction, adjustment.LimitOverride); err != nil {
+			return err
+		}
+		adjustment.TransactionID = transaction.ID
+		if err := uc.adjustmentRepo.Save(ctx, adjustment); err != nil {
+			return err
+		}
+		if err := uc.publisher.Publish(ctx, event.NewPointsChanged(transaction)); err != nil {
+			return err
+		}
+		return uc.recorder.Record(ctx, audit.Change{
+			Actor:      audit.AdminActor(adjustment.AdminID),
+			Action:     audit.ActionPointsAdjust,
+			TargetType: "point_adjustment",
+			TargetID:   strconv.Itoa(adjustment.ID),
+			After:      adjustment,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return adjustment, nil
+}
+
+func (uc *adjustmentUsecase) ListAdjustments(ctx context.Context, userID int, limit int) ([]*model.PointAdjustment, error) {
+	return uc.adjustmentRepo.FindAdjustments(ctx, userID, limit)
+}
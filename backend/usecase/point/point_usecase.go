@@ -2,40 +2,69 @@ package point
 
 import (
 	"context"
-	"errors"
+	"time"
 
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/event"
 	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
 	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/audit"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/logging"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/spendlimit"
 )
 
 type PointUsecase interface {
 	GetBalance(ctx context.Context, userID int) (int, error)
 	GetTransactionHistory(ctx context.Context, userID int, limit int) ([]*model.PointTransaction, error)
+	// TransferPoints moves points from the sender, who proves the request is
+	// theirs with the token issued to them, to another user
+	TransferPoints(ctx context.Context, fromUserID int, token string, toUserID, amount int, description string) (*model.PointTransfer, error)
+	GetUpcomingExpirations(ctx context.Context, userID int, within time.Duration) ([]model.PointExpiration, error)
+	// ExpirePoints sweeps every lot that expired before now and returns how many users were affected
+	ExpirePoints(ctx context.Context, now time.Time) (int, error)
 }
 
 type pointUsecase struct {
-	pointRepo repository.PointRepository
-	userRepo  repository.UserRepository
+	pointRepo  repository.PointRepository
+	lotRepo    repository.PointLotRepository
+	userRepo   repository.UserRepository
+	transactor repository.Transactor
+	wallet     Wallet
+	guard      spendlimit.Guard
+	publisher  event.Publisher
+	recorder   audit.Recorder
 }
 
 func NewPointUsecase(
 	pointRepo repository.PointRepository,
+	lotRepo repository.PointLotRepository,
 	userRepo repository.UserRepository,
+	transactor repository.Transactor,
+	wallet Wallet,
+	guard spendlimit.Guard,
+	publisher event.Publisher,
+	recorder audit.Recorder,
 ) PointUsecase {
 	return &pointUsecase{
-		pointRepo: pointRepo,
-		userRepo:  userRepo,
+		pointRepo:  pointRepo,
+		lotRepo:    lotRepo,
+		userRepo:   userRepo,
+		transactor: transactor,
+		wallet:     wallet,
+		guard:      guard,
+		publisher:  publisher,
+		recorder:   recorder,
 	}
 }
 
 func (uc *pointUsecase) GetBalance(ctx context.Context, userID int) (int, error) {
+	logging.Operation(ctx, "points.balance", userID)
 	// ユーザーの存在確認
 	user, err := uc.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return 0, err
 	}
 	if user == nil {
-		return 0, errors.New("user not found")
+		return 0, model.ErrUserNotFound
 	}
 
 	// ポイント残高を取得
@@ -53,14 +82,141 @@ func (uc *pointUsecase) GetBalance(ctx context.Context, userID int) (int, error)
 }
 
 func (uc *pointUsecase) GetTransactionHistory(ctx context.Context, userID int, limit int) ([]*model.PointTransaction, error) {
+	logging.Operation(ctx, "points.history", userID)
 	// ユーザーの存在確認
 	user, err := uc.userRepo.FindByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 	if user == nil {
-		return nil, errors.New("user not found")
+		return nil, model.ErrUserNotFound
 	}
 
 	return uc.pointRepo.FindTransactionsByUserID(ctx, userID, limit)
+}
+
+func (uc *pointUsecase) TransferPoints(ctx context.Context, fromUserID int, token string, toUserID, amount int, description string) (*model.PointTransfer, error) {
+	logging.Operation(ctx, audit.ActionPointsTransfer, fromUserID)
+	logging.AddAttrs(ctx, "to_user_id", toUserID)
+	transfer, err := model.NewPointTransfer(fromUserID, toUserID, amount, description)
+	if err != nil {
+		return nil, err
+	}
+
+	// 送信者・受信者の存在確認
+	// 凍結・停止中のアカウントとはどちらの向きにも送付できず、Wallet がトランザクション内で確認する
+	for _, userID := range []int{fromUserID, toUserID} {
+		user, err := uc.userRepo.FindByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			return nil, model.ErrUserNotFound
+		}
+		// 送信者本人によるリクエストであることを確認
+		if userID == fromUserID {
+			if err := user.Authenticate(token); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// ユーザー自身が設定した上限を確認する
+		// 上限の行ロックはガチャと同じくポイント残高の行ロックより先に取得する
+		if err := uc.guard.CheckSpend(ctx, fromUserID, amount, transfer.CreatedAt); err != nil {
+			return err
+		}
+
+		// デッドロックを避けるため、ユーザーID順に行ロックを取得する
+		firstID, secondID := fromUserID, toUserID
+		if firstID > secondID {
+			firstID, secondID = secondID, firstID
+		}
+		for _, userID := range []int{firstID, secondID} {
+			if _, err := uc.pointRepo.GetUserPointForUpdate(ctx, userID); err != nil {
+				return err
+			}
+		}
+
+		// 1日あたりの送付上限を確認
+		sentToday, err := uc.pointRepo.SumTransactionsSince(ctx, fromUserID, model.TransactionTypeTransferOut, model.StartOfDay(transfer.CreatedAt))
+		if err != nil {
+			return err
+		}
+		// transfer_out の金額は負数で記録されている
+		if -sentToday+amount > model.MaxDailyTransferAmount {
+			return model.ErrDailyTransferLimit
+		}
+
+		// 送付・受取の取引履歴を同一の送付IDで保存
+		out, in, err := transfer.Transactions()
+		if err != nil {
+			return err
+		}
+
+		// 受取側のポイントは送付元の有効期限を引き継ぐ
+		consumed, err := uc.wallet.Debit(ctx, out)
+		if err != nil {
+			return err
+		}
+		if err := uc.wallet.Credit(ctx, in, consumed...); err != nil {
+			return err
+		}
+		if err := uc.publisher.Publish(ctx, event.NewPointsChanged(out), event.NewPointsChanged(in)); err != nil {
+			return err
+		}
+		return uc.recorder.Record(ctx, audit.Change{
+			Actor:      audit.UserActor(fromUserID),
+			Action:     audit.ActionPointsTransfer,
+			TargetType: "point_transfer",
+			TargetID:   transfer.ID,
+			After:      transfer,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return transfer, nil
+}
+
+func (uc *pointUsecase) GetUpcomingExpirations(ctx context.Context, userID int, within time.Duration) ([]model.PointExpiration, error) {
+	logging.Operation(ctx, "points.expirations", userID)
+	now := time.Now()
+	return uc.lotRepo.FindUpcomingExpirations(ctx, userID, now, now.Add(within))
+}
+
+func (uc *pointUsecase) ExpirePoints(ctx context.Context, now time.Time) (int, error) {
+	const batchSize = 100
+
+	affected := 0
+	for {
+		userIDs, err := uc.lotRepo.FindUserIDsWithExpiredLots(ctx, now, batchSize)
+		if err != nil {
+			return affected, err
+		}
+		if len(userIDs) == 0 {
+			return affected, nil
+		}
+
+		// ユーザーごとにトランザクションを分け、ロック時間を短く保つ
+		for _, userID := range userIDs {
+			err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+				tx, err := uc.wallet.Expire(ctx, userID, now)
+				if err != nil || tx == nil {
+					return err
+				}
+				return uc.publisher.Publish(ctx, event.NewPointsChanged(tx))
+			})
+			if err != nil {
+				return affected, err
+			}
+			affected++
+		}
+
+		if len(userIDs) < batchSize {
+			return affected, nil
+		}
+	}
 }
\ No newline at end of file
@@ -0,0 +1,292 @@
+package point
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+// Wallet applies point movements to a user's balance, ledger and lots.
+// Every point credit or debit in the application goes through it so the
+// three stay consistent, and so no path can move points for a suspended
+// account (see TransactionType.RequiresActiveAccount).
+type Wallet interface {
+	// Credit adds tx.Amount to the balance. Without expiries the points form
+	// a new lot with the default lifetime; otherwise one lot per expiry is
+	// created, keeping the original expiry dates (used for transfers).
+	Credit(ctx context.Context, tx *model.PointTransaction, expiries ...model.PointExpiration) error
+	// Debit removes tx.Magnitude() from the balance, consuming the earliest
+	// expiring lots first, and returns what was consumed.
+	Debit(ctx context.Context, tx *model.PointTransaction) ([]model.PointExpiration, error)
+	// Adjust applies a signed admin_adjustment. Credits form a new lot and
+	// debits consume lots like Debit; MaxTransactionAmount applies unless overrideLimit is set.
+	Adjust(ctx context.Context, tx *model.PointTransaction, overrideLimit bool) error
+	// Expire removes every lot of the user that expired before now
+	Expire(ctx context.Context, userID int, now time.Time) (*model.PointTransaction, error)
+}
+
+type wallet struct {
+	pointRepo   repository.PointRepository
+	lotRepo     repository.PointLotRepository
+	userRepo    repository.UserRepository
+	transactor  repository.Transactor
+	lotLifetime time.Duration
+}
+
+func NewWallet(
+	pointRepo repository.PointRepository,
+	lotRepo repository.PointLotRepository,
+	userRepo repository.UserRepository,
+	transactor repository.Transactor,
+	expiryDays int,
+) Wallet {
+	if expiryDays <= 0 {
+		expiryDays = model.DefaultPointExpiryDays
+	}
+	return &wallet{
+		pointRepo:   pointRepo,
+		lotRepo:     lotRepo,
+		userRepo:    userRepo,
+		transactor:  transactor,
+		lotLifetime: time.Duration(expiryDays) * 24 * time.Hour,
+	}
+}
+
+func (w *wallet) Credit(ctx context.Context, tx *model.PointTransaction, expiries ...model.PointExpiration) error {
+	return w.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		userPoint, err := w.pointRepo.GetUserPointForUpdate(ctx, tx.UserID)
+		if err != nil {
+			return err
+		}
+		if err := w.checkActive(ctx, tx); err != nil {
+			return err
+		}
+
+		if userPoint == nil {
+			// 初回の場合は新規作成
+			userPoint, err = model.NewUserPoint(tx.UserID)
+			if err != nil {
+				return err
+			}
+			if err := userPoint.AddPoints(tx.Amount); err != nil {
+				return err
+			}
+			if err := w.pointRepo.CreateUserPoint(ctx, userPoint); err != nil {
+				return err
+			}
+		} else {
+			if err := userPoint.AddPoints(tx.Amount); err != nil {
+				return err
+			}
+			if err := w.pointRepo.UpdateUserPoint(ctx, userPoint); err != nil {
+				return err
+			}
+		}
+
+		tx.BalanceAfter = userPoint.Balance
+		if err := w.pointRepo.SaveTransaction(ctx, tx); err != nil {
+			return err
+		}
+
+		if len(expiries) == 0 {
+			expiries = []model.PointExpiration{{Amount: tx.Amount, ExpiresAt: tx.CreatedAt.Add(w.lotLifetime)}}
+		}
+		for _, expiry := range expiries {
+			lot, err := model.NewPointLot(tx.UserID, tx.ID, expiry.Amount, tx.CreatedAt, expiry.ExpiresAt)
+			if err != nil {
+				return err
+			}
+			if err := w.lotRepo.CreateLot(ctx, lot); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (w *wallet) Debit(ctx context.Context, tx *model.PointTransaction) ([]model.PointExpiration, error) {
+	var consumed []model.PointExpiration
+	err := w.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		userPoint, err := w.pointRepo.GetUserPointForUpdate(ctx, tx.UserID)
+		if err != nil {
+			return err
+		}
+		if err := w.checkActive(ctx, tx); err != nil {
+			return err
+		}
+		if userPoint == nil {
+			return errors.New("insufficient points")
+		}
+
+		if err := userPoint.SpendPoints(tx.Magnitude()); err != nil {
+			return err
+		}
+
+		lots, err := w.lotRepo.FindActiveLotsForUpdate(ctx, tx.UserID)
+		if err != nil {
+			return err
+		}
+		lots = unexpiredLots(lots, tx.CreatedAt)
+
+		consumed, err = model.ConsumeLots(lots, tx.Magnitude())
+		if err != nil {
+			return err
+		}
+		for _, lot := range lots {
+			if err := w.lotRepo.UpdateLot(ctx, lot); err != nil {
+				return err
+			}
+		}
+
+		if err := w.pointRepo.UpdateUserPoint(ctx, userPoint); err != nil {
+			return err
+		}
+		tx.BalanceAfter = userPoint.Balance
+		return w.pointRepo.SaveTransaction(ctx, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return consumed, nil
+}
+
+func (w *wallet) Adjust(ctx context.Context, tx *model.PointTransaction, overrideLimit bool) error {
+	return w.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		userPoint, err := w.pointRepo.GetUserPointForUpdate(ctx, tx.UserID)
+		if err != nil {
+			return err
+		}
+
+		isNew := userPoint == nil
+		if isNew {
+			userPoint, err = model.NewUserPoint(tx.UserID)
+			if err != nil {
+				return err
+			}
+		}
+		if err := userPoint.Adjust(tx.Amount, overrideLimit); err != nil {
+			return err
+		}
+		if isNew {
+			err = w.pointRepo.CreateUserPoint(ctx, userPoint)
+		} else {
+			err = w.pointRepo.UpdateUserPoint(ctx, userPoint)
+		}
+		if err != nil {
+			return err
+		}
+
+		tx.BalanceAfter = userPoint.Balance
+		if err := w.pointRepo.SaveTransaction(ctx, tx); err != nil {
+			return err
+		}
+
+		if tx.Amount > 0 {
+			lot, err := model.NewPointLot(tx.UserID, tx.ID, tx.Amount, tx.CreatedAt, tx.CreatedAt.Add(w.lotLifetime))
+			if err != nil {
+				return err
+			}
+			return w.lotRepo.CreateLot(ctx, lot)
+		}
+
+		lots, err := w.lotRepo.FindActiveLotsForUpdate(ctx, tx.UserID)
+		if err != nil {
+			return err
+		}
+		lots = unexpiredLots(lots, tx.CreatedAt)
+		if _, err := model.ConsumeLots(lots, tx.Magnitude()); err != nil {
+			return err
+		}
+		for _, lot := range lots {
+			if err := w.lotRepo.UpdateLot(ctx, lot); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (w *wallet) Expire(ctx context.Context, userID int, now time.Time) (*model.PointTransaction, error) {
+	var tx *model.PointTransaction
+	err := w.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		userPoint, err := w.pointRepo.GetUserPointForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		lots, err := w.lotRepo.FindActiveLotsForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		expired := 0
+		for _, lot := range lots {
+			if !lot.IsExpired(now) {
+				continue
+			}
+			expired += lot.Remaining
+			lot.Remaining = 0
+			if err := w.lotRepo.UpdateLot(ctx, lot); err != nil {
+				return err
+			}
+		}
+
+		if userPoint == nil || expired == 0 {
+			return nil
+		}
+
+		removed := userPoint.ExpirePoints(expired)
+		if removed == 0 {
+			return nil
+		}
+		if err := w.pointRepo.UpdateUserPoint(ctx, userPoint); err != nil {
+			return err
+		}
+
+		tx, err = model.NewExpireTransaction(userID, removed)
+		if err != nil {
+			return err
+		}
+		tx.BalanceAfter = userPoint.Balance
+		return w.pointRepo.SaveTransaction(ctx, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+// checkActive rejects tx when its type is kept from suspended accounts and
+// the user is frozen or banned at tx.CreatedAt. The user row is locked after
+// the balance row, the order every wallet caller uses, so a concurrent status
+// change either commits first and is seen here or waits for this transaction.
+func (w *wallet) checkActive(ctx context.Context, tx *model.PointTransaction) error {
+	if !tx.Type.RequiresActiveAccount() {
+		return nil
+	}
+	user, err := w.userRepo.FindByIDForUpdate(ctx, tx.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return model.ErrUserNotFound
+	}
+	return user.CheckActive(tx.CreatedAt)
+}
+
+// unexpiredLots filters out lots that expired but have not been swept yet
+func unexpiredLots(lots []*model.PointLot, now time.Time) []*model.PointLot {
+	active := lots[:0]
+	for _, lot := range lots {
+		if !lot.IsExpired(now) {
+			active = append(active, lot)
+		}
+	}
+	return active
+}
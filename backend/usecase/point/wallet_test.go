@@ -0,0 +1,140 @@
+package point
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+// fakePointRepository keeps one user's balance and transactions in memory;
+// methods the wallet does not call are left to the embedded nil interface
+type fakePointRepository struct {
+	repository.PointRepository
+	userPoint    *model.UserPoint
+	transactions []*model.PointTransaction
+}
+
+func (r *fakePointRepository) GetUserPointForUpdate(ctx context.Context, userID int) (*model.UserPoint, error) {
+	return r.userPoint, nil
+}
+
+func (r *fakePointRepository) CreateUserPoint(ctx context.Context, userPoint *model.UserPoint) error {
+	r.userPoint = userPoint
+	return nil
+}
+
+func (r *fakePointRepository) UpdateUserPoint(ctx context.Context, userPoint *model.UserPoint) error {
+	r.userPoint = userPoint
+	return nil
+}
+
+func (r *fakePointRepository) SaveTransaction(ctx context.Context, transaction *model.PointTransaction) error {
+	r.transactions = append(r.transactions, transaction)
+	return nil
+}
+
+type fakePointLotRepository struct {
+	repository.PointLotRepository
+	lots []*model.PointLot
+}
+
+func (r *fakePointLotRepository) CreateLot(ctx context.Context, lot *model.PointLot) error {
+	r.lots = append(r.lots, lot)
+	return nil
+}
+
+func (r *fakePointLotRepository) UpdateLot(ctx context.Context, lot *model.PointLot) error {
+	return nil
+}
+
+func (r *fakePointLotRepository) FindActiveLotsForUpdate(ctx context.Context, userID int) ([]*model.PointLot, error) {
+	return r.lots, nil
+}
+
+type fakeUserRepository struct {
+	repository.UserRepository
+	user *model.User
+}
+
+func (r *fakeUserRepository) FindByIDForUpdate(ctx context.Context, id int) (*model.User, error) {
+	return r.user, nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestWalletKeepsSuspendedAccountsOut(t *testing.T) {
+	now := time.Now()
+	expired := now.Add(-time.Hour)
+	tests := []struct {
+		name      string
+		status    model.UserStatus
+		until     *time.Time
+		txType    model.TransactionType
+		wantError bool
+	}{
+		{name: "frozen user spins", status: model.UserStatusFrozen, txType: model.TransactionTypeGacha, wantError: true},
+		{name: "banned user receives a transfer", status: model.UserStatusBanned, txType: model.TransactionTypeTransferIn, wantError: true},
+		{name: "frozen user is rewarded an achievement", status: model.UserStatusFrozen, txType: model.TransactionTypeAchievement},
+		{name: "suspension past its expiry", status: model.UserStatusFrozen, until: &expired, txType: model.TransactionTypeGacha},
+		{name: "active user spins", status: model.UserStatusActive, txType: model.TransactionTypeGacha},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pointRepo := &fakePointRepository{}
+			user := &model.User{ID: 1, Status: tt.status, StatusReason: "chargeback", StatusUntil: tt.until}
+			w := NewWallet(pointRepo, &fakePointLotRepository{}, &fakeUserRepository{user: user}, fakeTransactor{}, 0)
+
+			tx, err := model.NewPointTransaction(1, 100, tt.txType, "test")
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = w.Credit(context.Background(), tx)
+			if tt.wantError {
+				if !errors.Is(err, model.ErrAccountSuspended) {
+					t.Fatalf("Credit = %v, want ErrAccountSuspended", err)
+				}
+				if len(pointRepo.transactions) != 0 {
+					t.Errorf("%d transactions saved for a suspended account", len(pointRepo.transactions))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Credit = %v", err)
+			}
+		})
+	}
+}
+
+func TestWalletDebitChecksTheSender(t *testing.T) {
+	pointRepo := &fakePointRepository{}
+	lotRepo := &fakePointLotRepository{}
+	userRepo := &fakeUserRepository{user: &model.User{ID: 1, Status: model.UserStatusActive}}
+	w := NewWallet(pointRepo, lotRepo, userRepo, fakeTransactor{}, 0)
+
+	reward, err := model.NewPointTransaction(1, 500, model.TransactionTypeGacha, "reward")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Credit(context.Background(), reward); err != nil {
+		t.Fatal(err)
+	}
+
+	// 付与の後に凍結された場合も、送付はトランザクション内で弾かれる
+	userRepo.user.Status = model.UserStatusFrozen
+	out, err := model.NewPointTransaction(1, 200, model.TransactionTypeTransferOut, "transfer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Debit(context.Background(), out); !errors.Is(err, model.ErrAccountSuspended) {
+		t.Errorf("Debit = %v, want ErrAccountSuspended", err)
+	}
+}
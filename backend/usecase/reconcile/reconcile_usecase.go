@@ -0,0 +1,136 @@
+package reconcile
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+type ReconcileUsecase interface {
+	// Reconcile derives every user's balance from the double-entry ledger and
+	// reports users whose user_points.balance disagrees, along with any breach
+	// of the zero-sum invariant. With repair set, an adjustment transaction is
+	// written so the ledger explains the stored balance.
+	Reconcile(ctx context.Context, repair bool) (*model.ReconciliationReport, error)
+}
+
+type reconcileUsecase struct {
+	pointRepo  repository.PointRepository
+	transactor repository.Transactor
+}
+
+func NewReconcileUsecase(
+	pointRepo repository.PointRepository,
+	transactor repository.Transactor,
+) ReconcileUsecase {
+	return &reconcileUsecase{
+		pointRepo:  pointRepo,
+		transactor: transactor,
+	}
+}
+
+func (uc *reconcileUsecase) Reconcile(ctx context.Context, repair bool) (*model.ReconciliationReport, error) {
+	report := &model.ReconciliationReport{StartedAt: time.Now()}
+
+	userPoints, err := uc.pointRepo.FindAllUserPoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ledger, err := uc.pointRepo.FindUserLedgerBalances(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// 複式簿記の不変条件（全仕訳の合計がゼロ）を確認
+	report.LedgerTotal, err = uc.pointRepo.SumLedger(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.UnbalancedTransactions, err = uc.pointRepo.FindUnbalancedTransactionIDs(ctx, 100)
+	if err != nil {
+		return nil, err
+	}
+
+	recorded := make(map[int]int, len(userPoints))
+	for _, userPoint := range userPoints {
+		recorded[userPoint.UserID] = userPoint.Balance
+	}
+
+	// 残高行のみ・取引履歴のみのユーザーも対象にする
+	userIDs := make([]int, 0, len(recorded))
+	for userID := range recorded {
+		userIDs = append(userIDs, userID)
+	}
+	for userID := range ledger {
+		if _, ok := recorded[userID]; !ok {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	sort.Ints(userIDs)
+
+	for _, userID := range userIDs {
+		report.CheckedUsers++
+		if recorded[userID] == ledger[userID] {
+			continue
+		}
+
+		mismatch := model.BalanceMismatch{
+			UserID:          userID,
+			RecordedBalance: recorded[userID],
+			LedgerBalance:   ledger[userID],
+		}
+		if repair {
+			mismatch, err = uc.repair(ctx, userID)
+			if err != nil {
+				return nil, err
+			}
+		}
+		report.Mismatches = append(report.Mismatches, mismatch)
+	}
+
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+// repair re-checks the user under lock, since the bulk read above may be
+// stale, and records the difference as an adjustment transaction.
+func (uc *reconcileUsecase) repair(ctx context.Context, userID int) (model.BalanceMismatch, error) {
+	mismatch := model.BalanceMismatch{UserID: userID}
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		userPoint, err := uc.pointRepo.GetUserPointForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if userPoint != nil {
+			mismatch.RecordedBalance = userPoint.Balance
+		}
+
+		mismatch.LedgerBalance, err = uc.pointRepo.GetLedgerBalance(ctx, model.UserWalletAccount(userID))
+		if err != nil {
+			return err
+		}
+
+		difference := mismatch.Difference()
+		if difference == 0 {
+			mismatch.Repaired = true
+			return nil
+		}
+
+		description := fmt.Sprintf("Reconciliation: ledger %d, recorded balance %d", mismatch.LedgerBalance, mismatch.RecordedBalance)
+		adjustment, err := model.NewAdjustmentTransaction(userID, difference, description)
+		if err != nil {
+			return err
+		}
+		if err := uc.pointRepo.SaveTransaction(ctx, adjustment); err != nil {
+			return err
+		}
+
+		mismatch.Repaired = true
+		return nil
+	})
+	return mismatch, err
+}
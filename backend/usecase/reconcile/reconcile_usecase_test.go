@@ -0,0 +1,245 @@
+package reconcile
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/point"
+)
+
+// fakePointStore keeps user_points, point_transactions and the ledger in
+// memory. SaveTransaction posts LedgerEntries like the MySQL repository, and
+// the ledger balances are derived from the posted entries the way the
+// account_balances trigger derives them.
+type fakePointStore struct {
+	repository.PointRepository
+	userPoints   map[int]*model.UserPoint
+	transactions []*model.PointTransaction
+	entries      []model.LedgerEntry
+}
+
+func newFakePointStore() *fakePointStore {
+	return &fakePointStore{userPoints: make(map[int]*model.UserPoint)}
+}
+
+func (s *fakePointStore) GetUserPointForUpdate(ctx context.Context, userID int) (*model.UserPoint, error) {
+	return s.userPoints[userID], nil
+}
+
+func (s *fakePointStore) CreateUserPoint(ctx context.Context, userPoint *model.UserPoint) error {
+	s.userPoints[userPoint.UserID] = userPoint
+	return nil
+}
+
+func (s *fakePointStore) UpdateUserPoint(ctx context.Context, userPoint *model.UserPoint) error {
+	s.userPoints[userPoint.UserID] = userPoint
+	return nil
+}
+
+func (s *fakePointStore) SaveTransaction(ctx context.Context, transaction *model.PointTransaction) error {
+	transaction.ID = len(s.transactions) + 1
+	s.transactions = append(s.transactions, transaction)
+	s.entries = append(s.entries, transaction.LedgerEntries()...)
+	return nil
+}
+
+func (s *fakePointStore) FindAllUserPoints(ctx context.Context) ([]*model.UserPoint, error) {
+	var userPoints []*model.UserPoint
+	for _, userPoint := range s.userPoints {
+		userPoints = append(userPoints, userPoint)
+	}
+	return userPoints, nil
+}
+
+func (s *fakePointStore) GetLedgerBalance(ctx context.Context, account model.LedgerAccount) (int, error) {
+	balance := 0
+	for _, entry := range s.entries {
+		if entry.Account == account {
+			balance += entry.Amount
+		}
+	}
+	return balance, nil
+}
+
+func (s *fakePointStore) FindUserLedgerBalances(ctx context.Context) (map[int]int, error) {
+	balances := make(map[int]int)
+	for _, entry := range s.entries {
+		if userID, ok := entry.Account.UserID(); ok {
+			balances[userID] += entry.Amount
+		}
+	}
+	return balances, nil
+}
+
+func (s *fakePointStore) SumLedger(ctx context.Context) (int, error) {
+	total := 0
+	for _, entry := range s.entries {
+		total += entry.Amount
+	}
+	return total, nil
+}
+
+func (s *fakePointStore) FindUnbalancedTransactionIDs(ctx context.Context, limit int) ([]int, error) {
+	sums := make(map[int]int)
+	for _, entry := range s.entries {
+		sums[entry.TransactionID] += entry.Amount
+	}
+	var ids []int
+	for _, transaction := range s.transactions {
+		if sums[transaction.ID] != 0 && len(ids) < limit {
+			ids = append(ids, transaction.ID)
+		}
+	}
+	return ids, nil
+}
+
+type fakeLotStore struct {
+	repository.PointLotRepository
+	lots []*model.PointLot
+}
+
+func (s *fakeLotStore) CreateLot(ctx context.Context, lot *model.PointLot) error {
+	s.lots = append(s.lots, lot)
+	return nil
+}
+
+func (s *fakeLotStore) UpdateLot(ctx context.Context, lot *model.PointLot) error {
+	return nil
+}
+
+func (s *fakeLotStore) FindActiveLotsForUpdate(ctx context.Context, userID int) ([]*model.PointLot, error) {
+	var lots []*model.PointLot
+	for _, lot := range s.lots {
+		if lot.UserID == userID && lot.Remaining > 0 {
+			lots = append(lots, lot)
+		}
+	}
+	sort.SliceStable(lots, func(i, j int) bool { return lots[i].ExpiresAt.Before(lots[j].ExpiresAt) })
+	return lots, nil
+}
+
+// activeUsers reports every user as an active account
+type activeUsers struct {
+	repository.UserRepository
+}
+
+func (activeUsers) FindByIDForUpdate(ctx context.Context, id int) (*model.User, error) {
+	return &model.User{ID: id, Status: model.UserStatusActive}, nil
+}
+
+type fakeTransactor struct{}
+
+func (fakeTransactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func mustTransaction(t *testing.T, userID, amount int, transactionType model.TransactionType) *model.PointTransaction {
+	t.Helper()
+	transaction, err := model.NewPointTransaction(userID, amount, transactionType, string(transactionType))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return transaction
+}
+
+func TestLedgerMatchesBalancesAfterEveryWalletMovement(t *testing.T) {
+	ctx := context.Background()
+	store, lots := newFakePointStore(), &fakeLotStore{}
+	wallet := point.NewWallet(store, lots, activeUsers{}, fakeTransactor{}, 30)
+
+	// 付与・消費・送付・管理者調整・失効を一通り行う
+	if err := wallet.Credit(ctx, mustTransaction(t, 1, 800, model.TransactionTypeGacha)); err != nil {
+		t.Fatal(err)
+	}
+	if err := wallet.Credit(ctx, mustTransaction(t, 2, 150, model.TransactionTypeAchievement)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wallet.Debit(ctx, mustTransaction(t, 1, 120, model.TransactionTypeSpend)); err != nil {
+		t.Fatal(err)
+	}
+
+	transfer, err := model.NewPointTransfer(1, 2, 300, "gift")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, in, err := transfer.Transactions()
+	if err != nil {
+		t.Fatal(err)
+	}
+	consumed, err := wallet.Debit(ctx, out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wallet.Credit(ctx, in, consumed...); err != nil {
+		t.Fatal(err)
+	}
+
+	adjustment, err := model.NewPointAdjustment(2, -50, model.AdjustmentReasonCorrection, "double reward", "ops", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := wallet.Adjust(ctx, adjustment.Transaction(), false); err != nil {
+		t.Fatal(err)
+	}
+
+	// 30 日の有効期限を過ぎた時点で両ユーザーの残りのポイントを失効させる
+	later := time.Now().AddDate(0, 0, 31)
+	for _, userID := range []int{1, 2} {
+		if _, err := wallet.Expire(ctx, userID, later); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := wallet.Credit(ctx, mustTransaction(t, 2, 40, model.TransactionTypeGacha)); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := NewReconcileUsecase(store, fakeTransactor{}).Reconcile(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Unresolved() != 0 {
+		t.Fatalf("reconciliation found %d problems: %+v", report.Unresolved(), report)
+	}
+	if report.CheckedUsers != 2 {
+		t.Errorf("checked %d users, want 2", report.CheckedUsers)
+	}
+	if got := store.userPoints[2].Balance; got != 40 {
+		t.Errorf("user 2 balance = %d, want the 40 earned after the expiry", got)
+	}
+}
+
+func TestReconcileReportsAndRepairsBalanceChangedOutsideTheWallet(t *testing.T) {
+	ctx := context.Background()
+	store := newFakePointStore()
+	wallet := point.NewWallet(store, &fakeLotStore{}, activeUsers{}, fakeTransactor{}, 30)
+	if err := wallet.Credit(ctx, mustTransaction(t, 1, 500, model.TransactionTypeGacha)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 台帳を通さずに残高だけを書き換える
+	store.userPoints[1].Balance += 25
+
+	uc := NewReconcileUsecase(store, fakeTransactor{})
+	report, err := uc.Reconcile(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Mismatches) != 1 || report.Mismatches[0].Difference() != 25 {
+		t.Fatalf("mismatches = %+v, want user 1 ahead of the ledger by 25", report.Mismatches)
+	}
+
+	if _, err := uc.Reconcile(ctx, true); err != nil {
+		t.Fatal(err)
+	}
+	report, err = uc.Reconcile(ctx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Unresolved() != 0 {
+		t.Errorf("%d problems left after repair: %+v", report.Unresolved(), report)
+	}
+}
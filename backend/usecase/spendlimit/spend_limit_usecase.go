@@ -0,0 +1,222 @@
+package spendlimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/audit"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/logging"
+)
+
+// Guard enforces the user's own limits. Call it inside the transaction that
+// records the spin or spend so concurrent requests cannot both pass the check.
+type Guard interface {
+	CheckSpin(ctx context.Context, userID int, now time.Time) error
+	CheckSpend(ctx context.Context, userID int, amount int, now time.Time) error
+}
+
+// Status is a user's limits with their current usage
+type Status struct {
+	Limits        *model.SpendLimits
+	SpinsToday    int
+	SpentThisWeek int
+}
+
+type SpendLimitUsecase interface {
+	Guard
+	GetLimits(ctx context.Context, userID int) (*Status, error)
+	// UpdateLimits applies stricter limits at once and looser ones after the
+	// configured delay. The user proves the request is theirs with their token.
+	UpdateLimits(ctx context.Context, userID int, token string, change model.SpendLimitChange) (*Status, error)
+}
+
+type spendLimitUsecase struct {
+	limitRepo     repository.SpendLimitRepository
+	gachaRepo     repository.GachaRepository
+	pointRepo     repository.PointRepository
+	userRepo      repository.UserRepository
+	transactor    repository.Transactor
+	recorder      audit.Recorder
+	increaseDelay time.Duration
+}
+
+func NewSpendLimitUsecase(
+	limitRepo repository.SpendLimitRepository,
+	gachaRepo repository.GachaRepository,
+	pointRepo repository.PointRepository,
+	userRepo repository.UserRepository,
+	transactor repository.Transactor,
+	recorder audit.Recorder,
+	increaseDelay time.Duration,
+) SpendLimitUsecase {
+	if increaseDelay <= 0 {
+		increaseDelay = model.DefaultLimitIncreaseDelay
+	}
+	return &spendLimitUsecase{
+		limitRepo:     limitRepo,
+		gachaRepo:     gachaRepo,
+		pointRepo:     pointRepo,
+		userRepo:      userRepo,
+		transactor:    transactor,
+		recorder:      recorder,
+		increaseDelay: increaseDelay,
+	}
+}
+
+func (uc *spendLimitUsecase) GetLimits(ctx context.Context, userID int) (*Status, error) {
+	logging.Operation(ctx, "spend_limits.get", userID)
+	if err := uc.ensureUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	limits, err := uc.limitRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if limits == nil {
+		limits = model.NewSpendLimits(userID)
+	}
+	// 猶予期間を過ぎた引き上げは保存を待たずに反映して返す
+	limits.ApplyDue(time.Now())
+
+	return uc.status(ctx, limits, time.Now())
+}
+
+func (uc *spendLimitUsecase) UpdateLimits(ctx context.Context, userID int, token string, change model.SpendLimitChange) (*Status, error) {
+	logging.Operation(ctx, audit.ActionSpendLimitsUpdate, userID)
+	// 上限の変更は利用者本人のみが行える (監査記録も本人の操作として残る)
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, model.ErrUserNotFound
+	}
+	if err := user.Authenticate(token); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var limits *model.SpendLimits
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		limits, err = uc.limitRepo.FindByUserIDForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+		var before *model.SpendLimits
+		if limits == nil {
+			limits = model.NewSpendLimits(userID)
+		} else {
+			previous := *limits
+			before = &previous
+		}
+
+		if err := limits.Update(change, now, uc.increaseDelay); err != nil {
+			return err
+		}
+		if err := uc.limitRepo.Save(ctx, limits); err != nil {
+			return err
+		}
+
+		record := audit.Change{
+			Actor:      audit.UserActor(userID),
+			Action:     audit.ActionSpendLimitsUpdate,
+			TargetType: "spend_limits",
+			TargetID:   strconv.Itoa(userID),
+			After:      limits,
+		}
+		if before != nil {
+			record.Before = before
+		}
+		return uc.recorder.Record(ctx, record)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.status(ctx, limits, now)
+}
+
+func (uc *spendLimitUsecase) CheckSpin(ctx context.Context, userID int, now time.Time) error {
+	limits, err := uc.currentLimits(ctx, userID, now)
+	if err != nil || limits == nil {
+		return err
+	}
+
+	spinsToday, err := uc.gachaRepo.CountResultsSince(ctx, userID, model.StartOfDay(now))
+	if err != nil {
+		return err
+	}
+	return limits.CheckSpin(now, spinsToday)
+}
+
+func (uc *spendLimitUsecase) CheckSpend(ctx context.Context, userID int, amount int, now time.Time) error {
+	limits, err := uc.currentLimits(ctx, userID, now)
+	if err != nil || limits == nil {
+		return err
+	}
+
+	spent, err := uc.spentSince(ctx, userID, model.StartOfWeek(now))
+	if err != nil {
+		return err
+	}
+	return limits.CheckSpend(now, spent, amount)
+}
+
+// currentLimits locks the user's limits for the rest of the transaction.
+// Users without limits have no row, so their requests are not serialised.
+func (uc *spendLimitUsecase) currentLimits(ctx context.Context, userID int, now time.Time) (*model.SpendLimits, error) {
+	limits, err := uc.limitRepo.FindByUserIDForUpdate(ctx, userID)
+	if err != nil || limits == nil {
+		return nil, err
+	}
+	limits.ApplyDue(now)
+	return limits, nil
+}
+
+func (uc *spendLimitUsecase) status(ctx context.Context, limits *model.SpendLimits, now time.Time) (*Status, error) {
+	spinsToday, err := uc.gachaRepo.CountResultsSince(ctx, limits.UserID, model.StartOfDay(now))
+	if err != nil {
+		return nil, err
+	}
+	spent, err := uc.spentSince(ctx, limits.UserID, model.StartOfWeek(now))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Status{
+		Limits:        limits,
+		SpinsToday:    spinsToday,
+		SpentThisWeek: spent,
+	}, nil
+}
+
+// spentSince sums the points the user spent since the given time
+func (uc *spendLimitUsecase) spentSince(ctx context.Context, userID int, since time.Time) (int, error) {
+	spent := 0
+	for _, transactionType := range model.SpendTransactionTypes {
+		total, err := uc.pointRepo.SumTransactionsSince(ctx, userID, transactionType, since)
+		if err != nil {
+			return 0, err
+		}
+		// 消費系の取引は負数で記録されている
+		spent -= total
+	}
+	return spent, nil
+}
+
+func (uc *spendLimitUsecase) ensureUser(ctx context.Context, userID int) error {
+	// ユーザーの存在確認
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		return model.ErrUserNotFound
+	}
+	return nil
+}
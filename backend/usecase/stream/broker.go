@@ -0,0 +1,235 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/event"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+)
+
+const (
+	MessageBalanceChanged = "balance_changed"
+	MessageGachaResult    = "gacha_result"
+	MessageRarePull       = "rare_pull"
+
+	// historySize is how many recent messages are kept for Last-Event-ID resume
+	historySize = 1024
+	// clientBufferSize is how many messages may queue for a slow client
+	// before it is disconnected and has to resume
+	clientBufferSize = 64
+	// rarePullRarity is the lowest rarity announced on the global feed
+	rarePullRarity = model.RarityEpic
+)
+
+// Message is a server-sent event. ID is the ID of the stored event it was
+// built from, or 0 when the event was not stored and cannot be resumed.
+type Message struct {
+	ID     int64
+	Event  string
+	UserID int  // recipient; 0 for global messages
+	Global bool // part of the rare pull feed
+	Data   []byte
+}
+
+// Filter selects the messages a client receives
+type Filter struct {
+	UserID    int
+	RarePulls bool
+}
+
+func (f Filter) matches(msg Message) bool {
+	if msg.Global {
+		return f.RarePulls
+	}
+	return f.UserID != 0 && msg.UserID == f.UserID
+}
+
+// Subscription is one connected client
+type Subscription struct {
+	// Backlog holds the messages missed since Last-Event-ID, oldest first
+	Backlog []Message
+	// Reset is set when messages after Last-Event-ID are no longer buffered
+	// and the client should refetch its state
+	Reset bool
+	// Messages is closed when the client falls too far behind or the broker closes
+	Messages <-chan Message
+
+	filter   Filter
+	messages chan Message
+}
+
+// Broker turns domain events into stream messages and fans them out to
+// connected clients, keeping a ring buffer for resume
+type Broker struct {
+	tiers *model.RarityRegistry
+
+	mu      sync.Mutex
+	clients map[*Subscription]struct{}
+	history []Message
+	next    int // ring buffer write position
+	full    bool
+	closed  bool
+}
+
+func NewBroker(tiers *model.RarityRegistry) *Broker {
+	return &Broker{
+		tiers:   tiers,
+		clients: make(map[*Subscription]struct{}),
+		history: make([]Message, historySize),
+	}
+}
+
+// HandleEvent is an event subscriber; it never blocks on slow clients
+func (b *Broker) HandleEvent(ctx context.Context, e event.Event) error {
+	messages, err := b.messagesFor(e)
+	if err != nil {
+		return err
+	}
+	id, _ := event.IDFromContext(ctx)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil
+	}
+
+	for _, msg := range messages {
+		msg.ID = id
+		if msg.ID != 0 {
+			b.history[b.next] = msg
+			b.next = (b.next + 1) % historySize
+			b.full = b.full || b.next == 0
+		}
+
+		for client := range b.clients {
+			if !client.filter.matches(msg) {
+				continue
+			}
+			select {
+			case client.messages <- msg:
+			default:
+				// 遅いクライアントは切断し、Last-Event-ID で再接続させる
+				b.removeLocked(client)
+			}
+		}
+	}
+	return nil
+}
+
+// Subscribe registers a client. With lastEventID set, buffered messages after
+// it are returned in the backlog.
+func (b *Broker) Subscribe(filter Filter, lastEventID int64) (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, errors.New("stream is shutting down")
+	}
+
+	messages := make(chan Message, clientBufferSize)
+	subscription := &Subscription{
+		Messages: messages,
+		filter:   filter,
+		messages: messages,
+	}
+
+	if lastEventID > 0 {
+		subscription.Backlog, subscription.Reset = b.since(filter, lastEventID)
+	}
+
+	b.clients[subscription] = struct{}{}
+	return subscription, nil
+}
+
+// Unsubscribe removes a client that disconnected
+func (b *Broker) Unsubscribe(subscription *Subscription) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.removeLocked(subscription)
+}
+
+// Close disconnects every client
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for client := range b.clients {
+		b.removeLocked(client)
+	}
+}
+
+func (b *Broker) removeLocked(subscription *Subscription) {
+	if _, ok := b.clients[subscription]; !ok {
+		return
+	}
+	delete(b.clients, subscription)
+	close(subscription.messages)
+}
+
+// since returns buffered messages after lastEventID matching filter, and
+// whether messages the client needs may be missing because they predate the
+// buffer (overwritten, or published before a restart)
+func (b *Broker) since(filter Filter, lastEventID int64) ([]Message, bool) {
+	ordered := b.history[:b.next]
+	if b.full {
+		ordered = append(append([]Message{}, b.history[b.next:]...), b.history[:b.next]...)
+	}
+	if len(ordered) == 0 {
+		return nil, true
+	}
+
+	// 再送で順序が前後する場合があるため、ID で比較する
+	var backlog []Message
+	oldest := ordered[0].ID
+	for _, msg := range ordered {
+		if msg.ID < oldest {
+			oldest = msg.ID
+		}
+		if msg.ID > lastEventID && filter.matches(msg) {
+			backlog = append(backlog, msg)
+		}
+	}
+	return backlog, lastEventID < oldest
+}
+
+func (b *Broker) messagesFor(e event.Event) ([]Message, error) {
+	switch e := e.(type) {
+	case event.GachaExecuted:
+		data, err := json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		messages := []Message{{Event: MessageGachaResult, UserID: e.UserID, Data: data}}
+		if b.tiers.AtLeast(e.Rarity, rarePullRarity) {
+			messages = append(messages, Message{Event: MessageRarePull, Global: true, Data: data})
+		}
+		return messages, nil
+	case event.PointsCredited:
+		return balanceChanged(e.PointsChanged)
+	case event.PointsDebited:
+		return balanceChanged(e.PointsChanged)
+	}
+	return nil, nil
+}
+
+func balanceChanged(changed event.PointsChanged) ([]Message, error) {
+	data, err := json.Marshal(struct {
+		UserID        int                   `json:"user_id"`
+		Balance       int                   `json:"balance"`
+		Amount        int                   `json:"amount"`
+		Type          model.TransactionType `json:"type"`
+		TransactionID int                   `json:"transaction_id"`
+	}{
+		UserID:        changed.UserID,
+		Balance:       changed.BalanceAfter,
+		Amount:        changed.Amount,
+		Type:          changed.Type,
+		TransactionID: changed.TransactionID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []Message{{Event: MessageBalanceChanged, UserID: changed.UserID, Data: data}}, nil
+}
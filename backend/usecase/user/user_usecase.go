@@ -0,0 +1,229 @@
+package user
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/event"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/audit"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/logging"
+)
+
+type UserUsecase interface {
+	// CreateUser stores a user built by model.NewUser and returns the secret
+	// token issued to it, which the user presents to move points out
+	CreateUser(ctx context.Context, user *model.User) (string, error)
+	GetUser(ctx context.Context, userID int) (*model.User, error)
+	// RenameUser changes the user's name; the user proves the request is
+	// theirs with their token
+	RenameUser(ctx context.Context, userID int, token string, name string) (*model.User, error)
+	// UpdateVerification records a verified region and birth date; nil
+	// fields are left unchanged
+	UpdateVerification(ctx context.Context, userID int, region *string, birthDate *time.Time) (*model.User, error)
+	// ChangeStatus freezes, bans or reactivates the account and records who did it
+	ChangeStatus(ctx context.Context, userID int, status model.UserStatus, reason string, until *time.Time, adminID string) (*model.User, error)
+	GetStatusHistory(ctx context.Context, userID int, limit int) ([]*model.UserStatusChange, error)
+	// IssueToken replaces the user's token, for accounts that lost theirs or
+	// predate tokens
+	IssueToken(ctx context.Context, userID int) (string, error)
+}
+
+type userUsecase struct {
+	userRepo   repository.UserRepository
+	statusRepo repository.UserStatusRepository
+	transactor repository.Transactor
+	publisher  event.Publisher
+	recorder   audit.Recorder
+}
+
+func NewUserUsecase(
+	userRepo repository.UserRepository,
+	statusRepo repository.UserStatusRepository,
+	transactor repository.Transactor,
+	publisher event.Publisher,
+	recorder audit.Recorder,
+) UserUsecase {
+	return &userUsecase{
+		userRepo:   userRepo,
+		statusRepo: statusRepo,
+		transactor: transactor,
+		publisher:  publisher,
+		recorder:   recorder,
+	}
+}
+
+func (uc *userUsecase) CreateUser(ctx context.Context, user *model.User) (string, error) {
+	logging.AddAttrs(ctx, logging.KeyOperation, audit.ActionUserCreate)
+	token, err := user.IssueToken()
+	if err != nil {
+		return "", err
+	}
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+		logging.AddAttrs(ctx, logging.KeyUserID, user.ID)
+		if err := uc.publisher.Publish(ctx, event.UserCreated{UserID: user.ID, Name: user.Name, OccurredAt: user.CreatedAt}); err != nil {
+			return err
+		}
+		return uc.record(ctx, audit.ActionUserCreate, nil, user)
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (uc *userUsecase) RenameUser(ctx context.Context, userID int, token string, name string) (*model.User, error) {
+	return uc.update(ctx, userID, audit.ActionUserRename, func(user *model.User) error {
+		// 監査記録は本人の操作として残るため、本人のトークンを確認する
+		if err := user.Authenticate(token); err != nil {
+			return err
+		}
+		return user.UpdateName(name)
+	})
+}
+
+func (uc *userUsecase) GetUser(ctx context.Context, userID int) (*model.User, error) {
+	logging.Operation(ctx, "user.get", userID)
+	user, err := uc.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, model.ErrUserNotFound
+	}
+	return user, nil
+}
+
+func (uc *userUsecase) UpdateVerification(ctx context.Context, userID int, region *string, birthDate *time.Time) (*model.User, error) {
+	return uc.update(ctx, userID, audit.ActionUserVerification, func(user *model.User) error {
+		if region != nil {
+			if err := user.VerifyRegion(*region, time.Now()); err != nil {
+				return err
+			}
+		}
+		if birthDate != nil {
+			if err := user.VerifyAge(*birthDate, time.Now()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// update applies change to the locked user row and records it
+func (uc *userUsecase) update(ctx context.Context, userID int, action string, change func(user *model.User) error) (*model.User, error) {
+	logging.Operation(ctx, action, userID)
+	var user *model.User
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = uc.userRepo.FindByIDForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return model.ErrUserNotFound
+		}
+
+		before := *user
+		if err := change(user); err != nil {
+			return err
+		}
+		if err := uc.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return uc.record(ctx, action, &before, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (uc *userUsecase) ChangeStatus(ctx context.Context, userID int, status model.UserStatus, reason string, until *time.Time, adminID string) (*model.User, error) {
+	logging.Operation(ctx, audit.ActionUserStatus, userID)
+	var user *model.User
+	// 状態の更新と監査記録を同一トランザクションで保存
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = uc.userRepo.FindByIDForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return model.ErrUserNotFound
+		}
+
+		before := *user
+		change, err := user.ChangeStatus(status, reason, until, adminID, time.Now())
+		if err != nil {
+			return err
+		}
+		if err := uc.userRepo.UpdateStatus(ctx, user); err != nil {
+			return err
+		}
+		if err := uc.statusRepo.SaveChange(ctx, change); err != nil {
+			return err
+		}
+		return uc.record(ctx, audit.ActionUserStatus, &before, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (uc *userUsecase) IssueToken(ctx context.Context, userID int) (string, error) {
+	logging.Operation(ctx, audit.ActionUserToken, userID)
+	var token string
+	err := uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		user, err := uc.userRepo.FindByIDForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return model.ErrUserNotFound
+		}
+
+		before := *user
+		token, err = user.IssueToken()
+		if err != nil {
+			return err
+		}
+		if err := uc.userRepo.UpdateToken(ctx, user); err != nil {
+			return err
+		}
+		// トークンのハッシュは監査記録に含まれない
+		return uc.record(ctx, audit.ActionUserToken, &before, user)
+	})
+	if err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+func (uc *userUsecase) GetStatusHistory(ctx context.Context, userID int, limit int) ([]*model.UserStatusChange, error) {
+	logging.Operation(ctx, "user.status_history", userID)
+	if _, err := uc.GetUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return uc.statusRepo.FindChangesByUserID(ctx, userID, limit)
+}
+
+func (uc *userUsecase) record(ctx context.Context, action string, before, after *model.User) error {
+	change := audit.Change{
+		Actor:      audit.UserActor(after.ID),
+		Action:     action,
+		TargetType: "user",
+		TargetID:   strconv.Itoa(after.ID),
+		After:      after,
+	}
+	if before != nil {
+		change.Before = before
+	}
+	return uc.recorder.Record(ctx, change)
+}
@@ -0,0 +1,286 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/event"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/usecase/audit"
+)
+
+const (
+	deliveryBatchSize = 50
+	// deliveryLease keeps a claimed delivery from other workers while it is sent
+	deliveryLease        = 2 * time.Minute
+	maxDeadLetterListing = 100
+)
+
+// Request is a signed webhook call
+type Request struct {
+	URL     string
+	Headers map[string]string
+	Body    []byte
+}
+
+// Sender posts webhook requests and returns the receiver's status code
+type Sender interface {
+	Send(ctx context.Context, req Request) (int, error)
+}
+
+// RegisterWebhookInput describes a subscription created by an admin
+type RegisterWebhookInput struct {
+	URL        string
+	Secret     string
+	EventNames []string
+	Filter     string
+	AdminID    string
+}
+
+// TestResult is the outcome of a test delivery
+type TestResult struct {
+	StatusCode int
+	Error      string
+}
+
+type WebhookUsecase interface {
+	Register(ctx context.Context, input RegisterWebhookInput) (*model.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error)
+	// HandleEvent queues a delivery for every active subscription matching the event
+	HandleEvent(ctx context.Context, e event.Event) error
+	// DeliverPending sends due deliveries and returns how many succeeded
+	DeliverPending(ctx context.Context) (int, error)
+	// SendTest sends a webhook.test event to the subscription immediately
+	SendTest(ctx context.Context, subscriptionID int) (*TestResult, error)
+	ListDeadLetters(ctx context.Context, subscriptionID int, limit int) ([]*model.WebhookDelivery, error)
+	// Replay requeues a dead-lettered delivery
+	Replay(ctx context.Context, deliveryID int64) (*model.WebhookDelivery, error)
+}
+
+type webhookUsecase struct {
+	webhookRepo repository.WebhookRepository
+	transactor  repository.Transactor
+	recorder    audit.Recorder
+	sender      Sender
+	// tiers resolve the rarity names in subscription filters
+	tiers *model.RarityRegistry
+}
+
+func NewWebhookUsecase(webhookRepo repository.WebhookRepository, transactor repository.Transactor, recorder audit.Recorder, sender Sender, tiers *model.RarityRegistry) WebhookUsecase {
+	return &webhookUsecase{
+		webhookRepo: webhookRepo,
+		transactor:  transactor,
+		recorder:    recorder,
+		sender:      sender,
+		tiers:       tiers,
+	}
+}
+
+func (uc *webhookUsecase) Register(ctx context.Context, input RegisterWebhookInput) (*model.WebhookSubscription, error) {
+	subscription, err := model.NewWebhookSubscription(input.URL, input.Secret, input.EventNames, input.Filter, input.AdminID, uc.tiers)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.webhookRepo.CreateSubscription(ctx, subscription); err != nil {
+			return err
+		}
+
+		// シークレットは監査ログに残さない
+		recorded := *subscription
+		recorded.Secret = ""
+		return uc.recorder.Record(ctx, audit.Change{
+			Actor:      audit.AdminActor(input.AdminID),
+			Action:     audit.ActionWebhookRegister,
+			TargetType: "webhook_subscription",
+			TargetID:   strconv.Itoa(subscription.ID),
+			After:      &recorded,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+func (uc *webhookUsecase) ListSubscriptions(ctx context.Context) ([]*model.WebhookSubscription, error) {
+	return uc.webhookRepo.FindSubscriptions(ctx, false)
+}
+
+func (uc *webhookUsecase) HandleEvent(ctx context.Context, e event.Event) error {
+	subscriptions, err := uc.webhookRepo.FindSubscriptions(ctx, true)
+	if err != nil || len(subscriptions) == 0 {
+		return err
+	}
+
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(payload, &fields); err != nil {
+		return err
+	}
+
+	// 再配信時は同じイベントIDで重複が除外される
+	eventID, _ := event.IDFromContext(ctx)
+	for _, subscription := range subscriptions {
+		if !subscription.Matches(e.EventName(), fields, uc.tiers) {
+			continue
+		}
+		delivery := model.NewWebhookDelivery(subscription.ID, eventID, e.EventName(), payload)
+		if _, err := uc.webhookRepo.EnqueueDelivery(ctx, delivery); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (uc *webhookUsecase) DeliverPending(ctx context.Context) (int, error) {
+	delivered := 0
+	subscriptions := make(map[int]*model.WebhookSubscription)
+	for {
+		deliveries, err := uc.webhookRepo.ClaimDueDeliveries(ctx, time.Now(), deliveryLease, deliveryBatchSize)
+		if err != nil {
+			return delivered, err
+		}
+
+		for _, delivery := range deliveries {
+			subscription, ok := subscriptions[delivery.SubscriptionID]
+			if !ok {
+				subscription, err = uc.webhookRepo.FindSubscriptionByID(ctx, delivery.SubscriptionID)
+				if err != nil {
+					return delivered, err
+				}
+				subscriptions[delivery.SubscriptionID] = subscription
+			}
+
+			if subscription == nil || !subscription.Active {
+				delivery.MarkAttemptFailed(time.Now(), 0, errors.New("subscription is inactive"))
+			} else if statusCode, err := uc.send(ctx, subscription, delivery); err != nil {
+				delivery.MarkAttemptFailed(time.Now(), statusCode, err)
+			} else {
+				delivery.MarkDelivered(time.Now(), statusCode)
+				delivered++
+			}
+
+			if err := uc.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+				return delivered, err
+			}
+		}
+
+		if len(deliveries) < deliveryBatchSize {
+			return delivered, nil
+		}
+	}
+}
+
+func (uc *webhookUsecase) SendTest(ctx context.Context, subscriptionID int) (*TestResult, error) {
+	subscription, err := uc.webhookRepo.FindSubscriptionByID(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, errors.New("webhook not found")
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"subscription_id": subscription.ID,
+		"occurred_at":     time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := model.NewWebhookDelivery(subscription.ID, 0, model.WebhookTestEventName, payload)
+	statusCode, err := uc.send(ctx, subscription, delivery)
+	result := &TestResult{StatusCode: statusCode}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result, nil
+}
+
+func (uc *webhookUsecase) ListDeadLetters(ctx context.Context, subscriptionID int, limit int) ([]*model.WebhookDelivery, error) {
+	if limit <= 0 || limit > maxDeadLetterListing {
+		limit = maxDeadLetterListing
+	}
+	return uc.webhookRepo.FindDeadDeliveries(ctx, subscriptionID, limit)
+}
+
+func (uc *webhookUsecase) Replay(ctx context.Context, deliveryID int64) (*model.WebhookDelivery, error) {
+	delivery, err := uc.webhookRepo.FindDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, errors.New("delivery not found")
+	}
+
+	before := *delivery
+	if err := delivery.Replay(time.Now()); err != nil {
+		return nil, err
+	}
+	err = uc.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+			return err
+		}
+		return uc.recorder.Record(ctx, audit.Change{
+			Action:     audit.ActionWebhookReplay,
+			TargetType: "webhook_delivery",
+			TargetID:   strconv.FormatInt(delivery.ID, 10),
+			Before:     &before,
+			After:      delivery,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// send posts the delivery and treats any non-2xx response as a failure
+func (uc *webhookUsecase) send(ctx context.Context, subscription *model.WebhookSubscription, delivery *model.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(struct {
+		ID        int64           `json:"id"`
+		Event     string          `json:"event"`
+		CreatedAt time.Time       `json:"created_at"`
+		Data      json.RawMessage `json:"data"`
+	}{
+		ID:        delivery.ID,
+		Event:     delivery.EventName,
+		CreatedAt: delivery.CreatedAt,
+		Data:      delivery.Payload,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := time.Now().Unix()
+	statusCode, err := uc.sender.Send(ctx, Request{
+		URL: subscription.URL,
+		Headers: map[string]string{
+			"Content-Type":               "application/json",
+			"X-FortuneSpinner-Event":     delivery.EventName,
+			"X-FortuneSpinner-Delivery":  strconv.FormatInt(delivery.ID, 10),
+			"X-FortuneSpinner-Timestamp": strconv.FormatInt(timestamp, 10),
+			"X-FortuneSpinner-Signature": "sha256=" + model.SignWebhookPayload(subscription.Secret, timestamp, body),
+		},
+		Body: body,
+	})
+	if err != nil {
+		return statusCode, err
+	}
+	if statusCode < 200 || statusCode >= 300 {
+		return statusCode, fmt.Errorf("receiver responded with status %d", statusCode)
+	}
+	return statusCode, nil
+}
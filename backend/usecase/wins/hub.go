@@ -0,0 +1,190 @@
+package wins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/event"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/model"
+	"github.com/Amane-Fujiwara11/FortuneSpinner/backend/domain/repository"
+)
+
+const (
+	// minWinRarity is the lowest rarity broadcast as a big win; rarer tiers are broadcast too
+	minWinRarity = model.RarityEpic
+	// clientBufferSize is how many wins may queue for a slow client; older
+	// wins are dropped to make room since only recent wins matter on a ticker
+	clientBufferSize = 16
+	// shutdownWait bounds how long Close waits for connections to say goodbye
+	shutdownWait = 5 * time.Second
+)
+
+var (
+	ErrTooManyConnections = errors.New("too many connections")
+	ErrHubClosed          = errors.New("server is shutting down")
+)
+
+// Win is a big win as broadcast to clients
+type Win struct {
+	UserID       int       `json:"user_id"`
+	UserName     string    `json:"user_name"`
+	ItemName     string    `json:"item_name"`
+	Rarity       string    `json:"rarity"`
+	RarityColor  string    `json:"rarity_color"`
+	PointsEarned int       `json:"points_earned"`
+	OccurredAt   time.Time `json:"occurred_at"`
+}
+
+// Client is one connected ticker
+type Client struct {
+	// Messages carries JSON encoded wins and is closed when the hub shuts down
+	Messages <-chan []byte
+
+	messages chan []byte
+	dropped  int
+}
+
+// Hub broadcasts Epic and Legendary results to every connected client
+type Hub struct {
+	userRepo       repository.UserRepository
+	tiers          *model.RarityRegistry
+	maxConnections int
+
+	mu      sync.Mutex
+	clients map[*Client]struct{}
+	closed  bool
+	active  sync.WaitGroup
+}
+
+func NewHub(userRepo repository.UserRepository, tiers *model.RarityRegistry, maxConnections int) *Hub {
+	return &Hub{
+		userRepo:       userRepo,
+		tiers:          tiers,
+		maxConnections: maxConnections,
+		clients:        make(map[*Client]struct{}),
+	}
+}
+
+// Register adds a client unless the connection limit is reached. Callers
+// must call Unregister once the connection is finished.
+func (h *Hub) Register() (*Client, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, ErrHubClosed
+	}
+	if len(h.clients) >= h.maxConnections {
+		return nil, ErrTooManyConnections
+	}
+
+	messages := make(chan []byte, clientBufferSize)
+	client := &Client{Messages: messages, messages: messages}
+	h.clients[client] = struct{}{}
+	h.active.Add(1)
+	return client, nil
+}
+
+// Unregister removes a finished client
+func (h *Hub) Unregister(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, ok := h.clients[client]; !ok {
+		return
+	}
+	delete(h.clients, client)
+	if !h.closed {
+		close(client.messages)
+	}
+	if client.dropped > 0 {
+		slog.Warn("Wins ticker client dropped messages", "dropped", client.dropped)
+	}
+	h.active.Done()
+}
+
+// ConnectionCount returns the number of connected clients
+func (h *Hub) ConnectionCount() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.clients)
+}
+
+// HandleEvent is an event subscriber for gacha.executed
+func (h *Hub) HandleEvent(ctx context.Context, e event.Event) error {
+	executed, ok := e.(event.GachaExecuted)
+	if !ok || !h.tiers.AtLeast(executed.Rarity, minWinRarity) {
+		return nil
+	}
+
+	win := Win{
+		UserID:       executed.UserID,
+		ItemName:     executed.ItemName,
+		Rarity:       h.tiers.Name(executed.Rarity),
+		RarityColor:  h.tiers.Color(executed.Rarity),
+		PointsEarned: executed.PointsEarned,
+		OccurredAt:   executed.OccurredAt,
+	}
+	// 名前が取得できなくても配信は続ける
+	if user, err := h.userRepo.FindByID(ctx, executed.UserID); err == nil && user != nil {
+		win.UserName = user.Name
+	}
+
+	data, err := json.Marshal(win)
+	if err != nil {
+		return err
+	}
+	h.broadcast(data)
+	return nil
+}
+
+func (h *Hub) broadcast(data []byte) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return
+	}
+
+	for client := range h.clients {
+		for {
+			select {
+			case client.messages <- data:
+			default:
+				// バッファが一杯なら最も古いメッセージを捨てる
+				select {
+				case <-client.messages:
+					client.dropped++
+				default:
+				}
+				continue
+			}
+			break
+		}
+	}
+}
+
+// Close stops accepting clients, closes every client's channel so its
+// connection can send a close frame, and waits for them to finish
+func (h *Hub) Close() {
+	h.mu.Lock()
+	if !h.closed {
+		h.closed = true
+		for client := range h.clients {
+			close(client.messages)
+		}
+	}
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.active.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(shutdownWait):
+		slog.Warn("Timed out waiting for wins ticker connections to close", "connections", h.ConnectionCount())
+	}
+}
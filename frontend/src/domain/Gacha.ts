@@ -1,14 +1,10 @@
-export enum Rarity {
-  Common = "Common",
-  Rare = "Rare",
-  Epic = "Epic",
-  Legendary = "Legendary"
-}
-
+// Rarity tiers are configured on the server, so rarities are tier names
+// and their display colour comes with each result
 export interface GachaResult {
   id: number;
   itemName: string;
-  rarity: Rarity;
+  rarity: string;
+  rarityColor: string;
   pointsEarned: number;
 }
 
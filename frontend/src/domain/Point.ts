@@ -6,7 +6,15 @@ export interface UserPoint {
 export interface PointTransaction {
   id: number;
   amount: number;
-  type: "gacha" | "spend";
+  type: "gacha" | "spend" | "transfer_out" | "transfer_in" | "expire" | "adjustment" | "admin_adjustment" | "achievement";
   description: string;
   createdAt: string;
-}
\ No newline at end of file
+}
+
+export interface BalanceChanged {
+  user_id: number;
+  balance: number;
+  amount: number;
+  type: PointTransaction["type"];
+  transaction_id: number;
+}
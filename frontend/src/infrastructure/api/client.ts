@@ -1,4 +1,4 @@
-const API_BASE_URL = process.env.REACT_APP_API_URL || '/api';
+export const API_BASE_URL = process.env.REACT_APP_API_URL || '/api';
 
 export interface ApiResponse<T> {
   success: boolean;
@@ -5,26 +5,32 @@ export interface ExecuteGachaRequest {
   user_id: number;
 }
 
-interface GachaHistoryResponse {
+interface GachaResultResponse {
   id: number;
   item_name: string;
   rarity: string;
+  rarity_color: string;
   points_earned: number;
   created_at: string;
 }
 
+const toGachaHistory = (item: GachaResultResponse): GachaHistory => ({
+  id: item.id,
+  itemName: item.item_name,
+  rarity: item.rarity,
+  rarityColor: item.rarity_color,
+  pointsEarned: item.points_earned,
+  createdAt: item.created_at,
+});
+
 export const gachaApi = {
-  executeGacha: (userId: number): Promise<GachaResult> =>
-    apiClient.post<GachaResult>('/gacha/execute', { user_id: userId }),
+  executeGacha: async (userId: number): Promise<GachaResult> => {
+    const response = await apiClient.post<GachaResultResponse>('/gacha/execute', { user_id: userId });
+    return toGachaHistory(response);
+  },
 
   getGachaHistory: async (userId: number, limit: number = 20): Promise<GachaHistory[]> => {
-    const response = await apiClient.get<GachaHistoryResponse[]>(`/gacha/history?user_id=${userId}&limit=${limit}`);
-    return response.map(item => ({
-      id: item.id,
-      itemName: item.item_name,
-      rarity: item.rarity as any,
-      pointsEarned: item.points_earned,
-      createdAt: item.created_at,
-    }));
+    const response = await apiClient.get<GachaResultResponse[]>(`/gacha/history?user_id=${userId}&limit=${limit}`);
+    return response.map(toGachaHistory);
   },
 };
\ No newline at end of file
@@ -0,0 +1,20 @@
+import { API_BASE_URL } from './client';
+import { BalanceChanged } from '../../domain/Point';
+
+export interface BalanceStreamHandlers {
+  onBalanceChanged: (event: BalanceChanged) => void;
+  // 再接続時に取りこぼしがあった場合に呼ばれる
+  onReset: () => void;
+}
+
+export const streamApi = {
+  // EventSource は切断時に Last-Event-ID 付きで自動再接続する
+  subscribeBalance: (userId: number, handlers: BalanceStreamHandlers): (() => void) => {
+    const source = new EventSource(`${API_BASE_URL}/stream?user_id=${userId}`);
+    source.addEventListener('balance_changed', (event) => {
+      handlers.onBalanceChanged(JSON.parse((event as MessageEvent).data));
+    });
+    source.addEventListener('reset', () => handlers.onReset());
+    return () => source.close();
+  },
+};
@@ -1,6 +1,6 @@
 import React, { useState, useEffect } from 'react';
 import { gachaUsecase } from '../../usecases/gachaUsecase';
-import { GachaHistory as GachaHistoryType, Rarity } from '../../domain/Gacha';
+import { GachaHistory as GachaHistoryType } from '../../domain/Gacha';
 
 interface GachaHistoryProps {
   userId: number;
@@ -31,21 +31,6 @@ export const GachaHistory: React.FC<GachaHistoryProps> = ({ userId, refreshTrigg
     fetchHistory();
   }, [userId, refreshTrigger]);
 
-  const getRarityColor = (rarity: Rarity): string => {
-    switch (rarity) {
-      case Rarity.Common:
-        return '#808080';
-      case Rarity.Rare:
-        return '#0066ff';
-      case Rarity.Epic:
-        return '#8b00ff';
-      case Rarity.Legendary:
-        return '#ff6600';
-      default:
-        return '#000000';
-    }
-  };
-
   const formatDate = (dateString: string): string => {
     if (!dateString) {
       return 'No date';
@@ -78,7 +63,7 @@ export const GachaHistory: React.FC<GachaHistoryProps> = ({ userId, refreshTrigg
               <div className="item-info">
                 <span
                   className="item-name"
-                  style={{ color: getRarityColor(item.rarity) }}
+                  style={{ color: item.rarityColor }}
                 >
                   {item.itemName}
                 </span>
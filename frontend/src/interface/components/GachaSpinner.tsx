@@ -1,6 +1,6 @@
 import React, { useState } from 'react';
 import { gachaUsecase } from '../../usecases/gachaUsecase';
-import { GachaResult, Rarity } from '../../domain/Gacha';
+import { GachaResult } from '../../domain/Gacha';
 
 interface GachaSpinnerProps {
   userId: number;
@@ -33,21 +33,6 @@ export const GachaSpinner: React.FC<GachaSpinnerProps> = ({ userId, onGachaCompl
     }
   };
 
-  const getRarityColor = (rarity: Rarity): string => {
-    switch (rarity) {
-      case Rarity.Common:
-        return '#808080';
-      case Rarity.Rare:
-        return '#0066ff';
-      case Rarity.Epic:
-        return '#8b00ff';
-      case Rarity.Legendary:
-        return '#ff6600';
-      default:
-        return '#000000';
-    }
-  };
-
   return (
     <div className="gacha-spinner">
       <div className="spinner-container">
@@ -59,7 +44,7 @@ export const GachaSpinner: React.FC<GachaSpinnerProps> = ({ userId, onGachaCompl
             </div>
           ) : result ? (
             <div className="result-content">
-              <h3 style={{ color: getRarityColor(result.rarity) }}>
+              <h3 style={{ color: result.rarityColor }}>
                 {result.itemName}
               </h3>
               <p className="rarity">{result.rarity}</p>
@@ -32,6 +32,20 @@ export const PointDisplay: React.FC<PointDisplayProps> = ({ userId, onBalanceUpd
     }
   }, [userId]);
 
+  // 残高の変更をサーバーからリアルタイムに受け取る
+  useEffect(() => {
+    if (userId <= 0) {
+      return;
+    }
+    return pointUsecase.subscribeBalance(userId, {
+      onBalanceChanged: (event) => {
+        setBalance(event.balance);
+        onBalanceUpdate?.(event.balance);
+      },
+      onReset: () => fetchBalance(),
+    });
+  }, [userId]);
+
   const refreshBalance = () => {
     fetchBalance();
   };
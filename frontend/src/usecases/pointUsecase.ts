@@ -1,4 +1,5 @@
 import { pointApi } from '../infrastructure/api/pointApi';
+import { streamApi, BalanceStreamHandlers } from '../infrastructure/api/streamApi';
 import { UserPoint, PointTransaction } from '../domain/Point';
 
 export class PointUsecase {
@@ -15,6 +16,14 @@ export class PointUsecase {
     }
     return pointApi.getTransactionHistory(userId, limit);
   }
+
+  // 購読を解除する関数を返す
+  subscribeBalance(userId: number, handlers: BalanceStreamHandlers): () => void {
+    if (userId <= 0) {
+      throw new Error('Invalid user ID');
+    }
+    return streamApi.subscribeBalance(userId, handlers);
+  }
 }
 
 export const pointUsecase = new PointUsecase();
\ No newline at end of file
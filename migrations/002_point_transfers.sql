@@ -0,0 +1,13 @@
+-- Link transfer_out / transfer_in transaction pairs
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.COLUMNS
+     WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'point_transactions' AND COLUMN_NAME = 'transfer_id') = 0,
+    'ALTER TABLE point_transactions
+        ADD COLUMN transfer_id VARCHAR(64) NULL AFTER description,
+        ADD INDEX idx_transfer_id (transfer_id),
+        ADD INDEX idx_user_type_created_at (user_id, type, created_at)',
+    'DO 0'
+);
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
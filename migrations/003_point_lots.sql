@@ -0,0 +1,21 @@
+-- Track earned points as lots so they can expire oldest-first
+CREATE TABLE IF NOT EXISTS point_lots (
+    id INT PRIMARY KEY AUTO_INCREMENT,
+    user_id INT NOT NULL,
+    transaction_id INT NULL,
+    amount INT NOT NULL,
+    remaining INT NOT NULL,
+    earned_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    INDEX idx_user_remaining_expires (user_id, remaining, expires_at),
+    INDEX idx_expires_at (expires_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (transaction_id) REFERENCES point_transactions(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Existing balances have no provenance; give them a single lot with a full expiry period
+INSERT INTO point_lots (user_id, transaction_id, amount, remaining, earned_at, expires_at)
+SELECT up.user_id, NULL, up.balance, up.balance, NOW(), DATE_ADD(NOW(), INTERVAL 365 DAY)
+FROM user_points up
+WHERE up.balance > 0
+  AND NOT EXISTS (SELECT 1 FROM point_lots pl WHERE pl.user_id = up.user_id);
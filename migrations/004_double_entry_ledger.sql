@@ -0,0 +1,44 @@
+-- Store point_transactions.amount signed from the user's point of view
+UPDATE point_transactions
+SET amount = -amount
+WHERE type IN ('spend', 'transfer_out', 'expire') AND amount > 0;
+
+-- Double-entry postings: every transaction has a user wallet leg and a
+-- system account leg that sum to zero
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id INT PRIMARY KEY AUTO_INCREMENT,
+    transaction_id INT NOT NULL,
+    account VARCHAR(64) NOT NULL,
+    amount INT NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_account (account),
+    INDEX idx_transaction_id (transaction_id),
+    FOREIGN KEY (transaction_id) REFERENCES point_transactions(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Backfill postings for existing transactions
+INSERT INTO ledger_entries (transaction_id, account, amount, created_at)
+SELECT pt.id, CONCAT('user:', pt.user_id), pt.amount, pt.created_at
+FROM point_transactions pt
+WHERE NOT EXISTS (SELECT 1 FROM ledger_entries le WHERE le.transaction_id = pt.id);
+
+INSERT INTO ledger_entries (transaction_id, account, amount, created_at)
+SELECT pt.id,
+       CASE pt.type
+           WHEN 'gacha' THEN 'system:gacha_house'
+           WHEN 'spend' THEN 'system:shop'
+           WHEN 'transfer_out' THEN 'system:transfer_clearing'
+           WHEN 'transfer_in' THEN 'system:transfer_clearing'
+           WHEN 'expire' THEN 'system:expired'
+           ELSE 'system:adjustments'
+       END,
+       -pt.amount,
+       pt.created_at
+FROM point_transactions pt
+WHERE (SELECT COUNT(*) FROM ledger_entries le WHERE le.transaction_id = pt.id) = 1;
+
+-- Balances derived from the ledger
+CREATE OR REPLACE VIEW account_balances AS
+SELECT account, SUM(amount) AS balance, COUNT(*) AS entries
+FROM ledger_entries
+GROUP BY account;
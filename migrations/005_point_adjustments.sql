@@ -0,0 +1,17 @@
+-- Manual balance changes made by support staff
+CREATE TABLE IF NOT EXISTS point_adjustments (
+    id INT PRIMARY KEY AUTO_INCREMENT,
+    transaction_id INT NOT NULL,
+    user_id INT NOT NULL,
+    amount INT NOT NULL,
+    reason_code VARCHAR(50) NOT NULL,
+    reason TEXT NOT NULL,
+    admin_id VARCHAR(100) NOT NULL,
+    limit_override BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_user_id (user_id),
+    INDEX idx_admin_id (admin_id),
+    INDEX idx_created_at (created_at),
+    FOREIGN KEY (transaction_id) REFERENCES point_transactions(id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
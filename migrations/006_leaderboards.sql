@@ -0,0 +1,37 @@
+-- Materialized leaderboard scores, updated incrementally on every gacha spin
+CREATE TABLE IF NOT EXISTS leaderboard_scores (
+    metric VARCHAR(32) NOT NULL,
+    period VARCHAR(16) NOT NULL,
+    period_start DATE NOT NULL,
+    user_id INT NOT NULL,
+    score BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (metric, period, period_start, user_id),
+    INDEX idx_ranking (metric, period, period_start, score),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Balance rankings read user_points directly
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.STATISTICS
+     WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'user_points' AND INDEX_NAME = 'idx_balance') = 0,
+    'CREATE INDEX idx_balance ON user_points (balance)',
+    'DO 0'
+);
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+-- Backfill all-time scores from existing results
+INSERT INTO leaderboard_scores (metric, period, period_start, user_id, score)
+SELECT 'gacha_points', 'all_time', '1970-01-01', user_id, SUM(points_earned)
+FROM gacha_results
+GROUP BY user_id
+ON DUPLICATE KEY UPDATE score = VALUES(score);
+
+INSERT INTO leaderboard_scores (metric, period, period_start, user_id, score)
+SELECT 'legendary_pulls', 'all_time', '1970-01-01', user_id, COUNT(*)
+FROM gacha_results
+WHERE rarity = 4
+GROUP BY user_id
+ON DUPLICATE KEY UPDATE score = VALUES(score);
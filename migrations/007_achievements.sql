@@ -0,0 +1,11 @@
+-- Achievements unlocked per user; rules are defined in code
+CREATE TABLE IF NOT EXISTS user_achievements (
+    id INT PRIMARY KEY AUTO_INCREMENT,
+    user_id INT NOT NULL,
+    code VARCHAR(64) NOT NULL,
+    unlocked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reward_transaction_id INT NULL,
+    UNIQUE KEY uk_user_code (user_id, code),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (reward_transaction_id) REFERENCES point_transactions(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
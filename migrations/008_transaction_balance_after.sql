@@ -0,0 +1,11 @@
+-- Running balance after each point transaction, carried by point events.
+-- Existing rows stay NULL since their historical balances are not known.
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.COLUMNS
+     WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'point_transactions' AND COLUMN_NAME = 'balance_after') = 0,
+    'ALTER TABLE point_transactions ADD COLUMN balance_after INT NULL AFTER transfer_id',
+    'DO 0'
+);
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,22 @@
+-- Domain events written in the same transaction as the change they describe
+CREATE TABLE IF NOT EXISTS outbox_events (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    event_name VARCHAR(64) NOT NULL,
+    payload JSON NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+    last_error TEXT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    delivered_at TIMESTAMP NULL,
+    INDEX idx_status_next_attempt (status, next_attempt_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Subscribers that are not naturally idempotent record each event they handle
+CREATE TABLE IF NOT EXISTS outbox_consumptions (
+    event_id BIGINT NOT NULL,
+    consumer VARCHAR(64) NOT NULL,
+    consumed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (event_id, consumer),
+    FOREIGN KEY (event_id) REFERENCES outbox_events(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
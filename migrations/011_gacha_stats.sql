@@ -0,0 +1,10 @@
+-- Lets the rate statistics count results per item from the index alone
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.STATISTICS
+     WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'gacha_results' AND INDEX_NAME = 'idx_item_id') = 0,
+    'ALTER TABLE gacha_results ADD INDEX idx_item_id (item_id)',
+    'DO 0'
+);
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
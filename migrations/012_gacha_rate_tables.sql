@@ -0,0 +1,9 @@
+-- Published versions of the gacha item pool for drop-rate disclosure
+CREATE TABLE IF NOT EXISTS gacha_rate_tables (
+    version INT PRIMARY KEY,
+    effective_from TIMESTAMP(3) NOT NULL,
+    items JSON NOT NULL,
+    pity_rules JSON NOT NULL,
+    checksum CHAR(64) NOT NULL,
+    INDEX idx_effective_from (effective_from)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
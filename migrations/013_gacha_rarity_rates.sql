@@ -0,0 +1,10 @@
+-- Rarity rates and featured items of two-stage pools; NULL for versions published before them
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.COLUMNS
+     WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'gacha_rate_tables' AND COLUMN_NAME = 'rarity_rates') = 0,
+    'ALTER TABLE gacha_rate_tables ADD COLUMN rarity_rates JSON NULL AFTER effective_from',
+    'DO 0'
+);
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,30 @@
+-- Rarity tiers as data. IDs are the values stored in gacha_results.rarity, so
+-- tiers are never deleted or renumbered; the foreign key keeps every
+-- historical result resolvable to a tier.
+CREATE TABLE IF NOT EXISTS rarity_tiers (
+    id INT PRIMARY KEY,
+    name VARCHAR(32) NOT NULL,
+    tier_rank INT NOT NULL,
+    color CHAR(7) NOT NULL,
+    min_points INT NOT NULL,
+    max_points INT NOT NULL,
+    UNIQUE KEY uk_name (name),
+    UNIQUE KEY uk_tier_rank (tier_rank)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT IGNORE INTO rarity_tiers (id, name, tier_rank, color, min_points, max_points) VALUES
+    (1, 'Common', 1, '#808080', 1, 50),
+    (2, 'Rare', 2, '#0066ff', 10, 200),
+    (3, 'Epic', 3, '#8b00ff', 100, 1000),
+    (4, 'Legendary', 4, '#ff6600', 500, 5000);
+
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.TABLE_CONSTRAINTS
+     WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'gacha_results' AND CONSTRAINT_NAME = 'fk_gacha_results_rarity') = 0,
+    'ALTER TABLE gacha_results
+        ADD CONSTRAINT fk_gacha_results_rarity FOREIGN KEY (rarity) REFERENCES rarity_tiers(id)',
+    'DO 0'
+);
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
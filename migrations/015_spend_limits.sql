@@ -0,0 +1,26 @@
+-- Self-imposed responsible-gaming limits. A limit of 0 means no limit; a
+-- raised limit waits in the pending columns until its delay has passed.
+CREATE TABLE IF NOT EXISTS spend_limits (
+    user_id INT PRIMARY KEY,
+    daily_spins INT NOT NULL DEFAULT 0,
+    pending_daily_spins INT NULL,
+    pending_daily_spins_at TIMESTAMP(3) NULL,
+    weekly_spend INT NOT NULL DEFAULT 0,
+    pending_weekly_spend INT NULL,
+    pending_weekly_spend_at TIMESTAMP(3) NULL,
+    cool_off_until TIMESTAMP(3) NULL,
+    self_excluded_until TIMESTAMP(3) NULL,
+    updated_at TIMESTAMP(3) NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- Lets the daily spin limit count a user's spins since midnight from the index
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.STATISTICS
+     WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'gacha_results' AND INDEX_NAME = 'idx_user_created_at') = 0,
+    'ALTER TABLE gacha_results ADD INDEX idx_user_created_at (user_id, created_at)',
+    'DO 0'
+);
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,14 @@
+-- Region and age verification used by the regional gacha policies.
+-- An empty region means unknown and gets the default policy.
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.COLUMNS
+     WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'users' AND COLUMN_NAME = 'region') = 0,
+    'ALTER TABLE users
+        ADD COLUMN region CHAR(2) NOT NULL DEFAULT '''' AFTER name,
+        ADD COLUMN birth_date DATE NULL AFTER region,
+        ADD COLUMN age_verified_at TIMESTAMP NULL AFTER birth_date',
+    'DO 0'
+);
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,44 @@
+-- Abuse detection: the client that created each account, frozen point
+-- accounts and the admin review queue.
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.COLUMNS
+     WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'users' AND COLUMN_NAME = 'signup_ip') = 0,
+    'ALTER TABLE users
+        ADD COLUMN signup_ip VARCHAR(45) NOT NULL DEFAULT '''' AFTER age_verified_at,
+        ADD COLUMN signup_user_agent VARCHAR(255) NOT NULL DEFAULT '''' AFTER signup_ip,
+        ADD INDEX idx_signup_ip (signup_ip, created_at),
+        ADD INDEX idx_signup_user_agent (signup_user_agent, created_at)',
+    'DO 0'
+);
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.COLUMNS
+     WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'user_points' AND COLUMN_NAME = 'frozen_at') = 0,
+    'ALTER TABLE user_points
+        ADD COLUMN frozen_at TIMESTAMP NULL AFTER balance,
+        ADD COLUMN frozen_reason VARCHAR(500) NOT NULL DEFAULT '''' AFTER frozen_at',
+    'DO 0'
+);
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+CREATE TABLE IF NOT EXISTS abuse_flags (
+    id INT PRIMARY KEY AUTO_INCREMENT,
+    user_id INT NOT NULL,
+    score INT NOT NULL,
+    reasons JSON NOT NULL,
+    throttled BOOLEAN NOT NULL DEFAULT FALSE,
+    status VARCHAR(20) NOT NULL,
+    reviewed_by VARCHAR(100) NOT NULL DEFAULT '',
+    review_note VARCHAR(500) NOT NULL DEFAULT '',
+    reviewed_at TIMESTAMP NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    INDEX idx_user_id (user_id),
+    INDEX idx_status_score (status, score),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,27 @@
+-- Account suspension by support. A frozen or banned account cannot spin,
+-- spend or transfer points until it is reactivated or status_until passes.
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.COLUMNS
+     WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'users' AND COLUMN_NAME = 'status') = 0,
+    'ALTER TABLE users
+        ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT ''active'' AFTER signup_user_agent,
+        ADD COLUMN status_reason VARCHAR(500) NOT NULL DEFAULT '''' AFTER status,
+        ADD COLUMN status_until TIMESTAMP NULL AFTER status_reason',
+    'DO 0'
+);
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+CREATE TABLE IF NOT EXISTS user_status_changes (
+    id INT PRIMARY KEY AUTO_INCREMENT,
+    user_id INT NOT NULL,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    reason VARCHAR(500) NOT NULL DEFAULT '',
+    until TIMESTAMP NULL,
+    admin_id VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    INDEX idx_user_created_at (user_id, created_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
@@ -0,0 +1,42 @@
+-- Append-only, hash-chained audit log of state-changing operations.
+-- Snapshots are TEXT rather than JSON so MySQL keeps the exact bytes that
+-- were hashed.
+CREATE TABLE IF NOT EXISTS audit_log (
+    seq BIGINT PRIMARY KEY,
+    actor VARCHAR(150) NOT NULL,
+    action VARCHAR(100) NOT NULL,
+    target_type VARCHAR(50) NOT NULL,
+    target_id VARCHAR(100) NOT NULL DEFAULT '',
+    before_state MEDIUMTEXT NULL,
+    after_state MEDIUMTEXT NULL,
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL,
+    prev_hash CHAR(64) NOT NULL,
+    hash CHAR(64) NOT NULL,
+    INDEX idx_actor (actor, seq),
+    INDEX idx_action (action, seq),
+    INDEX idx_target (target_type, target_id, seq),
+    INDEX idx_request_id (request_id),
+    INDEX idx_created_at (created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- The last entry of the chain. Appends lock this row so entries are chained
+-- one at a time.
+CREATE TABLE IF NOT EXISTS audit_chain_head (
+    id TINYINT PRIMARY KEY,
+    last_seq BIGINT NOT NULL,
+    last_hash CHAR(64) NOT NULL
+) ENGINE=InnoDB;
+
+INSERT IGNORE INTO audit_chain_head (id, last_seq, last_hash) VALUES (1, 0, '');
+
+-- Reject edits and deletes from the application's connection; the hash chain
+-- still reveals changes made by anyone able to drop these triggers.
+DROP TRIGGER IF EXISTS audit_log_no_update;
+CREATE TRIGGER audit_log_no_update BEFORE UPDATE ON audit_log
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only';
+
+DROP TRIGGER IF EXISTS audit_log_no_delete;
+CREATE TRIGGER audit_log_no_delete BEFORE DELETE ON audit_log
+    FOR EACH ROW SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'audit_log is append-only';
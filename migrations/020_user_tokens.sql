@@ -0,0 +1,12 @@
+-- Secret token issued to each user at signup. Only its SHA-256 is stored;
+-- requests that move a user's points out must present the token.
+-- Existing accounts keep an empty hash until an admin issues them a token.
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.COLUMNS
+     WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'users' AND COLUMN_NAME = 'token_hash') = 0,
+    'ALTER TABLE users ADD COLUMN token_hash CHAR(64) NOT NULL DEFAULT '''' AFTER status_until',
+    'DO 0'
+);
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
@@ -0,0 +1,11 @@
+-- Only a region verified by an admin decides the gacha policy. Regions
+-- users declared themselves at signup stay unverified.
+SET @ddl = IF(
+    (SELECT COUNT(*) FROM information_schema.COLUMNS
+     WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = 'users' AND COLUMN_NAME = 'region_verified_at') = 0,
+    'ALTER TABLE users ADD COLUMN region_verified_at TIMESTAMP NULL AFTER region',
+    'DO 0'
+);
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
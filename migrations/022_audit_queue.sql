@@ -0,0 +1,15 @@
+-- Audit entries wait here until the audit-chain job appends them to the hash
+-- chain, so recording a change does not lock audit_chain_head inside every
+-- state-changing transaction.
+CREATE TABLE IF NOT EXISTS audit_queue (
+    id BIGINT PRIMARY KEY AUTO_INCREMENT,
+    actor VARCHAR(150) NOT NULL,
+    action VARCHAR(100) NOT NULL,
+    target_type VARCHAR(50) NOT NULL,
+    target_id VARCHAR(100) NOT NULL DEFAULT '',
+    before_state MEDIUMTEXT NULL,
+    after_state MEDIUMTEXT NULL,
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    ip VARCHAR(45) NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
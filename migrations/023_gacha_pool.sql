@@ -0,0 +1,31 @@
+-- The gacha pool as data: the rate of each rarity and the items drawn within
+-- it. The server loads the pool at startup and publishes it as a rate table,
+-- so changing these rows and restarting changes the disclosed odds.
+CREATE TABLE IF NOT EXISTS gacha_pool_rates (
+    rarity INT PRIMARY KEY,
+    weight INT NOT NULL,
+    featured_share INT NOT NULL DEFAULT 0,
+    FOREIGN KEY (rarity) REFERENCES rarity_tiers(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+CREATE TABLE IF NOT EXISTS gacha_items (
+    id INT PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    rarity INT NOT NULL,
+    points INT NOT NULL,
+    featured BOOLEAN NOT NULL DEFAULT FALSE,
+    INDEX idx_rarity (rarity),
+    FOREIGN KEY (rarity) REFERENCES rarity_tiers(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+INSERT IGNORE INTO gacha_pool_rates (rarity, weight, featured_share) VALUES
+    (1, 600000, 0),
+    (2, 300000, 0),
+    (3, 80000, 0),
+    (4, 20000, 0);
+
+INSERT IGNORE INTO gacha_items (id, name, rarity, points, featured) VALUES
+    (1, 'Bronze Coin', 1, 10, FALSE),
+    (2, 'Silver Coin', 2, 50, FALSE),
+    (3, 'Gold Coin', 3, 200, FALSE),
+    (4, 'Diamond', 4, 1000, FALSE);